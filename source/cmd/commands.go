@@ -0,0 +1,170 @@
+package main
+
+import (
+	"TestProject/source/config"
+	"TestProject/source/internal/application"
+	"TestProject/source/internal/storage"
+	"TestProject/source/internal/storage/migrations"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+)
+
+const commandTimeout = 30 * time.Minute
+
+// runCommand runs a one-off subcommand and returns the process exit code.
+func runCommand(name string, args []string) int {
+	switch name {
+	case "reconcile":
+		return runReconcile()
+	case "migrate":
+		return runMigrate(args)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\nusage: %s [reconcile | migrate up|down|status]\n", name, os.Args[0])
+		return 2
+	}
+}
+
+// runReconcile reconciles all wallets once, prints the report as JSON and
+// exits with 1 when any wallet has drifted.
+func runReconcile() int {
+	var app *application.Application
+	var db *gorm.DB
+
+	// The app is built but not started, so neither the HTTP server nor the
+	// periodic jobs run. Its stop hooks do not run either, so the database
+	// is closed here.
+	fxApp := fx.New(fx.NopLogger, CreateCoreApp(), fx.Populate(&app, &db))
+	if err := fxApp.Err(); err != nil {
+		fmt.Fprintf(os.Stderr, "error starting: %v\n", err)
+		return 2
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error connecting: %v\n", err)
+		return 2
+	}
+	defer sqlDB.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	report, err := app.Reconcile(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error reconciling: %v\n", err)
+		return 2
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		fmt.Fprintf(os.Stderr, "error writing report: %v\n", err)
+		return 2
+	}
+
+	if !report.Consistent() {
+		return 1
+	}
+	return 0
+}
+
+// runMigrate applies, reverts or lists schema migrations regardless of
+// DB_MIGRATE. up applies all pending migrations, down reverts the latest
+// one, and status exits with 1 while migrations are pending.
+func runMigrate(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintf(os.Stderr, "usage: %s migrate up|down|status\n", os.Args[0])
+		return 2
+	}
+
+	// The schema is not checked on connect, so that a database behind the
+	// binary can be migrated.
+	db, err := storage.OpenDatabase(config.NewDBConfig(), NewLogger())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error connecting: %v\n", err)
+		return 2
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error connecting: %v\n", err)
+		return 2
+	}
+	defer sqlDB.Close()
+
+	migrator, err := migrations.New(sqlDB)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error loading migrations: %v\n", err)
+		return 2
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, migration := range applied {
+			fmt.Printf("applied %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error migrating: %v\n", err)
+			return 2
+		}
+		if len(applied) == 0 {
+			fmt.Printf("schema is up to date at version %d\n", migrator.Latest())
+		}
+		return 0
+	case "down":
+		reverted, err := migrator.Down(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error reverting: %v\n", err)
+			return 2
+		}
+		fmt.Printf("reverted %04d_%s\n", reverted.Version, reverted.Name)
+		return 0
+	case "status":
+		return printMigrationStatus(ctx, migrator)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown migrate command %q\nusage: %s migrate up|down|status\n", args[0], os.Args[0])
+		return 2
+	}
+}
+
+func printMigrationStatus(ctx context.Context, migrator *migrations.Migrator) int {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error reading status: %v\n", err)
+		return 2
+	}
+
+	pending := false
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS")
+	for _, status := range statuses {
+		state := "pending"
+		switch {
+		case status.Unknown:
+			state = "applied " + status.AppliedAt.Format(time.RFC3339) + " (unknown to this binary)"
+		case status.AppliedAt != nil:
+			state = "applied " + status.AppliedAt.Format(time.RFC3339)
+		default:
+			pending = true
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\n", status.Version, status.Name, state)
+	}
+	if err := w.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "error writing status: %v\n", err)
+		return 2
+	}
+
+	if pending {
+		return 1
+	}
+	return 0
+}
@@ -3,8 +3,11 @@ package main
 import (
 	"TestProject/source/config"
 	"TestProject/source/internal/application"
+	"TestProject/source/internal/publisher"
 	"TestProject/source/internal/storage"
 	"TestProject/source/internal/transport"
+	"TestProject/source/internal/transport/grpcapi"
+	"TestProject/source/internal/webhook"
 	"log/slog"
 	"os"
 
@@ -12,9 +15,14 @@ import (
 	"github.com/robbert229/fxslog"
 	"go.uber.org/fx"
 	"go.uber.org/fx/fxevent"
+	"google.golang.org/grpc"
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(os.Args[1], os.Args[2:]))
+	}
+
 	fx.New(fx.WithLogger(func(log *slog.Logger) fxevent.Logger {
 		return &fxslog.SlogLogger{Logger: log}
 	}), CreateApp()).Run()
@@ -28,17 +36,42 @@ func NewLogger() *slog.Logger {
 
 func CreateApp() fx.Option {
 	return fx.Options(
-		application.Module,
+		CreateCoreApp(),
 		transport.Module,
-		storage.Module,
+		grpcapi.Module,
 
 		fx.Provide(
-			NewLogger,
-			config.NewDBConfig,
 			config.NewHttpConfig,
+			config.NewGrpcConfig,
 		),
 		fx.Invoke(
 			func(echo *echo.Echo) {},
+			func(*grpc.Server) {},
+		),
+	)
+}
+
+// CreateCoreApp is the application without the HTTP and gRPC servers. Subcommands run
+// on top of it.
+func CreateCoreApp() fx.Option {
+	return fx.Options(
+		application.Module,
+		storage.Module,
+		publisher.Module,
+		webhook.Module,
+
+		fx.Provide(
+			NewLogger,
+			config.NewDBConfig,
+			config.NewIdempotencyConfig,
+			config.NewHoldConfig,
+			config.NewReconciliationConfig,
+			config.NewLimitsConfig,
+			config.NewScheduleConfig,
+			config.NewSnapshotConfig,
+			config.NewInterestConfig,
+			config.NewOutboxConfig,
+			config.NewWebhookConfig,
 		),
 		fx.Invoke(
 			func(*application.Application) {},
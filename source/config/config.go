@@ -4,8 +4,10 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/shopspring/decimal"
 )
 
 type DBConfig struct {
@@ -22,6 +24,72 @@ type HttpConfig struct {
 	Port string `env:"HTTP_PORT" env-default:"8080"`
 }
 
+type GrpcConfig struct {
+	Port string `env:"GRPC_PORT" env-default:"9090"`
+}
+
+type IdempotencyConfig struct {
+	KeyTTL time.Duration `env:"IDEMPOTENCY_KEY_TTL" env-default:"24h"`
+}
+
+type HoldConfig struct {
+	DefaultTTL     time.Duration `env:"HOLD_DEFAULT_TTL" env-default:"168h"`
+	ExpiryInterval time.Duration `env:"HOLD_EXPIRY_INTERVAL" env-default:"1m"`
+}
+
+type ReconciliationConfig struct {
+	Interval time.Duration `env:"RECONCILIATION_INTERVAL" env-default:"1h"`
+}
+
+// SnapshotConfig sets how often daily balance snapshots are taken; zero
+// disables the job.
+type SnapshotConfig struct {
+	Interval time.Duration `env:"SNAPSHOT_INTERVAL" env-default:"1h"`
+}
+
+// InterestConfig sets how often interest is accrued and paid out; zero
+// disables the job.
+type InterestConfig struct {
+	Interval time.Duration `env:"INTEREST_INTERVAL" env-default:"1h"`
+}
+
+// OutboxConfig sets how often the relay publishes wallet events from the
+// outbox (zero disables it) and how long published events are kept.
+// Publisher is "none" (the default, events are only marked published),
+// "stdout" or "file"; the file publisher appends JSON lines to File.
+type OutboxConfig struct {
+	RelayInterval time.Duration `env:"OUTBOX_RELAY_INTERVAL" env-default:"1s"`
+	Retention     time.Duration `env:"OUTBOX_RETENTION" env-default:"168h"`
+	Publisher     string        `env:"OUTBOX_PUBLISHER" env-default:"none"`
+	File          string        `env:"OUTBOX_FILE" env-default:"wallet-events.ndjson"`
+}
+
+// WebhookConfig.MaxAttempts is how many times a delivery is tried before it
+// is moved to the dead-letter list; the wait between attempts starts at
+// Backoff and doubles each time. AllowPrivateNetworks lets webhooks target
+// loopback and private addresses, for local runs only.
+type WebhookConfig struct {
+	PollInterval         time.Duration `env:"WEBHOOK_POLL_INTERVAL" env-default:"1s"`
+	Timeout              time.Duration `env:"WEBHOOK_TIMEOUT" env-default:"10s"`
+	MaxAttempts          int64         `env:"WEBHOOK_MAX_ATTEMPTS" env-default:"10"`
+	Backoff              time.Duration `env:"WEBHOOK_BACKOFF" env-default:"30s"`
+	AllowPrivateNetworks bool          `env:"WEBHOOK_ALLOW_PRIVATE_NETWORKS" env-default:"false"`
+}
+
+// LimitsConfig holds the default velocity limits for wallets without their
+// own. Amounts are in the wallet currency; zero means no limit.
+type LimitsConfig struct {
+	PerOperation  decimal.Decimal `env:"LIMIT_PER_OPERATION" env-default:"0"`
+	DailyAmount   decimal.Decimal `env:"LIMIT_DAILY_AMOUNT" env-default:"0"`
+	DailyCount    int64           `env:"LIMIT_DAILY_COUNT" env-default:"0"`
+	MonthlyAmount decimal.Decimal `env:"LIMIT_MONTHLY_AMOUNT" env-default:"0"`
+	MonthlyCount  int64           `env:"LIMIT_MONTHLY_COUNT" env-default:"0"`
+}
+
+type ScheduleConfig struct {
+	PollInterval time.Duration `env:"SCHEDULE_POLL_INTERVAL" env-default:"10s"`
+}
+
 func LoadEnv() error {
 	err := godotenv.Load("config.env")
 	if err != nil {
@@ -52,6 +120,98 @@ func NewHttpConfig() HttpConfig {
 	}
 }
 
+func NewGrpcConfig() GrpcConfig {
+	LoadEnv()
+
+	return GrpcConfig{
+		Port: getEnv("GRPC_PORT", "9090"),
+	}
+}
+
+func NewIdempotencyConfig() IdempotencyConfig {
+	LoadEnv()
+
+	return IdempotencyConfig{
+		KeyTTL: getDurationEnv("IDEMPOTENCY_KEY_TTL", 24*time.Hour),
+	}
+}
+
+func NewHoldConfig() HoldConfig {
+	LoadEnv()
+
+	return HoldConfig{
+		DefaultTTL:     getDurationEnv("HOLD_DEFAULT_TTL", 168*time.Hour),
+		ExpiryInterval: getDurationEnv("HOLD_EXPIRY_INTERVAL", time.Minute),
+	}
+}
+
+func NewReconciliationConfig() ReconciliationConfig {
+	LoadEnv()
+
+	return ReconciliationConfig{
+		Interval: getDurationEnv("RECONCILIATION_INTERVAL", time.Hour),
+	}
+}
+
+func NewSnapshotConfig() SnapshotConfig {
+	LoadEnv()
+
+	return SnapshotConfig{
+		Interval: getDurationEnv("SNAPSHOT_INTERVAL", time.Hour),
+	}
+}
+
+func NewInterestConfig() InterestConfig {
+	LoadEnv()
+
+	return InterestConfig{
+		Interval: getDurationEnv("INTEREST_INTERVAL", time.Hour),
+	}
+}
+
+func NewOutboxConfig() OutboxConfig {
+	LoadEnv()
+
+	return OutboxConfig{
+		RelayInterval: getDurationEnv("OUTBOX_RELAY_INTERVAL", time.Second),
+		Retention:     getDurationEnv("OUTBOX_RETENTION", 168*time.Hour),
+		Publisher:     getEnv("OUTBOX_PUBLISHER", "none"),
+		File:          getEnv("OUTBOX_FILE", "wallet-events.ndjson"),
+	}
+}
+
+func NewWebhookConfig() WebhookConfig {
+	LoadEnv()
+
+	return WebhookConfig{
+		PollInterval:         getDurationEnv("WEBHOOK_POLL_INTERVAL", time.Second),
+		Timeout:              getDurationEnv("WEBHOOK_TIMEOUT", 10*time.Second),
+		MaxAttempts:          getIntEnv("WEBHOOK_MAX_ATTEMPTS", 10),
+		Backoff:              getDurationEnv("WEBHOOK_BACKOFF", 30*time.Second),
+		AllowPrivateNetworks: getBoolEnv("WEBHOOK_ALLOW_PRIVATE_NETWORKS", false),
+	}
+}
+
+func NewLimitsConfig() LimitsConfig {
+	LoadEnv()
+
+	return LimitsConfig{
+		PerOperation:  getDecimalEnv("LIMIT_PER_OPERATION", decimal.Zero),
+		DailyAmount:   getDecimalEnv("LIMIT_DAILY_AMOUNT", decimal.Zero),
+		DailyCount:    getIntEnv("LIMIT_DAILY_COUNT", 0),
+		MonthlyAmount: getDecimalEnv("LIMIT_MONTHLY_AMOUNT", decimal.Zero),
+		MonthlyCount:  getIntEnv("LIMIT_MONTHLY_COUNT", 0),
+	}
+}
+
+func NewScheduleConfig() ScheduleConfig {
+	LoadEnv()
+
+	return ScheduleConfig{
+		PollInterval: getDurationEnv("SCHEDULE_POLL_INTERVAL", 10*time.Second),
+	}
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -67,3 +227,30 @@ func getBoolEnv(key string, defaultValue bool) bool {
 	}
 	return defaultValue
 }
+
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+func getIntEnv(key string, defaultValue int64) int64 {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+func getDecimalEnv(key string, defaultValue decimal.Decimal) decimal.Decimal {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := decimal.NewFromString(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
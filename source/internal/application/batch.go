@@ -0,0 +1,80 @@
+package application
+
+import (
+	"TestProject/source/internal/entities"
+	"context"
+	"fmt"
+)
+
+// ProcessBatch runs DEPOSIT and WITHDRAW operations across many wallets.
+//
+// In ALL_OR_NOTHING mode the operations run in one database transaction;
+// the first failure rolls the whole batch back and is returned as a
+// BatchItemError. In BEST_EFFORT mode each operation is committed on its
+// own and its failure is reported in its BatchResult.
+//
+// A transaction that spans several operations keeps every wallet lock until
+// it commits, so the wallets of the batch are locked up front in ascending
+// ID order. This also covers a BEST_EFFORT batch that joins the transaction
+// of an idempotent request. There each operation runs in its own savepoint,
+// so that a failed one, including a failed write of its ledger or journal
+// entry, is rolled back alone and does not abort the operations after it.
+func (a *Application) ProcessBatch(ctx context.Context, mode string, transactions []entities.Transaction) ([]entities.BatchResult, error) {
+	if len(transactions) == 0 {
+		return nil, fmt.Errorf("%w: no operations", entities.ErrInvalidBatch)
+	}
+
+	walletIDs := make([]string, 0, len(transactions))
+	for _, transaction := range transactions {
+		walletIDs = append(walletIDs, transaction.WalletId)
+	}
+	results := make([]entities.BatchResult, len(transactions))
+
+	switch mode {
+	case entities.BatchAllOrNothing:
+		err := a.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			err := a.WalletRepo.LockMany(ctx, walletIDs)
+			if err != nil {
+				return fmt.Errorf("error locking wallets: %w", err)
+			}
+
+			for i, transaction := range transactions {
+				transaction, err = a.ProcessTransaction(ctx, transaction)
+				if err != nil {
+					return &entities.BatchItemError{Index: i, Err: err}
+				}
+				results[i] = entities.BatchResult{Index: i, Transaction: transaction}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error processing batch: %w", err)
+		}
+	case entities.BatchBestEffort:
+		// Outside a transaction every operation commits and releases its
+		// lock on its own, so there is nothing to lock up front.
+		if a.Transactor.InTransaction(ctx) {
+			err := a.WalletRepo.LockMany(ctx, walletIDs)
+			if err != nil {
+				return nil, fmt.Errorf("error locking wallets: %w", err)
+			}
+		}
+
+		for i, transaction := range transactions {
+			result := transaction
+			err := a.Transactor.WithinSavepoint(ctx, func(ctx context.Context) error {
+				processed, err := a.ProcessTransaction(ctx, transaction)
+				if err != nil {
+					return err
+				}
+				result = processed
+				return nil
+			})
+			results[i] = entities.BatchResult{Index: i, Transaction: result, Err: err}
+		}
+	default:
+		return nil, fmt.Errorf("%w: mode %s", entities.ErrInvalidBatch, mode)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,142 @@
+package application
+
+import (
+	"TestProject/source/internal/entities"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestApplication_ProcessBatch_BestEffort(t *testing.T) {
+	repo := newMockWalletRepo()
+	app, _ := newTestApplication(repo)
+	ctx := context.Background()
+
+	first := entities.NewWallet()
+	first.Balance = decimal.NewFromFloat(100.0)
+	repo.wallets[first.ID] = first
+	second := entities.NewWallet()
+	repo.wallets[second.ID] = second
+
+	results, err := app.ProcessBatch(ctx, entities.BatchBestEffort, []entities.Transaction{
+		{WalletId: first.ID, OperationType: "WITHDRAW", Amount: decimal.NewFromFloat(30.0)},
+		{WalletId: second.ID, OperationType: "WITHDRAW", Amount: decimal.NewFromFloat(10.0)},
+		{WalletId: second.ID, OperationType: "DEPOSIT", Amount: decimal.NewFromFloat(50.0)},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	if results[0].Err != nil || results[2].Err != nil {
+		t.Errorf("expected operations 0 and 2 to succeed, got %v and %v", results[0].Err, results[2].Err)
+	}
+	if !errors.Is(results[1].Err, entities.ErrInsufficientFunds) || results[1].Index != 1 {
+		t.Errorf("expected operation 1 to fail with ErrInsufficientFunds, got %+v", results[1])
+	}
+	if !repo.wallets[first.ID].Balance.Equal(decimal.NewFromFloat(70.0)) {
+		t.Errorf("expected balance 70.0, got %s", repo.wallets[first.ID].Balance.String())
+	}
+	if !repo.wallets[second.ID].Balance.Equal(decimal.NewFromFloat(50.0)) {
+		t.Errorf("expected balance 50.0, got %s", repo.wallets[second.ID].Balance.String())
+	}
+}
+
+// txTransactor behaves as if ctx were bound to a transaction and counts
+// the savepoints taken in it.
+type txTransactor struct {
+	savepoints int
+}
+
+func (t *txTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (t *txTransactor) WithinSavepoint(ctx context.Context, fn func(ctx context.Context) error) error {
+	t.savepoints++
+	return fn(ctx)
+}
+
+func (t *txTransactor) InTransaction(ctx context.Context) bool {
+	return true
+}
+
+func TestApplication_ProcessBatch_BestEffortSavepoints(t *testing.T) {
+	repo := newMockWalletRepo()
+	app, _ := newTestApplication(repo)
+	ctx := context.Background()
+
+	wallet := entities.NewWallet()
+	repo.wallets[wallet.ID] = wallet
+	batch := []entities.Transaction{
+		{WalletId: wallet.ID, OperationType: "DEPOSIT", Amount: decimal.NewFromFloat(10.0)},
+		{WalletId: wallet.ID, OperationType: "WITHDRAW", Amount: decimal.NewFromFloat(50.0)},
+	}
+
+	// Committed one by one, nothing is locked up front.
+	_, err := app.ProcessBatch(ctx, entities.BatchBestEffort, batch)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.lockManyCalls != 0 {
+		t.Errorf("expected no up-front lock outside a transaction, got %d", repo.lockManyCalls)
+	}
+
+	// Inside a transaction every operation gets its own savepoint.
+	transactor := &txTransactor{}
+	app.Transactor = transactor
+	results, err := app.ProcessBatch(ctx, entities.BatchBestEffort, batch)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.lockManyCalls != 1 {
+		t.Errorf("expected the wallets to be locked once, got %d", repo.lockManyCalls)
+	}
+	if transactor.savepoints != len(batch) {
+		t.Errorf("expected %d savepoints, got %d", len(batch), transactor.savepoints)
+	}
+	if results[0].Err != nil || !errors.Is(results[1].Err, entities.ErrInsufficientFunds) {
+		t.Errorf("expected the deposit to succeed and the withdrawal to fail, got %v and %v", results[0].Err, results[1].Err)
+	}
+}
+
+func TestApplication_ProcessBatch_AllOrNothingReportsFailedOperation(t *testing.T) {
+	repo := newMockWalletRepo()
+	app, _ := newTestApplication(repo)
+	ctx := context.Background()
+
+	wallet := entities.NewWallet()
+	wallet.Balance = decimal.NewFromFloat(100.0)
+	repo.wallets[wallet.ID] = wallet
+
+	_, err := app.ProcessBatch(ctx, entities.BatchAllOrNothing, []entities.Transaction{
+		{WalletId: wallet.ID, OperationType: "DEPOSIT", Amount: decimal.NewFromFloat(10.0)},
+		{WalletId: "missing-wallet", OperationType: "DEPOSIT", Amount: decimal.NewFromFloat(10.0)},
+	})
+
+	var itemErr *entities.BatchItemError
+	if !errors.As(err, &itemErr) || itemErr.Index != 1 {
+		t.Fatalf("expected BatchItemError for operation 1, got %v", err)
+	}
+	if !errors.Is(err, entities.ErrWalletNotFound) {
+		t.Errorf("expected ErrWalletNotFound, got %v", err)
+	}
+}
+
+func TestApplication_ProcessBatch_Invalid(t *testing.T) {
+	app, _ := newTestApplication(newMockWalletRepo())
+
+	_, err := app.ProcessBatch(context.Background(), entities.BatchAllOrNothing, nil)
+	if !errors.Is(err, entities.ErrInvalidBatch) {
+		t.Errorf("expected ErrInvalidBatch for an empty batch, got %v", err)
+	}
+
+	_, err = app.ProcessBatch(context.Background(), "SOMETIMES", []entities.Transaction{{}})
+	if !errors.Is(err, entities.ErrInvalidBatch) {
+		t.Errorf("expected ErrInvalidBatch for an unknown mode, got %v", err)
+	}
+}
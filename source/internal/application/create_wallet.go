@@ -0,0 +1,59 @@
+package application
+
+import (
+	"TestProject/source/internal/entities"
+	"context"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// CreateWallet creates an empty wallet and posts the initial balance, if
+// any, as a deposit so that it is backed by a journal entry. The opening
+// deposit is never charged a fee.
+func (a *Application) CreateWallet(ctx context.Context, wallet entities.Wallet) (entities.Wallet, error) {
+	if wallet.Currency == "" {
+		wallet.Currency = entities.DefaultCurrency
+	}
+
+	err := entities.ValidateAmount(wallet.Balance, wallet.Currency)
+	if err != nil {
+		return entities.Wallet{}, err
+	}
+
+	initial := wallet.Balance
+	wallet.Balance = decimal.Zero
+
+	err = a.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if wallet.OwnerId != "" {
+			_, err := a.CustomerRepo.GetByID(ctx, wallet.OwnerId)
+			if err != nil {
+				return fmt.Errorf("error getting owner: %w", err)
+			}
+		}
+
+		wallet, err = a.WalletRepo.Create(ctx, wallet)
+		if err != nil {
+			return fmt.Errorf("error creating wallet: %w", err)
+		}
+		if !initial.IsPositive() {
+			return nil
+		}
+
+		transaction, err := a.processTransaction(ctx, entities.Transaction{
+			WalletId:      wallet.ID,
+			OperationType: deposit,
+			Amount:        initial,
+		}, false)
+		if err != nil {
+			return fmt.Errorf("error posting initial balance: %w", err)
+		}
+		wallet.Balance = transaction.Balance
+		return nil
+	})
+	if err != nil {
+		return entities.Wallet{}, err
+	}
+
+	return wallet, nil
+}
@@ -0,0 +1,88 @@
+package application
+
+import (
+	"TestProject/source/internal/entities"
+	"context"
+	"fmt"
+	"time"
+)
+
+func (a *Application) CreateCustomer(ctx context.Context, customer entities.Customer) (entities.Customer, error) {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	customer.CreatedAt = now
+	customer.UpdatedAt = now
+
+	customer, err := a.CustomerRepo.Create(ctx, customer)
+	if err != nil {
+		return entities.Customer{}, fmt.Errorf("error creating customer: %w", err)
+	}
+	return customer, nil
+}
+
+func (a *Application) GetCustomer(ctx context.Context, customerID string) (entities.Customer, error) {
+	customer, err := a.CustomerRepo.GetByID(ctx, customerID)
+	if err != nil {
+		return entities.Customer{}, fmt.Errorf("error getting customer: %w", err)
+	}
+	return customer, nil
+}
+
+// ListCustomers returns up to limit customers in ID order, starting after
+// afterID.
+func (a *Application) ListCustomers(ctx context.Context, afterID string, limit int) ([]entities.Customer, error) {
+	customers, err := a.CustomerRepo.List(ctx, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error listing customers: %w", err)
+	}
+	return customers, nil
+}
+
+// UpdateCustomer replaces the customer's name and contacts.
+func (a *Application) UpdateCustomer(ctx context.Context, customer entities.Customer) (entities.Customer, error) {
+	customer.UpdatedAt = time.Now().UTC().Truncate(time.Microsecond)
+
+	customer, err := a.CustomerRepo.Update(ctx, customer)
+	if err != nil {
+		return entities.Customer{}, fmt.Errorf("error updating customer: %w", err)
+	}
+	return customer, nil
+}
+
+// DeleteCustomer deletes a customer that owns no wallets. Wallets keep
+// their history, so they are never deleted along with their owner.
+func (a *Application) DeleteCustomer(ctx context.Context, customerID string) error {
+	err := a.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		wallets, err := a.WalletRepo.ListByOwner(ctx, customerID)
+		if err != nil {
+			return fmt.Errorf("error listing wallets: %w", err)
+		}
+		if len(wallets) > 0 {
+			return entities.ErrCustomerHasWallets
+		}
+		return a.CustomerRepo.Delete(ctx, customerID)
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting customer: %w", err)
+	}
+	return nil
+}
+
+// GetCustomerWallets returns the customer with its wallets and their total
+// balance per currency.
+func (a *Application) GetCustomerWallets(ctx context.Context, customerID string) (entities.CustomerWallets, error) {
+	customer, err := a.CustomerRepo.GetByID(ctx, customerID)
+	if err != nil {
+		return entities.CustomerWallets{}, fmt.Errorf("error getting customer: %w", err)
+	}
+
+	wallets, err := a.WalletRepo.ListByOwner(ctx, customerID)
+	if err != nil {
+		return entities.CustomerWallets{}, fmt.Errorf("error listing wallets: %w", err)
+	}
+
+	return entities.CustomerWallets{
+		Customer: customer,
+		Wallets:  wallets,
+		Totals:   entities.TotalsByCurrency(wallets),
+	}, nil
+}
@@ -0,0 +1,216 @@
+package application
+
+import (
+	"TestProject/source/internal/entities"
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+)
+
+type mockCustomerRepo struct {
+	mu        sync.Mutex
+	customers map[string]entities.Customer
+}
+
+func newMockCustomerRepo() *mockCustomerRepo {
+	return &mockCustomerRepo{customers: make(map[string]entities.Customer)}
+}
+
+func (m *mockCustomerRepo) Create(ctx context.Context, customer entities.Customer) (entities.Customer, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, existing := range m.customers {
+		if customer.Email != "" && existing.Email == customer.Email {
+			return entities.Customer{}, entities.ErrCustomerExists
+		}
+	}
+	m.customers[customer.ID] = customer
+	return customer, nil
+}
+
+func (m *mockCustomerRepo) GetByID(ctx context.Context, customerID string) (entities.Customer, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	customer, ok := m.customers[customerID]
+	if !ok {
+		return entities.Customer{}, entities.ErrCustomerNotFound
+	}
+	return customer, nil
+}
+
+func (m *mockCustomerRepo) List(ctx context.Context, afterID string, limit int) ([]entities.Customer, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var result []entities.Customer
+	for id, customer := range m.customers {
+		if id > afterID {
+			result = append(result, customer)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (m *mockCustomerRepo) Update(ctx context.Context, customer entities.Customer) (entities.Customer, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	existing, ok := m.customers[customer.ID]
+	if !ok {
+		return entities.Customer{}, entities.ErrCustomerNotFound
+	}
+	customer.CreatedAt = existing.CreatedAt
+	m.customers[customer.ID] = customer
+	return customer, nil
+}
+
+func (m *mockCustomerRepo) Delete(ctx context.Context, customerID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.customers[customerID]; !ok {
+		return entities.ErrCustomerNotFound
+	}
+	delete(m.customers, customerID)
+	return nil
+}
+
+func TestApplication_CreateWallet_Owner(t *testing.T) {
+	repo := newMockWalletRepo()
+	app, _ := newTestApplication(repo)
+	ctx := context.Background()
+
+	wallet := entities.NewWallet()
+	wallet.OwnerId = entities.NewCustomer().ID
+	_, err := app.CreateWallet(ctx, wallet)
+	if !errors.Is(err, entities.ErrCustomerNotFound) {
+		t.Fatalf("expected ErrCustomerNotFound, got %v", err)
+	}
+	if _, ok := repo.wallets[wallet.ID]; ok {
+		t.Error("expected no wallet for an unknown owner")
+	}
+
+	customer := entities.NewCustomer()
+	customer.Name = "Alice"
+	customer, err = app.CreateCustomer(ctx, customer)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wallet.OwnerId = customer.ID
+	created, err := app.CreateWallet(ctx, wallet)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.OwnerId != customer.ID {
+		t.Errorf("expected owner %s, got %s", customer.ID, created.OwnerId)
+	}
+}
+
+func TestApplication_GetCustomerWallets(t *testing.T) {
+	repo := newMockWalletRepo()
+	app, _ := newTestApplication(repo)
+	ctx := context.Background()
+
+	customer, err := app.CreateCustomer(ctx, entities.Customer{ID: entities.NewCustomer().ID, Name: "Alice"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wallets := []struct {
+		currency string
+		balance  string
+		reserved string
+	}{
+		{"RUB", "100.50", "0"},
+		{"RUB", "20.25", "10"},
+		{"USD", "7", "0"},
+	}
+	for _, w := range wallets {
+		wallet := entities.NewWallet()
+		wallet.OwnerId = customer.ID
+		wallet.Currency = w.currency
+		wallet.Balance = dec(w.balance)
+		wallet.Reserved = dec(w.reserved)
+		repo.wallets[wallet.ID] = wallet
+	}
+	repo.wallets[entities.NewWallet().ID] = entities.NewWallet()
+
+	result, err := app.GetCustomerWallets(ctx, customer.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Wallets) != 3 {
+		t.Fatalf("expected 3 wallets, got %d", len(result.Wallets))
+	}
+
+	totals := make(map[string]entities.CurrencyTotal)
+	for _, total := range result.Totals {
+		totals[total.Currency] = total
+	}
+	if len(totals) != 2 {
+		t.Fatalf("expected totals in 2 currencies, got %v", result.Totals)
+	}
+	if !totals["RUB"].Balance.Equal(dec("120.75")) || !totals["RUB"].Available.Equal(dec("110.75")) {
+		t.Errorf("unexpected RUB total: %+v", totals["RUB"])
+	}
+	if !totals["USD"].Balance.Equal(dec("7")) {
+		t.Errorf("unexpected USD total: %+v", totals["USD"])
+	}
+
+	_, err = app.GetCustomerWallets(ctx, entities.NewCustomer().ID)
+	if !errors.Is(err, entities.ErrCustomerNotFound) {
+		t.Errorf("expected ErrCustomerNotFound, got %v", err)
+	}
+}
+
+func TestApplication_DeleteCustomer(t *testing.T) {
+	repo := newMockWalletRepo()
+	app, _ := newTestApplication(repo)
+	ctx := context.Background()
+
+	customer, err := app.CreateCustomer(ctx, entities.Customer{ID: entities.NewCustomer().ID, Name: "Alice"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wallet := entities.NewWallet()
+	wallet.OwnerId = customer.ID
+	repo.wallets[wallet.ID] = wallet
+
+	err = app.DeleteCustomer(ctx, customer.ID)
+	if !errors.Is(err, entities.ErrCustomerHasWallets) {
+		t.Fatalf("expected ErrCustomerHasWallets, got %v", err)
+	}
+
+	delete(repo.wallets, wallet.ID)
+	err = app.DeleteCustomer(ctx, customer.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = app.GetCustomer(ctx, customer.ID)
+	if !errors.Is(err, entities.ErrCustomerNotFound) {
+		t.Errorf("expected ErrCustomerNotFound, got %v", err)
+	}
+}
+
+func TestApplication_CreateCustomer_DuplicateEmail(t *testing.T) {
+	app, _ := newTestApplication(newMockWalletRepo())
+	ctx := context.Background()
+
+	_, err := app.CreateCustomer(ctx, entities.Customer{ID: entities.NewCustomer().ID, Name: "Alice", Email: "alice@example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = app.CreateCustomer(ctx, entities.Customer{ID: entities.NewCustomer().ID, Name: "Bob", Email: "alice@example.com"})
+	if !errors.Is(err, entities.ErrCustomerExists) {
+		t.Errorf("expected ErrCustomerExists, got %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package application
+
+import (
+	"TestProject/source/internal/entities"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// feeOperations are the operation types that can carry a fee.
+var feeOperations = map[string]bool{deposit: true, withdraw: true}
+
+// transactionFee prices the transaction with the wallet's rule or the
+// default one. Operations without a rule are free.
+func (a *Application) transactionFee(ctx context.Context, transaction entities.Transaction) (decimal.Decimal, error) {
+	rule, err := a.FeeRepo.Effective(ctx, transaction.WalletId, transaction.OperationType, transaction.Currency)
+	if errors.Is(err, entities.ErrFeeRuleNotFound) {
+		return decimal.Zero, nil
+	}
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("error getting fee rule: %w", err)
+	}
+	return rule.Calculate(transaction.Amount)
+}
+
+// ListFeeRules returns the wallet's own rules, or the default rules when
+// walletID is empty.
+func (a *Application) ListFeeRules(ctx context.Context, walletID string) ([]entities.FeeRule, error) {
+	if walletID != "" {
+		_, err := a.WalletRepo.GetByID(ctx, walletID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rules, err := a.FeeRepo.List(ctx, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing fee rules: %w", err)
+	}
+	return rules, nil
+}
+
+// SetFeeRule creates or replaces a rule. Wallet rules take the currency of
+// the wallet.
+func (a *Application) SetFeeRule(ctx context.Context, rule entities.FeeRule) (entities.FeeRule, error) {
+	if !feeOperations[rule.OperationType] {
+		return entities.FeeRule{}, fmt.Errorf("%w: fees are not charged on %s", entities.ErrInvalidFeeRule, rule.OperationType)
+	}
+	if rule.WalletId != "" {
+		wallet, err := a.WalletRepo.GetByID(ctx, rule.WalletId)
+		if err != nil {
+			return entities.FeeRule{}, err
+		}
+		rule.Currency = wallet.Currency
+	}
+
+	err := rule.Validate()
+	if err != nil {
+		return entities.FeeRule{}, err
+	}
+	amounts := []decimal.Decimal{rule.Flat, rule.Min, rule.Max}
+	for _, tier := range rule.Tiers {
+		amounts = append(amounts, tier.From, tier.Flat)
+	}
+	for _, amount := range amounts {
+		err = entities.ValidateAmount(amount, rule.Currency)
+		if err != nil {
+			return entities.FeeRule{}, err
+		}
+	}
+
+	rule, err = a.FeeRepo.Save(ctx, rule)
+	if err != nil {
+		return entities.FeeRule{}, fmt.Errorf("error saving fee rule: %w", err)
+	}
+	return rule, nil
+}
+
+// DeleteFeeRule removes a rule; a wallet falls back to the default rule.
+func (a *Application) DeleteFeeRule(ctx context.Context, walletID, operationType, currency string) error {
+	if walletID != "" {
+		wallet, err := a.WalletRepo.GetByID(ctx, walletID)
+		if err != nil {
+			return err
+		}
+		currency = wallet.Currency
+	}
+
+	err := a.FeeRepo.Delete(ctx, walletID, operationType, currency)
+	if err != nil {
+		return fmt.Errorf("error deleting fee rule: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,290 @@
+package application
+
+import (
+	"TestProject/source/internal/entities"
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+type mockFeeRepo struct {
+	mu    sync.Mutex
+	rules map[string]entities.FeeRule
+}
+
+func newMockFeeRepo() *mockFeeRepo {
+	return &mockFeeRepo{rules: make(map[string]entities.FeeRule)}
+}
+
+func feeKey(walletID, operationType, currency string) string {
+	return walletID + "/" + operationType + "/" + currency
+}
+
+func (m *mockFeeRepo) Effective(ctx context.Context, walletID, operationType, currency string) (entities.FeeRule, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, key := range []string{feeKey(walletID, operationType, currency), feeKey("", operationType, currency)} {
+		if rule, ok := m.rules[key]; ok {
+			return rule, nil
+		}
+	}
+	return entities.FeeRule{}, entities.ErrFeeRuleNotFound
+}
+
+func (m *mockFeeRepo) List(ctx context.Context, walletID string) ([]entities.FeeRule, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var result []entities.FeeRule
+	for _, rule := range m.rules {
+		if rule.WalletId == walletID {
+			result = append(result, rule)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].OperationType+result[i].Currency < result[j].OperationType+result[j].Currency
+	})
+	return result, nil
+}
+
+func (m *mockFeeRepo) Save(ctx context.Context, rule entities.FeeRule) (entities.FeeRule, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.rules[feeKey(rule.WalletId, rule.OperationType, rule.Currency)] = rule
+	return rule, nil
+}
+
+func (m *mockFeeRepo) Delete(ctx context.Context, walletID, operationType, currency string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := feeKey(walletID, operationType, currency)
+	if _, ok := m.rules[key]; !ok {
+		return entities.ErrFeeRuleNotFound
+	}
+	delete(m.rules, key)
+	return nil
+}
+
+func dec(value string) decimal.Decimal {
+	return decimal.RequireFromString(value)
+}
+
+func TestFeeRule_Calculate(t *testing.T) {
+	tiered := []entities.FeeTier{
+		{From: dec("0"), Flat: dec("1")},
+		{From: dec("100"), Percent: dec("1")},
+		{From: dec("1000"), Flat: dec("5"), Percent: dec("0.5")},
+	}
+
+	tests := []struct {
+		name   string
+		rule   entities.FeeRule
+		amount string
+		fee    string
+	}{
+		{"flat", entities.FeeRule{Type: entities.FeeFlat, Flat: dec("2.5")}, "10", "2.5"},
+		{"percentage", entities.FeeRule{Type: entities.FeePercentage, Percent: dec("1.5")}, "200", "3"},
+		{"percentage rounds half up", entities.FeeRule{Type: entities.FeePercentage, Percent: dec("1")}, "0.5", "0.01"},
+		{"percentage min", entities.FeeRule{Type: entities.FeePercentage, Percent: dec("1"), Min: dec("0.3")}, "10", "0.3"},
+		{"percentage max", entities.FeeRule{Type: entities.FeePercentage, Percent: dec("1"), Max: dec("5")}, "10000", "5"},
+		{"first tier", entities.FeeRule{Type: entities.FeeTiered, Tiers: tiered}, "99.99", "1"},
+		{"middle tier", entities.FeeRule{Type: entities.FeeTiered, Tiers: tiered}, "100", "1"},
+		{"last tier", entities.FeeRule{Type: entities.FeeTiered, Tiers: tiered}, "2000", "15"},
+		{"below first tier", entities.FeeRule{Type: entities.FeeTiered, Tiers: tiered[1:], Min: dec("0.2")}, "50", "0.2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.rule.Currency = "USD"
+			err := tt.rule.Validate()
+			if err != nil {
+				t.Fatalf("unexpected validation error: %v", err)
+			}
+
+			fee, err := tt.rule.Calculate(dec(tt.amount))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !fee.Equal(dec(tt.fee)) {
+				t.Errorf("expected fee %s, got %s", tt.fee, fee.String())
+			}
+		})
+	}
+}
+
+func TestFeeRule_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		rule entities.FeeRule
+	}{
+		{"unknown type", entities.FeeRule{Type: "BONUS"}},
+		{"negative flat", entities.FeeRule{Type: entities.FeeFlat, Flat: dec("-1")}},
+		{"percent above 100", entities.FeeRule{Type: entities.FeePercentage, Percent: dec("101")}},
+		{"max below min", entities.FeeRule{Type: entities.FeeFlat, Min: dec("5"), Max: dec("1")}},
+		{"tiers on flat rule", entities.FeeRule{Type: entities.FeeFlat, Tiers: []entities.FeeTier{{}}}},
+		{"tiered without tiers", entities.FeeRule{Type: entities.FeeTiered}},
+		{"unsorted tiers", entities.FeeRule{Type: entities.FeeTiered, Tiers: []entities.FeeTier{{From: dec("10")}, {From: dec("10")}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Validate()
+			if !errors.Is(err, entities.ErrInvalidFeeRule) {
+				t.Errorf("expected ErrInvalidFeeRule, got %v", err)
+			}
+		})
+	}
+}
+
+func TestApplication_ProcessTransactionWithFee(t *testing.T) {
+	repo := newMockWalletRepo()
+	app, _ := newTestApplication(repo)
+	ctx := context.Background()
+
+	wallet := entities.NewWallet()
+	wallet.Currency = "USD"
+	wallet.Balance = dec("100")
+	repo.wallets[wallet.ID] = wallet
+
+	_, err := app.SetFeeRule(ctx, entities.FeeRule{
+		OperationType: "WITHDRAW",
+		Currency:      "USD",
+		Type:          entities.FeePercentage,
+		Percent:       dec("1"),
+		Min:           dec("0.5"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 99.60 + max(0.996, 0.50) rounded = 100.60 is more than the balance.
+	_, err = app.ProcessTransaction(ctx, entities.Transaction{WalletId: wallet.ID, OperationType: "WITHDRAW", Amount: dec("99.6")})
+	if !errors.Is(err, entities.ErrInsufficientFunds) {
+		t.Fatalf("expected ErrInsufficientFunds, got %v", err)
+	}
+
+	transaction, err := app.ProcessTransaction(ctx, entities.Transaction{WalletId: wallet.ID, OperationType: "WITHDRAW", Amount: dec("99")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !transaction.Fee.Equal(dec("0.99")) || !transaction.Balance.Equal(dec("0.01")) {
+		t.Errorf("expected fee 0.99 and balance 0.01, got %s and %s", transaction.Fee.String(), transaction.Balance.String())
+	}
+
+	balances, err := app.SystemAccountBalances(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var fees decimal.Decimal
+	for _, balance := range balances {
+		if balance.AccountId == entities.SystemFees {
+			fees = balance.Balance
+		}
+	}
+	if !fees.Equal(dec("0.99")) {
+		t.Errorf("expected 0.99 on %s, got %s", entities.SystemFees, fees.String())
+	}
+
+	// Deposits have no rule, so they stay free.
+	transaction, err = app.ProcessTransaction(ctx, entities.Transaction{WalletId: wallet.ID, OperationType: "DEPOSIT", Amount: dec("10")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !transaction.Fee.IsZero() {
+		t.Errorf("expected no fee on deposit, got %s", transaction.Fee.String())
+	}
+}
+
+func TestApplication_WalletFeeRuleOverridesDefault(t *testing.T) {
+	repo := newMockWalletRepo()
+	app, _ := newTestApplication(repo)
+	ctx := context.Background()
+
+	wallet := entities.NewWallet()
+	wallet.Currency = "USD"
+	wallet.Balance = dec("100")
+	repo.wallets[wallet.ID] = wallet
+
+	for _, rule := range []entities.FeeRule{
+		{OperationType: "DEPOSIT", Currency: "USD", Type: entities.FeeFlat, Flat: dec("1")},
+		{WalletId: wallet.ID, OperationType: "DEPOSIT", Type: entities.FeeFlat, Flat: dec("0.25")},
+	} {
+		_, err := app.SetFeeRule(ctx, rule)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	transaction, err := app.ProcessTransaction(ctx, entities.Transaction{WalletId: wallet.ID, OperationType: "DEPOSIT", Amount: dec("10")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !transaction.Fee.Equal(dec("0.25")) || !transaction.Balance.Equal(dec("109.75")) {
+		t.Errorf("expected fee 0.25 and balance 109.75, got %s and %s", transaction.Fee.String(), transaction.Balance.String())
+	}
+
+	err = app.DeleteFeeRule(ctx, wallet.ID, "DEPOSIT", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	transaction, err = app.ProcessTransaction(ctx, entities.Transaction{WalletId: wallet.ID, OperationType: "DEPOSIT", Amount: dec("10")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !transaction.Fee.Equal(dec("1")) {
+		t.Errorf("expected default fee 1, got %s", transaction.Fee.String())
+	}
+
+	_, err = app.SetFeeRule(ctx, entities.FeeRule{OperationType: "TRANSFER_OUT", Currency: "USD", Type: entities.FeeFlat})
+	if !errors.Is(err, entities.ErrInvalidFeeRule) {
+		t.Errorf("expected ErrInvalidFeeRule, got %v", err)
+	}
+}
+
+func TestApplication_CreateWallet_OpeningBalanceIsFree(t *testing.T) {
+	repo := newMockWalletRepo()
+	app, ledger := newTestApplication(repo)
+	ctx := context.Background()
+
+	// A flat deposit fee larger than the opening balance neither fails the
+	// creation nor is charged on it.
+	_, err := app.SetFeeRule(ctx, entities.FeeRule{
+		OperationType: "DEPOSIT",
+		Currency:      "USD",
+		Type:          entities.FeeFlat,
+		Flat:          dec("5"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wallet := entities.NewWallet()
+	wallet.Currency = "USD"
+	wallet.Balance = dec("3")
+	wallet, err = app.CreateWallet(ctx, wallet)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !wallet.Balance.Equal(dec("3")) {
+		t.Errorf("expected balance 3, got %s", wallet.Balance.String())
+	}
+	if len(ledger.transactions) != 1 || !ledger.transactions[0].Fee.IsZero() {
+		t.Errorf("expected one free opening deposit, got %+v", ledger.transactions)
+	}
+
+	// Later deposits are charged.
+	transaction, err := app.ProcessTransaction(ctx, entities.Transaction{WalletId: wallet.ID, OperationType: "DEPOSIT", Amount: dec("10")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !transaction.Fee.Equal(dec("5")) {
+		t.Errorf("expected fee 5, got %s", transaction.Fee.String())
+	}
+}
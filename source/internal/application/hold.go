@@ -0,0 +1,287 @@
+package application
+
+import (
+	"TestProject/source/internal/entities"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"go.uber.org/fx"
+)
+
+const holdExpiryBatchSize = 100
+
+// Every hold state change happens under the lock of the hold's wallet, so
+// the hold is re-read after UpdateWithLock has taken that lock.
+
+// CreateHold reserves hold.Amount on the wallet. A zero ExpiresAt gets the
+// configured default TTL.
+func (a *Application) CreateHold(ctx context.Context, hold entities.Hold) (entities.Hold, error) {
+	if hold.ID == "" {
+		hold.ID = uuid.NewString()
+	}
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	hold.Status = entities.HoldActive
+	hold.CapturedAmount = decimal.Zero
+	hold.CreatedAt = now
+	hold.UpdatedAt = now
+	if hold.ExpiresAt.IsZero() {
+		hold.ExpiresAt = now.Add(a.holdConf.DefaultTTL)
+	}
+
+	err := a.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		_, err := a.WalletRepo.UpdateWithLock(ctx, hold.WalletId, func(w *entities.Wallet) error {
+			if hold.Currency != "" && hold.Currency != w.Currency {
+				return fmt.Errorf("%w: wallet is %s, got %s", entities.ErrCurrencyMismatch, w.Currency, hold.Currency)
+			}
+			err := w.CheckDebit()
+			if err != nil {
+				return err
+			}
+			err = entities.ValidateAmount(hold.Amount, w.Currency)
+			if err != nil {
+				return err
+			}
+			if w.Spendable().LessThan(hold.Amount) {
+				return entities.ErrInsufficientFunds
+			}
+			err = a.checkVelocity(ctx, w.ID, hold.Amount, now, "")
+			if err != nil {
+				return err
+			}
+
+			hold.Currency = w.Currency
+			w.Reserved = w.Reserved.Add(hold.Amount)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		hold, err = a.HoldRepo.Create(ctx, hold)
+		if err != nil {
+			return fmt.Errorf("error creating hold: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return entities.Hold{}, fmt.Errorf("error processing hold: %w", err)
+	}
+
+	return hold, nil
+}
+
+// CaptureHold withdraws amount from the held funds and releases the rest.
+// A nil amount captures the whole hold.
+func (a *Application) CaptureHold(ctx context.Context, holdID string, amount *decimal.Decimal) (entities.Hold, entities.Transaction, error) {
+	var (
+		hold        entities.Hold
+		transaction entities.Transaction
+		entry       entities.JournalEntry
+	)
+
+	err := a.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		walletID, err := a.holdWalletID(ctx, holdID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now().UTC().Truncate(time.Microsecond)
+
+		wallet, err := a.WalletRepo.UpdateWithLock(ctx, walletID, func(w *entities.Wallet) error {
+			hold, err = a.HoldRepo.GetByID(ctx, holdID)
+			if err != nil {
+				return err
+			}
+			if hold.Status != entities.HoldActive {
+				return fmt.Errorf("%w: %s", entities.ErrHoldNotActive, hold.Status)
+			}
+			if !now.Before(hold.ExpiresAt) {
+				return entities.ErrHoldExpired
+			}
+			err = w.CheckDebit()
+			if err != nil {
+				return err
+			}
+
+			captured := hold.Amount
+			if amount != nil {
+				captured = *amount
+			}
+			if captured.GreaterThan(hold.Amount) {
+				return entities.ErrCaptureExceedsHold
+			}
+			err = entities.ValidateAmount(captured, w.Currency)
+			if err != nil {
+				return err
+			}
+			// The hold was checked when it was created, but the limits may
+			// have been lowered since.
+			err = a.checkVelocity(ctx, w.ID, captured, now, hold.ID)
+			if err != nil {
+				return err
+			}
+
+			transaction = entities.Transaction{
+				ID:            uuid.NewString(),
+				WalletId:      w.ID,
+				OperationType: capture,
+				Currency:      w.Currency,
+				Amount:        captured,
+				BalanceBefore: w.Balance,
+				ReferenceId:   hold.ID,
+				CreatedAt:     now,
+			}
+
+			entry, err = singleWalletEntry(transaction)
+			if err != nil {
+				return err
+			}
+			err = applyJournal(entry, map[string]*entities.Wallet{w.ID: w})
+			if err != nil {
+				return err
+			}
+			w.Reserved = w.Reserved.Sub(hold.Amount)
+
+			hold.Status = entities.HoldCaptured
+			hold.CapturedAmount = captured
+			hold.TransactionId = transaction.ID
+			hold.UpdatedAt = now
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		transaction.Balance = wallet.Balance
+
+		transaction, err = a.recordTransaction(ctx, transaction)
+		if err != nil {
+			return fmt.Errorf("error recording transaction: %w", err)
+		}
+		err = a.JournalRepo.Create(ctx, entry)
+		if err != nil {
+			return fmt.Errorf("error recording journal entry: %w", err)
+		}
+
+		hold, err = a.HoldRepo.Update(ctx, hold)
+		if err != nil {
+			return fmt.Errorf("error updating hold: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return entities.Hold{}, entities.Transaction{}, fmt.Errorf("error capturing hold: %w", err)
+	}
+
+	return hold, transaction, nil
+}
+
+// VoidHold releases all funds held by an active hold.
+func (a *Application) VoidHold(ctx context.Context, holdID string) (entities.Hold, error) {
+	hold, err := a.releaseHold(ctx, holdID, entities.HoldVoided)
+	if err != nil {
+		return entities.Hold{}, fmt.Errorf("error voiding hold: %w", err)
+	}
+	return hold, nil
+}
+
+// ExpireHolds releases active holds whose TTL has run out and returns how
+// many were expired.
+func (a *Application) ExpireHolds(ctx context.Context) (int, error) {
+	expired := 0
+
+	for {
+		holds, err := a.HoldRepo.ListExpired(ctx, time.Now().UTC(), holdExpiryBatchSize)
+		if err != nil {
+			return expired, fmt.Errorf("error listing expired holds: %w", err)
+		}
+
+		for _, hold := range holds {
+			_, err = a.releaseHold(ctx, hold.ID, entities.HoldExpired)
+			if errors.Is(err, entities.ErrHoldNotActive) {
+				// Captured or voided since it was listed.
+				continue
+			}
+			if err != nil {
+				return expired, fmt.Errorf("error expiring hold %s: %w", hold.ID, err)
+			}
+			expired++
+		}
+
+		if len(holds) < holdExpiryBatchSize {
+			return expired, nil
+		}
+	}
+}
+
+func (a *Application) releaseHold(ctx context.Context, holdID, status string) (entities.Hold, error) {
+	var hold entities.Hold
+
+	err := a.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		walletID, err := a.holdWalletID(ctx, holdID)
+		if err != nil {
+			return err
+		}
+
+		_, err = a.WalletRepo.UpdateWithLock(ctx, walletID, func(w *entities.Wallet) error {
+			hold, err = a.HoldRepo.GetByID(ctx, holdID)
+			if err != nil {
+				return err
+			}
+			if hold.Status != entities.HoldActive {
+				return fmt.Errorf("%w: %s", entities.ErrHoldNotActive, hold.Status)
+			}
+
+			w.Reserved = w.Reserved.Sub(hold.Amount)
+
+			hold.Status = status
+			hold.UpdatedAt = time.Now().UTC().Truncate(time.Microsecond)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		hold, err = a.HoldRepo.Update(ctx, hold)
+		if err != nil {
+			return fmt.Errorf("error updating hold: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return entities.Hold{}, err
+	}
+
+	return hold, nil
+}
+
+func (a *Application) holdWalletID(ctx context.Context, holdID string) (string, error) {
+	hold, err := a.HoldRepo.GetByID(ctx, holdID)
+	if err != nil {
+		return "", err
+	}
+	return hold.WalletId, nil
+}
+
+// RegisterHoldExpiry periodically releases expired holds. A zero expiry
+// interval disables it.
+func RegisterHoldExpiry(lc fx.Lifecycle, app *Application) {
+	if app.holdConf.ExpiryInterval <= 0 {
+		return
+	}
+
+	runPeriodically(lc, app.holdConf.ExpiryInterval, func(ctx context.Context) {
+		expired, err := app.ExpireHolds(ctx)
+		if err != nil {
+			app.log.ErrorContext(ctx, "error expiring holds", slog.String("error", err.Error()))
+		}
+		if expired > 0 {
+			app.log.InfoContext(ctx, "expired holds", slog.Int("expired", expired))
+		}
+	})
+}
@@ -0,0 +1,254 @@
+package application
+
+import (
+	"TestProject/source/internal/entities"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type mockHoldRepo struct {
+	mu    sync.Mutex
+	holds map[string]entities.Hold
+}
+
+func newMockHoldRepo() *mockHoldRepo {
+	return &mockHoldRepo{holds: make(map[string]entities.Hold)}
+}
+
+func (m *mockHoldRepo) Create(ctx context.Context, hold entities.Hold) (entities.Hold, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.holds[hold.ID] = hold
+	return hold, nil
+}
+
+func (m *mockHoldRepo) GetByID(ctx context.Context, holdID string) (entities.Hold, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	hold, ok := m.holds[holdID]
+	if !ok {
+		return entities.Hold{}, entities.ErrHoldNotFound
+	}
+	return hold, nil
+}
+
+func (m *mockHoldRepo) Update(ctx context.Context, hold entities.Hold) (entities.Hold, error) {
+	return m.Create(ctx, hold)
+}
+
+func (m *mockHoldRepo) ListExpired(ctx context.Context, now time.Time, limit int) ([]entities.Hold, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var result []entities.Hold
+	for _, hold := range m.holds {
+		if hold.Status == entities.HoldActive && !hold.ExpiresAt.After(now) && len(result) < limit {
+			result = append(result, hold)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockHoldRepo) Usage(ctx context.Context, walletID string, since time.Time, exceptID string) (entities.Usage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var usage entities.Usage
+	for _, hold := range m.holds {
+		if hold.WalletId == walletID && hold.Status == entities.HoldActive && hold.ID != exceptID &&
+			!hold.CreatedAt.Before(since) {
+			usage.Amount = usage.Amount.Add(hold.Amount)
+			usage.Count++
+		}
+	}
+	return usage, nil
+}
+
+func newHoldTestApplication(balance float64) (*Application, *mockWalletRepo, *mockTransactionRepo, entities.Wallet) {
+	repo := newMockWalletRepo()
+	app, ledger := newTestApplication(repo)
+	app.HoldRepo = newMockHoldRepo()
+	app.holdConf.DefaultTTL = time.Hour
+
+	wallet := entities.NewWallet()
+	wallet.Balance = decimal.NewFromFloat(balance)
+	repo.wallets[wallet.ID] = wallet
+
+	return app, repo, ledger, wallet
+}
+
+func TestApplication_CreateHold_ReducesAvailable(t *testing.T) {
+	app, repo, _, wallet := newHoldTestApplication(1000.0)
+	ctx := context.Background()
+
+	hold := entities.NewHold()
+	hold.WalletId = wallet.ID
+	hold.Amount = decimal.NewFromFloat(400.0)
+
+	_, err := app.CreateHold(ctx, hold)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	updated := repo.wallets[wallet.ID]
+	if !updated.Balance.Equal(decimal.NewFromFloat(1000.0)) {
+		t.Errorf("expected ledger balance 1000.0, got %s", updated.Balance.String())
+	}
+	if !updated.Available().Equal(decimal.NewFromFloat(600.0)) {
+		t.Errorf("expected available balance 600.0, got %s", updated.Available().String())
+	}
+
+	_, err = app.ProcessTransaction(ctx, entities.Transaction{
+		WalletId:      wallet.ID,
+		OperationType: "WITHDRAW",
+		Amount:        decimal.NewFromFloat(700.0),
+	})
+	if !errors.Is(err, entities.ErrInsufficientFunds) {
+		t.Errorf("expected withdraw over available balance to fail, got %v", err)
+	}
+}
+
+func TestApplication_CreateHold_InsufficientFunds(t *testing.T) {
+	app, _, _, wallet := newHoldTestApplication(100.0)
+
+	hold := entities.NewHold()
+	hold.WalletId = wallet.ID
+	hold.Amount = decimal.NewFromFloat(150.0)
+
+	_, err := app.CreateHold(context.Background(), hold)
+	if !errors.Is(err, entities.ErrInsufficientFunds) {
+		t.Fatalf("expected ErrInsufficientFunds, got %v", err)
+	}
+}
+
+func TestApplication_CaptureHold_Partial(t *testing.T) {
+	app, repo, ledger, wallet := newHoldTestApplication(1000.0)
+	ctx := context.Background()
+
+	hold := entities.NewHold()
+	hold.WalletId = wallet.ID
+	hold.Amount = decimal.NewFromFloat(400.0)
+	hold, err := app.CreateHold(ctx, hold)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	amount := decimal.NewFromFloat(250.0)
+	captured, transaction, err := app.CaptureHold(ctx, hold.ID, &amount)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if captured.Status != entities.HoldCaptured || !captured.CapturedAmount.Equal(amount) {
+		t.Errorf("unexpected hold after capture: %+v", captured)
+	}
+	if transaction.ReferenceId != hold.ID || transaction.OperationType != "CAPTURE" {
+		t.Errorf("unexpected capture transaction: %+v", transaction)
+	}
+
+	updated := repo.wallets[wallet.ID]
+	if !updated.Balance.Equal(decimal.NewFromFloat(750.0)) {
+		t.Errorf("expected ledger balance 750.0, got %s", updated.Balance.String())
+	}
+	if !updated.Reserved.IsZero() {
+		t.Errorf("expected nothing reserved, got %s", updated.Reserved.String())
+	}
+	if len(ledger.transactions) != 1 {
+		t.Errorf("expected 1 ledger entry, got %d", len(ledger.transactions))
+	}
+
+	_, _, err = app.CaptureHold(ctx, hold.ID, nil)
+	if !errors.Is(err, entities.ErrHoldNotActive) {
+		t.Errorf("expected ErrHoldNotActive on second capture, got %v", err)
+	}
+}
+
+func TestApplication_CaptureHold_ExceedsHold(t *testing.T) {
+	app, _, _, wallet := newHoldTestApplication(1000.0)
+	ctx := context.Background()
+
+	hold := entities.NewHold()
+	hold.WalletId = wallet.ID
+	hold.Amount = decimal.NewFromFloat(100.0)
+	hold, err := app.CreateHold(ctx, hold)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	amount := decimal.NewFromFloat(150.0)
+	_, _, err = app.CaptureHold(ctx, hold.ID, &amount)
+	if !errors.Is(err, entities.ErrCaptureExceedsHold) {
+		t.Fatalf("expected ErrCaptureExceedsHold, got %v", err)
+	}
+}
+
+func TestApplication_VoidHold(t *testing.T) {
+	app, repo, ledger, wallet := newHoldTestApplication(1000.0)
+	ctx := context.Background()
+
+	hold := entities.NewHold()
+	hold.WalletId = wallet.ID
+	hold.Amount = decimal.NewFromFloat(400.0)
+	hold, err := app.CreateHold(ctx, hold)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	voided, err := app.VoidHold(ctx, hold.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if voided.Status != entities.HoldVoided {
+		t.Errorf("expected status VOIDED, got %s", voided.Status)
+	}
+
+	updated := repo.wallets[wallet.ID]
+	if !updated.Available().Equal(decimal.NewFromFloat(1000.0)) {
+		t.Errorf("expected available balance 1000.0, got %s", updated.Available().String())
+	}
+	if len(ledger.transactions) != 0 {
+		t.Errorf("expected no ledger entries, got %d", len(ledger.transactions))
+	}
+}
+
+func TestApplication_ExpireHolds(t *testing.T) {
+	app, repo, _, wallet := newHoldTestApplication(1000.0)
+	ctx := context.Background()
+
+	hold := entities.NewHold()
+	hold.WalletId = wallet.ID
+	hold.Amount = decimal.NewFromFloat(400.0)
+	hold.ExpiresAt = time.Now().Add(-time.Second)
+	hold, err := app.CreateHold(ctx, hold)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, _, err = app.CaptureHold(ctx, hold.ID, nil)
+	if !errors.Is(err, entities.ErrHoldExpired) {
+		t.Errorf("expected ErrHoldExpired, got %v", err)
+	}
+
+	expired, err := app.ExpireHolds(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expired != 1 {
+		t.Errorf("expected 1 expired hold, got %d", expired)
+	}
+
+	stored, _ := app.HoldRepo.GetByID(ctx, hold.ID)
+	if stored.Status != entities.HoldExpired {
+		t.Errorf("expected status EXPIRED, got %s", stored.Status)
+	}
+	if !repo.wallets[wallet.ID].Reserved.IsZero() {
+		t.Errorf("expected nothing reserved, got %s", repo.wallets[wallet.ID].Reserved.String())
+	}
+}
@@ -0,0 +1,86 @@
+package application
+
+import (
+	"TestProject/source/internal/entities"
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.uber.org/fx"
+)
+
+const idempotencyCleanupInterval = time.Hour
+
+// ExecuteIdempotent runs fn at most once per idempotency key. fn and the key
+// bookkeeping share one database transaction, so the stored response is
+// committed together with the changes fn made. A completed key with the same
+// request hash is returned with replayed set; with a different hash the call
+// fails with ErrIdempotencyKeyReused. Failed calls leave no trace and can be
+// retried with the same key.
+func (a *Application) ExecuteIdempotent(
+	ctx context.Context,
+	key, requestHash string,
+	fn func(ctx context.Context) (statusCode int, response []byte, err error),
+) (record entities.IdempotencyRecord, replayed bool, err error) {
+	now := time.Now().UTC()
+
+	err = a.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		stored, err := a.IdempotencyRepo.Acquire(ctx, entities.IdempotencyRecord{
+			Key:         key,
+			RequestHash: requestHash,
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(a.idempotencyTTL),
+		})
+		if err != nil {
+			return fmt.Errorf("error acquiring idempotency key: %w", err)
+		}
+
+		if stored.RequestHash != requestHash {
+			return entities.ErrIdempotencyKeyReused
+		}
+		if stored.Completed() {
+			record, replayed = stored, true
+			return nil
+		}
+
+		stored.StatusCode, stored.Response, err = fn(ctx)
+		if err != nil {
+			return err
+		}
+
+		err = a.IdempotencyRepo.Complete(ctx, stored)
+		if err != nil {
+			return fmt.Errorf("error storing idempotent response: %w", err)
+		}
+
+		record = stored
+		return nil
+	})
+	if err != nil {
+		return entities.IdempotencyRecord{}, false, err
+	}
+
+	return record, replayed, nil
+}
+
+func (a *Application) PurgeExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	deleted, err := a.IdempotencyRepo.DeleteExpired(ctx, time.Now().UTC())
+	if err != nil {
+		return 0, fmt.Errorf("error purging idempotency keys: %w", err)
+	}
+	return deleted, nil
+}
+
+// RegisterIdempotencyKeyCleanup periodically removes expired idempotency keys
+// for as long as the app runs.
+func RegisterIdempotencyKeyCleanup(lc fx.Lifecycle, app *Application) {
+	runPeriodically(lc, idempotencyCleanupInterval, func(ctx context.Context) {
+		deleted, err := app.PurgeExpiredIdempotencyKeys(ctx)
+		if err != nil {
+			app.log.ErrorContext(ctx, "error purging idempotency keys", slog.String("error", err.Error()))
+			return
+		}
+		app.log.InfoContext(ctx, "purged idempotency keys", slog.Int64("deleted", deleted))
+	})
+}
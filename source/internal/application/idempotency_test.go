@@ -0,0 +1,132 @@
+package application
+
+import (
+	"TestProject/source/internal/entities"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type mockIdempotencyRepo struct {
+	mu      sync.Mutex
+	records map[string]entities.IdempotencyRecord
+}
+
+func newMockIdempotencyRepo() *mockIdempotencyRepo {
+	return &mockIdempotencyRepo{records: make(map[string]entities.IdempotencyRecord)}
+}
+
+func (m *mockIdempotencyRepo) Acquire(ctx context.Context, record entities.IdempotencyRecord) (entities.IdempotencyRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, ok := m.records[record.Key]
+	if ok && stored.ExpiresAt.After(record.CreatedAt) {
+		return stored, nil
+	}
+	m.records[record.Key] = record
+	return record, nil
+}
+
+func (m *mockIdempotencyRepo) Complete(ctx context.Context, record entities.IdempotencyRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.records[record.Key] = record
+	return nil
+}
+
+func (m *mockIdempotencyRepo) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	return 0, nil
+}
+
+func newIdempotentTestApplication() (*Application, *mockIdempotencyRepo) {
+	repo := newMockIdempotencyRepo()
+	return &Application{
+		idempotencyTTL:  time.Hour,
+		Transactor:      mockTransactor{},
+		IdempotencyRepo: repo,
+	}, repo
+}
+
+func TestApplication_ExecuteIdempotent_Replays(t *testing.T) {
+	app, _ := newIdempotentTestApplication()
+	ctx := context.Background()
+
+	calls := 0
+	fn := func(ctx context.Context) (int, []byte, error) {
+		calls++
+		return 201, []byte(`{"balance":1500}`), nil
+	}
+
+	first, replayed, err := app.ExecuteIdempotent(ctx, "key-1", "hash-1", fn)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if replayed {
+		t.Error("expected first call not to be replayed")
+	}
+
+	second, replayed, err := app.ExecuteIdempotent(ctx, "key-1", "hash-1", fn)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !replayed {
+		t.Error("expected second call to be replayed")
+	}
+
+	if calls != 1 {
+		t.Errorf("expected fn to run once, ran %d times", calls)
+	}
+	if second.StatusCode != first.StatusCode || string(second.Response) != string(first.Response) {
+		t.Errorf("expected replayed response %d %s, got %d %s",
+			first.StatusCode, first.Response, second.StatusCode, second.Response)
+	}
+}
+
+func TestApplication_ExecuteIdempotent_DifferentRequest(t *testing.T) {
+	app, _ := newIdempotentTestApplication()
+	ctx := context.Background()
+
+	fn := func(ctx context.Context) (int, []byte, error) {
+		return 201, []byte(`{}`), nil
+	}
+
+	_, _, err := app.ExecuteIdempotent(ctx, "key-1", "hash-1", fn)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, _, err = app.ExecuteIdempotent(ctx, "key-1", "hash-2", fn)
+	if !errors.Is(err, entities.ErrIdempotencyKeyReused) {
+		t.Errorf("expected ErrIdempotencyKeyReused, got %v", err)
+	}
+}
+
+func TestApplication_ExecuteIdempotent_ExpiredKey(t *testing.T) {
+	app, repo := newIdempotentTestApplication()
+	ctx := context.Background()
+
+	repo.records["key-1"] = entities.IdempotencyRecord{
+		Key:         "key-1",
+		RequestHash: "hash-1",
+		StatusCode:  201,
+		Response:    []byte(`{"old":true}`),
+		ExpiresAt:   time.Now().Add(-time.Minute),
+	}
+
+	record, replayed, err := app.ExecuteIdempotent(ctx, "key-1", "hash-2", func(ctx context.Context) (int, []byte, error) {
+		return 201, []byte(`{"old":false}`), nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if replayed {
+		t.Error("expected expired key not to be replayed")
+	}
+	if string(record.Response) != `{"old":false}` {
+		t.Errorf("unexpected response %s", record.Response)
+	}
+}
@@ -0,0 +1,272 @@
+package application
+
+import (
+	"TestProject/source/internal/entities"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"go.uber.org/fx"
+)
+
+const interestBatchSize = 100
+
+// SetInterestPlan starts accruing interest on a wallet from today, or
+// changes its plan. A changed plan keeps the interest accrued so far and
+// applies from the first day not accrued yet.
+func (a *Application) SetInterestPlan(ctx context.Context, plan entities.InterestPlan) (entities.InterestPlan, error) {
+	if plan.DayCount == "" {
+		plan.DayCount = entities.DayCountActual365
+	}
+	if plan.Rounding == "" {
+		plan.Rounding = entities.RoundHalfUp
+	}
+	if plan.PayoutFrequency == "" {
+		plan.PayoutFrequency = entities.FrequencyMonthly
+	}
+	err := plan.Validate()
+	if err != nil {
+		return entities.InterestPlan{}, err
+	}
+
+	err = a.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		wallet, err := a.WalletRepo.GetByID(ctx, plan.WalletId)
+		if err != nil {
+			return err
+		}
+		plan.Currency = wallet.Currency
+
+		now := time.Now().UTC().Truncate(time.Microsecond)
+		existing, err := a.InterestRepo.GetByWalletIDForUpdate(ctx, plan.WalletId)
+		switch {
+		case err == nil:
+			plan.Accrued = existing.Accrued
+			plan.NextAccrualDay = existing.NextAccrualDay
+			plan.NextPayoutAt = existing.NextPayoutAt
+			if plan.PayoutFrequency != existing.PayoutFrequency {
+				plan.NextPayoutAt = plan.FirstPeriodEnd(plan.NextAccrualDay)
+			}
+			plan.CreatedAt = existing.CreatedAt
+		case errors.Is(err, entities.ErrInterestPlanNotFound):
+			today := now.Truncate(24 * time.Hour)
+			plan.Accrued = decimal.Zero
+			plan.NextAccrualDay = today
+			plan.NextPayoutAt = plan.FirstPeriodEnd(today)
+			plan.CreatedAt = now
+		default:
+			return fmt.Errorf("error getting interest plan: %w", err)
+		}
+		plan.UpdatedAt = now
+
+		plan, err = a.InterestRepo.Save(ctx, plan)
+		if err != nil {
+			return fmt.Errorf("error saving interest plan: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return entities.InterestPlan{}, fmt.Errorf("error setting interest plan: %w", err)
+	}
+
+	return plan, nil
+}
+
+func (a *Application) GetInterestPlan(ctx context.Context, walletID string) (entities.InterestPlan, error) {
+	plan, err := a.InterestRepo.GetByWalletID(ctx, walletID)
+	if err != nil {
+		return entities.InterestPlan{}, fmt.Errorf("error getting interest plan: %w", err)
+	}
+	return plan, nil
+}
+
+// ListInterestPayouts returns the wallet's latest payouts, newest first.
+func (a *Application) ListInterestPayouts(ctx context.Context, walletID string, limit int) ([]entities.InterestPayout, error) {
+	payouts, err := a.InterestRepo.ListPayouts(ctx, walletID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error listing interest payouts: %w", err)
+	}
+	return payouts, nil
+}
+
+// DeleteInterestPlan stops accruing interest on the wallet and pays out what
+// has been accrued so far. The part lost to rounding is dropped. When the
+// payout is rejected the plan stays.
+func (a *Application) DeleteInterestPlan(ctx context.Context, walletID string) error {
+	err := a.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		plan, err := a.InterestRepo.GetByWalletIDForUpdate(ctx, walletID)
+		if err != nil {
+			return err
+		}
+
+		err = a.payInterest(ctx, &plan, plan.NextAccrualDay, time.Now().UTC().Truncate(time.Microsecond))
+		if err != nil {
+			return err
+		}
+
+		return a.InterestRepo.Delete(ctx, walletID)
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting interest plan: %w", err)
+	}
+	return nil
+}
+
+// RunInterest accrues every plan up to the last day finished before now and
+// pays out the periods that ended. It returns how many plans it accrued.
+// Days already accrued are skipped, so running it again for the same now,
+// or an earlier one, changes nothing.
+func (a *Application) RunInterest(ctx context.Context, now time.Time) (int, error) {
+	cutoff := now.UTC().Add(-snapshotGrace).Truncate(24 * time.Hour)
+	plans := 0
+
+	for plans < interestBatchSize {
+		ran, err := a.accrueNextPlan(ctx, cutoff)
+		if err != nil {
+			return plans, err
+		}
+		if !ran {
+			break
+		}
+		plans++
+	}
+
+	return plans, nil
+}
+
+// accrueNextPlan claims one plan with days before cutoff not accrued yet,
+// accrues them one by one and pays out every period that ends on the way,
+// all in one database transaction. A rejected payout, for example on a
+// frozen wallet, keeps the interest accrued and is retried with the next
+// day's accrual.
+func (a *Application) accrueNextPlan(ctx context.Context, cutoff time.Time) (bool, error) {
+	ran := false
+
+	err := a.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		plan, err := a.InterestRepo.ClaimDue(ctx, cutoff)
+		if errors.Is(err, entities.ErrInterestPlanNotFound) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error claiming interest plan: %w", err)
+		}
+
+		now := time.Now().UTC().Truncate(time.Microsecond)
+		payoutFailed := false
+
+		for day := plan.NextAccrualDay; day.Before(cutoff); day = day.AddDate(0, 0, 1) {
+			// Timestamps are stored with microsecond precision, so this is
+			// the end of the day.
+			balance, err := a.GetBalanceAt(ctx, plan.WalletId, day.AddDate(0, 0, 1).Add(-time.Microsecond))
+			if err != nil {
+				return fmt.Errorf("error getting balance for %s: %w", day.Format(time.DateOnly), err)
+			}
+
+			accrual := entities.InterestAccrual{
+				WalletId:  plan.WalletId,
+				Day:       day,
+				Balance:   balance.Balance,
+				Amount:    plan.DailyInterest(balance.Balance, day),
+				CreatedAt: now,
+			}
+			created, err := a.InterestRepo.CreateAccrual(ctx, accrual)
+			if err != nil {
+				return fmt.Errorf("error recording interest accrual: %w", err)
+			}
+			if created {
+				plan.Accrued = plan.Accrued.Add(accrual.Amount)
+			}
+			plan.NextAccrualDay = day.AddDate(0, 0, 1)
+
+			if payoutFailed || plan.NextAccrualDay.Before(plan.NextPayoutAt) {
+				continue
+			}
+			err = a.payInterest(ctx, &plan, plan.NextPayoutAt, now)
+			if errors.Is(err, entities.ErrInterestPayoutFailed) {
+				a.log.WarnContext(ctx, "error paying interest",
+					slog.String("wallet_id", plan.WalletId), slog.String("error", err.Error()))
+				payoutFailed = true
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			for !plan.NextPayoutAt.After(plan.NextAccrualDay) {
+				plan.NextPayoutAt = plan.PeriodAfter(plan.NextPayoutAt)
+			}
+		}
+
+		plan.UpdatedAt = now
+		_, err = a.InterestRepo.Save(ctx, plan)
+		if err != nil {
+			return fmt.Errorf("error saving interest plan: %w", err)
+		}
+
+		ran = true
+		return nil
+	})
+
+	return ran, err
+}
+
+// payInterest posts the rounded accrued interest to the wallet as an
+// INTEREST transaction and keeps the rounding remainder accrued. A rejected
+// transaction fails with ErrInterestPayoutFailed and changes nothing.
+func (a *Application) payInterest(ctx context.Context, plan *entities.InterestPlan, periodEnd, now time.Time) error {
+	amount, err := plan.Payable()
+	if err != nil {
+		return err
+	}
+	if !amount.IsPositive() {
+		return nil
+	}
+
+	payout := entities.InterestPayout{
+		ID:        uuid.NewString(),
+		WalletId:  plan.WalletId,
+		PeriodEnd: periodEnd,
+		Amount:    amount,
+		CreatedAt: now,
+	}
+
+	transaction, err := a.ProcessTransaction(ctx, entities.Transaction{
+		WalletId:      plan.WalletId,
+		OperationType: interest,
+		Currency:      plan.Currency,
+		Amount:        amount,
+		ReferenceId:   payout.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %w", entities.ErrInterestPayoutFailed, err)
+	}
+	payout.TransactionId = transaction.ID
+
+	_, err = a.InterestRepo.CreatePayout(ctx, payout)
+	if err != nil {
+		return fmt.Errorf("error recording interest payout: %w", err)
+	}
+
+	plan.Accrued = plan.Accrued.Sub(amount)
+	return nil
+}
+
+// RegisterInterestAccrual periodically accrues and pays out interest. A
+// zero interval disables the job.
+func RegisterInterestAccrual(lc fx.Lifecycle, app *Application) {
+	if app.interestInterval <= 0 {
+		return
+	}
+
+	runPeriodically(lc, app.interestInterval, func(ctx context.Context) {
+		plans, err := app.RunInterest(ctx, time.Now())
+		if err != nil {
+			app.log.ErrorContext(ctx, "error accruing interest", slog.String("error", err.Error()))
+		}
+		if plans > 0 {
+			app.log.InfoContext(ctx, "interest accrued", slog.Int("plans", plans))
+		}
+	})
+}
@@ -0,0 +1,355 @@
+package application
+
+import (
+	"TestProject/source/internal/entities"
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type mockInterestRepo struct {
+	mu       sync.Mutex
+	plans    map[string]entities.InterestPlan
+	accruals map[string]entities.InterestAccrual
+	payouts  []entities.InterestPayout
+}
+
+func newMockInterestRepo() *mockInterestRepo {
+	return &mockInterestRepo{
+		plans:    make(map[string]entities.InterestPlan),
+		accruals: make(map[string]entities.InterestAccrual),
+	}
+}
+
+func (m *mockInterestRepo) GetByWalletID(ctx context.Context, walletID string) (entities.InterestPlan, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	plan, ok := m.plans[walletID]
+	if !ok {
+		return entities.InterestPlan{}, entities.ErrInterestPlanNotFound
+	}
+	return plan, nil
+}
+
+func (m *mockInterestRepo) GetByWalletIDForUpdate(ctx context.Context, walletID string) (entities.InterestPlan, error) {
+	return m.GetByWalletID(ctx, walletID)
+}
+
+func (m *mockInterestRepo) ClaimDue(ctx context.Context, before time.Time) (entities.InterestPlan, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var due []entities.InterestPlan
+	for _, plan := range m.plans {
+		if plan.NextAccrualDay.Before(before) {
+			due = append(due, plan)
+		}
+	}
+	if len(due) == 0 {
+		return entities.InterestPlan{}, entities.ErrInterestPlanNotFound
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].NextAccrualDay.Before(due[j].NextAccrualDay) })
+	return due[0], nil
+}
+
+func (m *mockInterestRepo) Save(ctx context.Context, plan entities.InterestPlan) (entities.InterestPlan, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.plans[plan.WalletId] = plan
+	return plan, nil
+}
+
+func (m *mockInterestRepo) Delete(ctx context.Context, walletID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.plans[walletID]; !ok {
+		return entities.ErrInterestPlanNotFound
+	}
+	delete(m.plans, walletID)
+	return nil
+}
+
+func (m *mockInterestRepo) CreateAccrual(ctx context.Context, accrual entities.InterestAccrual) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := accrual.WalletId + "/" + accrual.Day.Format(time.DateOnly)
+	if _, ok := m.accruals[key]; ok {
+		return false, nil
+	}
+	m.accruals[key] = accrual
+	return true, nil
+}
+
+func (m *mockInterestRepo) CreatePayout(ctx context.Context, payout entities.InterestPayout) (entities.InterestPayout, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.payouts = append(m.payouts, payout)
+	return payout, nil
+}
+
+func (m *mockInterestRepo) ListPayouts(ctx context.Context, walletID string, limit int) ([]entities.InterestPayout, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var result []entities.InterestPayout
+	for i := len(m.payouts) - 1; i >= 0 && len(result) < limit; i-- {
+		if m.payouts[i].WalletId == walletID {
+			result = append(result, m.payouts[i])
+		}
+	}
+	return result, nil
+}
+
+func TestInterestPlan_DailyInterestAndPayable(t *testing.T) {
+	day := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	balance := dec("1000")
+
+	tests := []struct {
+		name     string
+		dayCount string
+		rounding string
+		accrued  string
+		daily    string
+		payable  string
+	}{
+		{"act/365 half up", entities.DayCountActual365, entities.RoundHalfUp, "0.125", "0.2739726027", "0.13"},
+		{"act/360 half even", entities.DayCountActual360, entities.RoundHalfEven, "0.125", "0.2777777778", "0.12"},
+		{"act/act leap year down", entities.DayCountActualActual, entities.RoundDown, "0.129", "0.2732240437", "0.12"},
+		{"act/365 up", entities.DayCountActual365, entities.RoundUp, "0.121", "0.2739726027", "0.13"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := entities.InterestPlan{
+				Currency:        "USD",
+				AnnualRate:      dec("10"),
+				DayCount:        tt.dayCount,
+				Rounding:        tt.rounding,
+				PayoutFrequency: entities.FrequencyMonthly,
+				Accrued:         dec(tt.accrued),
+			}
+			if err := plan.Validate(); err != nil {
+				t.Fatalf("unexpected validation error: %v", err)
+			}
+
+			daily := plan.DailyInterest(balance, day)
+			if !daily.Equal(dec(tt.daily)) {
+				t.Errorf("expected daily interest %s, got %s", tt.daily, daily.String())
+			}
+			payable, err := plan.Payable()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !payable.Equal(dec(tt.payable)) {
+				t.Errorf("expected payable %s, got %s", tt.payable, payable.String())
+			}
+		})
+	}
+
+	plan := entities.InterestPlan{AnnualRate: dec("10"), DayCount: entities.DayCountActual365}
+	if !plan.DailyInterest(dec("-100"), day).IsZero() {
+		t.Error("expected no interest on a negative balance")
+	}
+}
+
+func newInterestTestApplication(t *testing.T) (*Application, *mockWalletRepo, entities.Wallet) {
+	t.Helper()
+
+	repo := newMockWalletRepo()
+	app, _ := newTestApplication(repo)
+	app.log = slog.New(slog.DiscardHandler)
+
+	// 36500.00 at 10% over 365 days earns exactly 10.00 a day.
+	wallet := entities.NewWallet()
+	wallet.Currency = "USD"
+	wallet.Balance = dec("36500")
+	repo.wallets[wallet.ID] = wallet
+
+	journal := app.JournalRepo.(*mockJournalRepo)
+	journal.entries = append(journal.entries, walletEntry(wallet.ID, 36500, time.Date(2025, time.January, 1, 12, 0, 0, 0, time.UTC)))
+
+	return app, repo, wallet
+}
+
+func TestApplication_RunInterest(t *testing.T) {
+	app, repo, wallet := newInterestTestApplication(t)
+	interests := app.InterestRepo.(*mockInterestRepo)
+	ctx := context.Background()
+
+	interests.plans[wallet.ID] = entities.InterestPlan{
+		WalletId:        wallet.ID,
+		Currency:        "USD",
+		AnnualRate:      dec("10"),
+		DayCount:        entities.DayCountActual365,
+		Rounding:        entities.RoundHalfUp,
+		PayoutFrequency: entities.FrequencyMonthly,
+		NextAccrualDay:  time.Date(2025, time.January, 30, 0, 0, 0, 0, time.UTC),
+		NextPayoutAt:    time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	now := time.Date(2025, time.February, 3, 0, 30, 0, 0, time.UTC)
+	plans, err := app.RunInterest(ctx, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if plans != 1 {
+		t.Fatalf("expected 1 plan accrued, got %d", plans)
+	}
+
+	// January 30 and 31 are paid out on February 1, February 1 and 2 stay
+	// accrued until March 1.
+	if len(interests.accruals) != 4 {
+		t.Errorf("expected 4 accruals, got %d", len(interests.accruals))
+	}
+	if len(interests.payouts) != 1 || !interests.payouts[0].Amount.Equal(dec("20")) {
+		t.Fatalf("expected one payout of 20, got %+v", interests.payouts)
+	}
+	plan := interests.plans[wallet.ID]
+	if !plan.Accrued.Equal(dec("20")) {
+		t.Errorf("expected 20 accrued, got %s", plan.Accrued.String())
+	}
+	if !plan.NextAccrualDay.Equal(time.Date(2025, time.February, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected next accrual day February 3, got %s", plan.NextAccrualDay)
+	}
+	if !plan.NextPayoutAt.Equal(time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected next payout March 1, got %s", plan.NextPayoutAt)
+	}
+	if balance := repo.wallets[wallet.ID].Balance; !balance.Equal(dec("36520")) {
+		t.Errorf("expected balance 36520, got %s", balance.String())
+	}
+
+	balances, err := app.SystemAccountBalances(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, balance := range balances {
+		if balance.AccountId == entities.SystemInterest && !balance.Balance.Equal(dec("-20")) {
+			t.Errorf("expected -20 on %s, got %s", entities.SystemInterest, balance.Balance.String())
+		}
+	}
+
+	// Running again for the same or an earlier date changes nothing.
+	for _, again := range []time.Time{now, now.Add(-48 * time.Hour)} {
+		plans, err = app.RunInterest(ctx, again)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if plans != 0 {
+			t.Errorf("expected no plans on re-run for %s, got %d", again, plans)
+		}
+	}
+	if len(interests.payouts) != 1 || !interests.plans[wallet.ID].Accrued.Equal(dec("20")) {
+		t.Errorf("expected re-runs to change nothing, got %d payouts and %s accrued",
+			len(interests.payouts), interests.plans[wallet.ID].Accrued.String())
+	}
+
+	// A day that is accrued already is never counted twice, even if the
+	// plan state is behind.
+	plan = interests.plans[wallet.ID]
+	plan.NextAccrualDay = time.Date(2025, time.February, 2, 0, 0, 0, 0, time.UTC)
+	interests.plans[wallet.ID] = plan
+	_, err = app.RunInterest(ctx, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if accrued := interests.plans[wallet.ID].Accrued; !accrued.Equal(dec("20")) {
+		t.Errorf("expected 20 accrued, got %s", accrued.String())
+	}
+}
+
+func TestApplication_RunInterestRetriesRejectedPayout(t *testing.T) {
+	app, repo, wallet := newInterestTestApplication(t)
+	interests := app.InterestRepo.(*mockInterestRepo)
+	ctx := context.Background()
+
+	interests.plans[wallet.ID] = entities.InterestPlan{
+		WalletId:        wallet.ID,
+		Currency:        "USD",
+		AnnualRate:      dec("10"),
+		DayCount:        entities.DayCountActual365,
+		Rounding:        entities.RoundHalfUp,
+		PayoutFrequency: entities.FrequencyDaily,
+		NextAccrualDay:  time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC),
+		NextPayoutAt:    time.Date(2025, time.February, 2, 0, 0, 0, 0, time.UTC),
+	}
+
+	frozen := repo.wallets[wallet.ID]
+	frozen.Status = entities.WalletFrozen
+	frozen.FreezeMode = entities.FreezeAll
+	repo.wallets[wallet.ID] = frozen
+
+	_, err := app.RunInterest(ctx, time.Date(2025, time.February, 3, 1, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	plan := interests.plans[wallet.ID]
+	if len(interests.payouts) != 0 || !plan.Accrued.Equal(dec("20")) {
+		t.Fatalf("expected no payouts and 20 accrued, got %d payouts and %s", len(interests.payouts), plan.Accrued.String())
+	}
+	if !plan.NextPayoutAt.Equal(time.Date(2025, time.February, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the payout to stay due, got %s", plan.NextPayoutAt)
+	}
+
+	unfrozen := repo.wallets[wallet.ID]
+	unfrozen.Status = entities.WalletActive
+	unfrozen.FreezeMode = ""
+	repo.wallets[wallet.ID] = unfrozen
+
+	_, err = app.RunInterest(ctx, time.Date(2025, time.February, 4, 1, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	plan = interests.plans[wallet.ID]
+	if len(interests.payouts) != 1 || !interests.payouts[0].Amount.Equal(dec("30")) || !plan.Accrued.IsZero() {
+		t.Fatalf("expected one payout of 30 and nothing accrued, got %+v and %s", interests.payouts, plan.Accrued.String())
+	}
+	if !plan.NextPayoutAt.Equal(time.Date(2025, time.February, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected next payout February 5, got %s", plan.NextPayoutAt)
+	}
+}
+
+func TestApplication_SetInterestPlanKeepsAccrued(t *testing.T) {
+	app, _, wallet := newInterestTestApplication(t)
+	ctx := context.Background()
+
+	plan, err := app.SetInterestPlan(ctx, entities.InterestPlan{WalletId: wallet.ID, AnnualRate: dec("5")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if plan.Currency != "USD" || plan.DayCount != entities.DayCountActual365 || plan.PayoutFrequency != entities.FrequencyMonthly {
+		t.Errorf("expected USD plan with defaults, got %+v", plan)
+	}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	if !plan.NextAccrualDay.Equal(today) || plan.NextPayoutAt.Day() != 1 {
+		t.Errorf("expected accrual from today and payout on the 1st, got %s and %s", plan.NextAccrualDay, plan.NextPayoutAt)
+	}
+
+	plan.Accrued = decimal.RequireFromString("1.23")
+	app.InterestRepo.(*mockInterestRepo).plans[wallet.ID] = plan
+
+	plan, err = app.SetInterestPlan(ctx, entities.InterestPlan{WalletId: wallet.ID, AnnualRate: dec("6"), PayoutFrequency: entities.FrequencyDaily})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !plan.Accrued.Equal(dec("1.23")) || !plan.AnnualRate.Equal(dec("6")) {
+		t.Errorf("expected rate 6 with 1.23 accrued, got %s and %s", plan.AnnualRate.String(), plan.Accrued.String())
+	}
+	if !plan.NextPayoutAt.Equal(today.AddDate(0, 0, 1)) {
+		t.Errorf("expected daily payout tomorrow, got %s", plan.NextPayoutAt)
+	}
+
+	_, err = app.SetInterestPlan(ctx, entities.InterestPlan{WalletId: wallet.ID, AnnualRate: dec("6"), DayCount: "30/360"})
+	if err == nil {
+		t.Error("expected an error for an unknown day count")
+	}
+}
@@ -0,0 +1,91 @@
+package application
+
+import (
+	"TestProject/source/internal/entities"
+	"context"
+	"fmt"
+)
+
+// counterparties maps single-wallet operations to the system account on the
+// other side of the journal entry.
+var counterparties = map[string]string{
+	deposit:          entities.SystemCashIn,
+	withdraw:         entities.SystemCashOut,
+	capture:          entities.SystemSettlement,
+	interest:         entities.SystemInterest,
+	depositReversal:  entities.SystemCashIn,
+	withdrawReversal: entities.SystemCashOut,
+}
+
+// walletCredits reports whether the operation adds money to the wallet.
+func walletCredits(operationType string) bool {
+	return operationType == deposit || operationType == withdrawReversal || operationType == transferIn || operationType == interest
+}
+
+// singleWalletEntry builds the entry for an operation that moves money
+// between one wallet and its system counterparty. A fee is a separate pair
+// of postings from the wallet to the fee revenue account; a negative fee
+// refunds it back to the wallet.
+func singleWalletEntry(transaction entities.Transaction) (entities.JournalEntry, error) {
+	account, ok := counterparties[transaction.OperationType]
+	if !ok {
+		return entities.JournalEntry{}, fmt.Errorf("%w: %s", entities.ErrInvalidOperation, transaction.OperationType)
+	}
+
+	amount := transaction.Amount
+	if !walletCredits(transaction.OperationType) {
+		amount = amount.Neg()
+	}
+
+	postings := []entities.Posting{
+		entities.WalletPosting(transaction.WalletId, transaction.Currency, amount),
+		entities.SystemPosting(account, transaction.Currency, amount.Neg()),
+	}
+	if !transaction.Fee.IsZero() {
+		postings = append(postings,
+			entities.WalletPosting(transaction.WalletId, transaction.Currency, transaction.Fee.Neg()),
+			entities.SystemPosting(entities.SystemFees, transaction.Currency, transaction.Fee),
+		)
+	}
+
+	return entities.JournalEntry{
+		ID:            transaction.ID,
+		OperationType: transaction.OperationType,
+		Postings:      postings,
+		CreatedAt:     transaction.CreatedAt,
+	}, nil
+}
+
+// applyJournal validates the entry and applies its wallet postings to the
+// locked wallets. It is the only place that changes wallet balances.
+func applyJournal(entry entities.JournalEntry, wallets map[string]*entities.Wallet) error {
+	err := entry.Validate()
+	if err != nil {
+		return err
+	}
+
+	for _, posting := range entry.Postings {
+		if posting.AccountType != entities.AccountWallet {
+			continue
+		}
+		w, ok := wallets[posting.AccountId]
+		if !ok {
+			return fmt.Errorf("%w: wallet %s is not locked", entities.ErrUnbalancedJournal, posting.AccountId)
+		}
+		if posting.Currency != w.Currency {
+			return fmt.Errorf("%w: wallet is %s, got %s", entities.ErrCurrencyMismatch, w.Currency, posting.Currency)
+		}
+		w.Balance = w.Balance.Add(posting.Amount)
+	}
+	return nil
+}
+
+// SystemAccountBalances returns the balance of every system account that has
+// postings. Together with the wallet balances they always sum to zero.
+func (a *Application) SystemAccountBalances(ctx context.Context) ([]entities.AccountBalance, error) {
+	balances, err := a.JournalRepo.Balances(ctx, entities.AccountSystem)
+	if err != nil {
+		return nil, fmt.Errorf("error getting system account balances: %w", err)
+	}
+	return balances, nil
+}
@@ -0,0 +1,209 @@
+package application
+
+import (
+	"TestProject/source/internal/entities"
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type mockJournalRepo struct {
+	mu      sync.Mutex
+	entries []entities.JournalEntry
+	wallets *mockWalletRepo
+}
+
+func newMockJournalRepo(wallets *mockWalletRepo) *mockJournalRepo {
+	return &mockJournalRepo{wallets: wallets}
+}
+
+func (m *mockJournalRepo) Create(ctx context.Context, entry entities.JournalEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries = append(m.entries, entry)
+	return nil
+}
+
+func (m *mockJournalRepo) Balances(ctx context.Context, accountType string) ([]entities.AccountBalance, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	index := make(map[string]int)
+	var result []entities.AccountBalance
+	for _, entry := range m.entries {
+		for _, posting := range entry.Postings {
+			if posting.AccountType != accountType {
+				continue
+			}
+			key := posting.AccountId + "/" + posting.Currency
+			i, ok := index[key]
+			if !ok {
+				i = len(result)
+				index[key] = i
+				result = append(result, entities.AccountBalance{
+					AccountType: posting.AccountType,
+					AccountId:   posting.AccountId,
+					Currency:    posting.Currency,
+				})
+			}
+			result[i].Balance = result[i].Balance.Add(posting.Amount)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockJournalRepo) WalletPostingsSum(ctx context.Context, walletID string, from, to time.Time) (decimal.Decimal, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sum := decimal.Zero
+	for _, entry := range m.entries {
+		if entry.CreatedAt.Before(from) || entry.CreatedAt.After(to) {
+			continue
+		}
+		for _, posting := range entry.Postings {
+			if posting.AccountType == entities.AccountWallet && posting.AccountId == walletID {
+				sum = sum.Add(posting.Amount)
+			}
+		}
+	}
+	return sum, nil
+}
+
+func (m *mockJournalRepo) WalletBalanceChecks(ctx context.Context, afterID string, limit int) ([]entities.BalanceCheck, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.wallets.mu.RLock()
+	defer m.wallets.mu.RUnlock()
+
+	ids := make([]string, 0, len(m.wallets.wallets))
+	for id := range m.wallets.wallets {
+		if id > afterID {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+
+	result := make([]entities.BalanceCheck, 0, len(ids))
+	for _, id := range ids {
+		wallet := m.wallets.wallets[id]
+		check := entities.BalanceCheck{WalletId: id, Currency: wallet.Currency, Balance: wallet.Balance}
+		for _, entry := range m.entries {
+			for _, posting := range entry.Postings {
+				if posting.AccountType == entities.AccountWallet && posting.AccountId == id {
+					check.LedgerBalance = check.LedgerBalance.Add(posting.Amount)
+				}
+			}
+		}
+		result = append(result, check)
+	}
+	return result, nil
+}
+
+func TestApplication_Journal_BooksBalance(t *testing.T) {
+	repo := newMockWalletRepo()
+	app, _ := newTestApplication(repo)
+	journal := app.JournalRepo.(*mockJournalRepo)
+	ctx := context.Background()
+
+	from := entities.NewWallet()
+	from.Balance = decimal.NewFromInt(100)
+	from, err := app.CreateWallet(ctx, from)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	to, err := app.CreateWallet(ctx, entities.NewWallet())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	depositTx, err := app.ProcessTransaction(ctx, entities.Transaction{
+		WalletId: from.ID, OperationType: "DEPOSIT", Amount: decimal.NewFromInt(50),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = app.ProcessTransaction(ctx, entities.Transaction{
+		WalletId: from.ID, OperationType: "WITHDRAW", Amount: decimal.NewFromInt(30),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	transfer := entities.NewTransfer()
+	transfer.FromWalletId = from.ID
+	transfer.ToWalletId = to.ID
+	transfer.Amount = decimal.NewFromInt(20)
+	_, err = app.Transfer(ctx, transfer)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	partial := decimal.NewFromInt(10)
+	_, err = app.ReverseTransaction(ctx, depositTx.ID, &partial, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(journal.entries) != 5 {
+		t.Fatalf("expected 5 journal entries, got %d", len(journal.entries))
+	}
+	for _, entry := range journal.entries {
+		if err := entry.Validate(); err != nil {
+			t.Errorf("entry %s %s: %v", entry.OperationType, entry.ID, err)
+		}
+	}
+
+	total := decimal.Zero
+	for _, wallet := range repo.wallets {
+		total = total.Add(wallet.Balance)
+	}
+	system, err := app.SystemAccountBalances(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, account := range system {
+		total = total.Add(account.Balance)
+	}
+	if !total.IsZero() {
+		t.Errorf("expected wallets and system accounts to sum to zero, got %s", total.String())
+	}
+	if !repo.wallets[from.ID].Balance.Equal(decimal.NewFromInt(90)) {
+		t.Errorf("expected from balance 90, got %s", repo.wallets[from.ID].Balance.String())
+	}
+}
+
+func TestApplyJournal_RejectsUnbalanced(t *testing.T) {
+	wallet := entities.NewWallet()
+	wallet.Balance = decimal.NewFromInt(100)
+
+	entry := entities.JournalEntry{
+		ID:            "entry",
+		OperationType: "DEPOSIT",
+		Postings: []entities.Posting{
+			entities.WalletPosting(wallet.ID, wallet.Currency, decimal.NewFromInt(50)),
+			entities.SystemPosting(entities.SystemCashIn, wallet.Currency, decimal.NewFromInt(-40)),
+		},
+	}
+
+	err := applyJournal(entry, map[string]*entities.Wallet{wallet.ID: &wallet})
+	if !errors.Is(err, entities.ErrUnbalancedJournal) {
+		t.Fatalf("expected ErrUnbalancedJournal, got %v", err)
+	}
+	if !wallet.Balance.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("expected balance to stay 100, got %s", wallet.Balance.String())
+	}
+
+	entry.Postings = entry.Postings[:1]
+	err = applyJournal(entry, map[string]*entities.Wallet{wallet.ID: &wallet})
+	if !errors.Is(err, entities.ErrUnbalancedJournal) {
+		t.Fatalf("expected ErrUnbalancedJournal for a single posting, got %v", err)
+	}
+}
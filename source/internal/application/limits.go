@@ -0,0 +1,129 @@
+package application
+
+import (
+	"TestProject/source/config"
+	"TestProject/source/internal/entities"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// limitedOperations are the outgoing operations counted against velocity
+// limits. Active holds count as well, since they become captures.
+var limitedOperations = []string{withdraw, transferOut, capture}
+
+func defaultLimits(conf config.LimitsConfig) entities.VelocityLimits {
+	return entities.VelocityLimits{
+		PerOperation:  conf.PerOperation,
+		DailyAmount:   conf.DailyAmount,
+		DailyCount:    conf.DailyCount,
+		MonthlyAmount: conf.MonthlyAmount,
+		MonthlyCount:  conf.MonthlyCount,
+	}
+}
+
+// checkVelocity fails with a LimitExceededError when taking amount from the
+// wallet at now would break one of its limits. Callers hold the wallet lock,
+// so concurrent operations on the wallet see each other's usage. A capture
+// passes its hold as exceptHoldID, so that the hold is not counted next to
+// the capture itself.
+func (a *Application) checkVelocity(ctx context.Context, walletID string, amount decimal.Decimal, now time.Time, exceptHoldID string) error {
+	limits, err := a.effectiveLimits(ctx, walletID)
+	if err != nil {
+		return err
+	}
+	if limits.Unlimited() {
+		return nil
+	}
+
+	now = now.UTC()
+	daily, err := a.outgoingUsage(ctx, walletID, now.Add(-24*time.Hour), exceptHoldID)
+	if err != nil {
+		return fmt.Errorf("error getting daily usage: %w", err)
+	}
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	monthly, err := a.outgoingUsage(ctx, walletID, monthStart, exceptHoldID)
+	if err != nil {
+		return fmt.Errorf("error getting monthly usage: %w", err)
+	}
+
+	return limits.Check(amount, daily, monthly)
+}
+
+// outgoingUsage adds the active holds created since to the limited
+// operations, so that holding funds and capturing them later cannot get
+// around the limits.
+func (a *Application) outgoingUsage(ctx context.Context, walletID string, since time.Time, exceptHoldID string) (entities.Usage, error) {
+	usage, err := a.TransactionRepo.Usage(ctx, walletID, limitedOperations, since)
+	if err != nil {
+		return entities.Usage{}, err
+	}
+	held, err := a.HoldRepo.Usage(ctx, walletID, since, exceptHoldID)
+	if err != nil {
+		return entities.Usage{}, err
+	}
+
+	return entities.Usage{Amount: usage.Amount.Add(held.Amount), Count: usage.Count + held.Count}, nil
+}
+
+// effectiveLimits returns the wallet's own limits or the defaults.
+func (a *Application) effectiveLimits(ctx context.Context, walletID string) (entities.VelocityLimits, error) {
+	limits, err := a.LimitRepo.GetByWalletID(ctx, walletID)
+	if errors.Is(err, entities.ErrLimitsNotFound) {
+		limits = a.defaultLimits
+		limits.WalletId = walletID
+		return limits, nil
+	}
+	if err != nil {
+		return entities.VelocityLimits{}, fmt.Errorf("error getting wallet limits: %w", err)
+	}
+	return limits, nil
+}
+
+// GetWalletLimits returns the limits that apply to the wallet.
+func (a *Application) GetWalletLimits(ctx context.Context, walletID string) (entities.VelocityLimits, error) {
+	_, err := a.WalletRepo.GetByID(ctx, walletID)
+	if err != nil {
+		return entities.VelocityLimits{}, err
+	}
+	return a.effectiveLimits(ctx, walletID)
+}
+
+// SetWalletLimits replaces the defaults for one wallet.
+func (a *Application) SetWalletLimits(ctx context.Context, limits entities.VelocityLimits) (entities.VelocityLimits, error) {
+	wallet, err := a.WalletRepo.GetByID(ctx, limits.WalletId)
+	if err != nil {
+		return entities.VelocityLimits{}, err
+	}
+
+	for _, amount := range []decimal.Decimal{limits.PerOperation, limits.DailyAmount, limits.MonthlyAmount} {
+		if amount.IsNegative() {
+			return entities.VelocityLimits{}, fmt.Errorf("%w: limit cannot be negative", entities.ErrInvalidAmount)
+		}
+		err = entities.ValidateAmount(amount, wallet.Currency)
+		if err != nil {
+			return entities.VelocityLimits{}, err
+		}
+	}
+	if limits.DailyCount < 0 || limits.MonthlyCount < 0 {
+		return entities.VelocityLimits{}, fmt.Errorf("%w: limit cannot be negative", entities.ErrInvalidAmount)
+	}
+
+	limits, err = a.LimitRepo.Save(ctx, limits)
+	if err != nil {
+		return entities.VelocityLimits{}, fmt.Errorf("error saving wallet limits: %w", err)
+	}
+	return limits, nil
+}
+
+// ResetWalletLimits makes the wallet use the default limits again.
+func (a *Application) ResetWalletLimits(ctx context.Context, walletID string) (entities.VelocityLimits, error) {
+	err := a.LimitRepo.Delete(ctx, walletID)
+	if err != nil {
+		return entities.VelocityLimits{}, fmt.Errorf("error deleting wallet limits: %w", err)
+	}
+	return a.GetWalletLimits(ctx, walletID)
+}
@@ -0,0 +1,253 @@
+package application
+
+import (
+	"TestProject/source/internal/entities"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+type mockLimitRepo struct {
+	mu     sync.Mutex
+	limits map[string]entities.VelocityLimits
+}
+
+func newMockLimitRepo() *mockLimitRepo {
+	return &mockLimitRepo{limits: make(map[string]entities.VelocityLimits)}
+}
+
+func (m *mockLimitRepo) GetByWalletID(ctx context.Context, walletID string) (entities.VelocityLimits, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	limits, ok := m.limits[walletID]
+	if !ok {
+		return entities.VelocityLimits{}, entities.ErrLimitsNotFound
+	}
+	return limits, nil
+}
+
+func (m *mockLimitRepo) Save(ctx context.Context, limits entities.VelocityLimits) (entities.VelocityLimits, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.limits[limits.WalletId] = limits
+	return limits, nil
+}
+
+func (m *mockLimitRepo) Delete(ctx context.Context, walletID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.limits, walletID)
+	return nil
+}
+
+func withdrawTx(walletID string, amount int64) entities.Transaction {
+	return entities.Transaction{WalletId: walletID, OperationType: "WITHDRAW", Amount: decimal.NewFromInt(amount)}
+}
+
+func TestApplication_VelocityLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		limits  entities.VelocityLimits
+		amounts []int64
+		limit   string
+	}{
+		{
+			name:    "per operation",
+			limits:  entities.VelocityLimits{PerOperation: decimal.NewFromInt(100)},
+			amounts: []int64{100, 101},
+			limit:   entities.LimitPerOperation,
+		},
+		{
+			name:    "daily amount",
+			limits:  entities.VelocityLimits{DailyAmount: decimal.NewFromInt(250)},
+			amounts: []int64{100, 150, 1},
+			limit:   entities.LimitDailyAmount,
+		},
+		{
+			name:    "daily count",
+			limits:  entities.VelocityLimits{DailyCount: 2},
+			amounts: []int64{1, 1, 1},
+			limit:   entities.LimitDailyCount,
+		},
+		{
+			name:    "monthly amount",
+			limits:  entities.VelocityLimits{MonthlyAmount: decimal.NewFromInt(300)},
+			amounts: []int64{200, 200},
+			limit:   entities.LimitMonthlyAmount,
+		},
+		{
+			name:    "monthly count",
+			limits:  entities.VelocityLimits{MonthlyCount: 1},
+			amounts: []int64{1, 1},
+			limit:   entities.LimitMonthlyCount,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockWalletRepo()
+			app, _ := newTestApplication(repo)
+			ctx := context.Background()
+
+			wallet := entities.NewWallet()
+			wallet.Balance = decimal.NewFromInt(10000)
+			repo.wallets[wallet.ID] = wallet
+
+			tt.limits.WalletId = wallet.ID
+			_, err := app.SetWalletLimits(ctx, tt.limits)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			last := len(tt.amounts) - 1
+			for i, amount := range tt.amounts[:last] {
+				_, err = app.ProcessTransaction(ctx, withdrawTx(wallet.ID, amount))
+				if err != nil {
+					t.Fatalf("withdraw %d: unexpected error: %v", i, err)
+				}
+			}
+
+			_, err = app.ProcessTransaction(ctx, withdrawTx(wallet.ID, tt.amounts[last]))
+			if !errors.Is(err, entities.ErrLimitExceeded) {
+				t.Fatalf("expected ErrLimitExceeded, got %v", err)
+			}
+			var limitErr *entities.LimitExceededError
+			if !errors.As(err, &limitErr) || limitErr.Limit != tt.limit {
+				t.Errorf("expected %s limit, got %v", tt.limit, err)
+			}
+		})
+	}
+}
+
+func TestApplication_VelocityLimits_DefaultsAndTransfers(t *testing.T) {
+	repo := newMockWalletRepo()
+	app, _ := newTestApplication(repo)
+	app.defaultLimits = entities.VelocityLimits{DailyAmount: decimal.NewFromInt(100)}
+	ctx := context.Background()
+
+	from := entities.NewWallet()
+	from.Balance = decimal.NewFromInt(1000)
+	to := entities.NewWallet()
+	repo.wallets[from.ID] = from
+	repo.wallets[to.ID] = to
+
+	_, err := app.ProcessTransaction(ctx, withdrawTx(from.ID, 60))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	transfer := entities.NewTransfer()
+	transfer.FromWalletId = from.ID
+	transfer.ToWalletId = to.ID
+	transfer.Amount = decimal.NewFromInt(50)
+	_, err = app.Transfer(ctx, transfer)
+	if !errors.Is(err, entities.ErrLimitExceeded) {
+		t.Fatalf("expected transfer to count against the default daily limit, got %v", err)
+	}
+
+	// Incoming money is not limited.
+	_, err = app.ProcessTransaction(ctx, entities.Transaction{
+		WalletId: from.ID, OperationType: "DEPOSIT", Amount: decimal.NewFromInt(500),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = app.SetWalletLimits(ctx, entities.VelocityLimits{WalletId: from.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = app.Transfer(ctx, transfer)
+	if err != nil {
+		t.Fatalf("expected wallet without limits to transfer, got %v", err)
+	}
+}
+
+func TestApplication_VelocityLimits_Holds(t *testing.T) {
+	newHold := func(walletID string, amount int64) entities.Hold {
+		hold := entities.NewHold()
+		hold.WalletId = walletID
+		hold.Amount = decimal.NewFromInt(amount)
+		return hold
+	}
+	setLimits := func(t *testing.T, app *Application, limits entities.VelocityLimits) {
+		t.Helper()
+		_, err := app.SetWalletLimits(context.Background(), limits)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	expectLimit := func(t *testing.T, err error, limit string) {
+		t.Helper()
+		var limitErr *entities.LimitExceededError
+		if !errors.As(err, &limitErr) || limitErr.Limit != limit {
+			t.Fatalf("expected %s limit, got %v", limit, err)
+		}
+	}
+
+	t.Run("hold over the per operation limit", func(t *testing.T) {
+		app, _, _, wallet := newHoldTestApplication(1000)
+		setLimits(t, app, entities.VelocityLimits{WalletId: wallet.ID, PerOperation: decimal.NewFromInt(100)})
+
+		_, err := app.CreateHold(context.Background(), newHold(wallet.ID, 101))
+		expectLimit(t, err, entities.LimitPerOperation)
+	})
+
+	t.Run("open holds count against the daily amount", func(t *testing.T) {
+		app, _, _, wallet := newHoldTestApplication(1000)
+		ctx := context.Background()
+		setLimits(t, app, entities.VelocityLimits{WalletId: wallet.ID, DailyAmount: decimal.NewFromInt(100)})
+
+		_, err := app.CreateHold(ctx, newHold(wallet.ID, 60))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_, err = app.CreateHold(ctx, newHold(wallet.ID, 60))
+		expectLimit(t, err, entities.LimitDailyAmount)
+		_, err = app.ProcessTransaction(ctx, withdrawTx(wallet.ID, 60))
+		expectLimit(t, err, entities.LimitDailyAmount)
+	})
+
+	t.Run("capture counts once and then as a capture", func(t *testing.T) {
+		app, _, _, wallet := newHoldTestApplication(1000)
+		ctx := context.Background()
+		setLimits(t, app, entities.VelocityLimits{WalletId: wallet.ID, DailyAmount: decimal.NewFromInt(100)})
+
+		hold, err := app.CreateHold(ctx, newHold(wallet.ID, 80))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_, _, err = app.CaptureHold(ctx, hold.ID, nil)
+		if err != nil {
+			t.Fatalf("unexpected error capturing within the limit: %v", err)
+		}
+		_, err = app.ProcessTransaction(ctx, withdrawTx(wallet.ID, 30))
+		expectLimit(t, err, entities.LimitDailyAmount)
+	})
+
+	t.Run("capture over a lowered limit", func(t *testing.T) {
+		app, _, _, wallet := newHoldTestApplication(1000)
+		ctx := context.Background()
+
+		hold, err := app.CreateHold(ctx, newHold(wallet.ID, 500))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		setLimits(t, app, entities.VelocityLimits{WalletId: wallet.ID, PerOperation: decimal.NewFromInt(100)})
+
+		_, _, err = app.CaptureHold(ctx, hold.ID, nil)
+		expectLimit(t, err, entities.LimitPerOperation)
+
+		partial := decimal.NewFromInt(100)
+		_, _, err = app.CaptureHold(ctx, hold.ID, &partial)
+		if err != nil {
+			t.Fatalf("unexpected error capturing within the limit: %v", err)
+		}
+	})
+}
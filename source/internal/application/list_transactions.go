@@ -0,0 +1,44 @@
+package application
+
+import (
+	"TestProject/source/internal/entities"
+	"context"
+	"fmt"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 100
+)
+
+func (a *Application) ListTransactions(ctx context.Context, filter entities.TransactionFilter) (entities.TransactionPage, error) {
+	_, err := a.WalletRepo.GetByID(ctx, filter.WalletId)
+	if err != nil {
+		return entities.TransactionPage{}, fmt.Errorf("error getting wallet: %w", err)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	// One extra row tells whether there is a next page.
+	filter.Limit = limit + 1
+
+	transactions, err := a.TransactionRepo.List(ctx, filter)
+	if err != nil {
+		return entities.TransactionPage{}, fmt.Errorf("error listing transactions: %w", err)
+	}
+
+	page := entities.TransactionPage{Transactions: transactions}
+	if len(transactions) > limit {
+		page.Transactions = transactions[:limit]
+		last := page.Transactions[limit-1]
+		page.NextCursor = &entities.TransactionCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+
+	return page, nil
+}
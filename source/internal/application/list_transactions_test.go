@@ -0,0 +1,73 @@
+package application
+
+import (
+	"TestProject/source/internal/entities"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestApplication_ListTransactions_Paginates(t *testing.T) {
+	repo := newMockWalletRepo()
+	app, ledger := newTestApplication(repo)
+	ctx := context.Background()
+
+	wallet := entities.NewWallet()
+	repo.wallets[wallet.ID] = wallet
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		transaction := entities.NewTransaction()
+		transaction.WalletId = wallet.ID
+		transaction.OperationType = "DEPOSIT"
+		transaction.Amount = decimal.NewFromInt(int64(i + 1))
+		transaction.CreatedAt = start.Add(time.Duration(i) * time.Minute)
+		ledger.transactions = append(ledger.transactions, transaction)
+	}
+
+	first, err := app.ListTransactions(ctx, entities.TransactionFilter{WalletId: wallet.ID, Limit: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(first.Transactions) != 2 {
+		t.Fatalf("expected 2 transactions, got %d", len(first.Transactions))
+	}
+	if !first.Transactions[0].Amount.Equal(decimal.NewFromInt(5)) {
+		t.Errorf("expected newest transaction first, got amount %s", first.Transactions[0].Amount.String())
+	}
+	if first.NextCursor == nil {
+		t.Fatal("expected next cursor")
+	}
+
+	second, err := app.ListTransactions(ctx, entities.TransactionFilter{WalletId: wallet.ID, Limit: 2, Cursor: first.NextCursor})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(second.Transactions) != 2 || !second.Transactions[0].Amount.Equal(decimal.NewFromInt(3)) {
+		t.Fatalf("unexpected second page: %+v", second.Transactions)
+	}
+
+	last, err := app.ListTransactions(ctx, entities.TransactionFilter{WalletId: wallet.ID, Limit: 2, Cursor: second.NextCursor})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(last.Transactions) != 1 {
+		t.Fatalf("expected 1 transaction on last page, got %d", len(last.Transactions))
+	}
+	if last.NextCursor != nil {
+		t.Error("expected no next cursor on last page")
+	}
+}
+
+func TestApplication_ListTransactions_WalletNotFound(t *testing.T) {
+	repo := newMockWalletRepo()
+	app, _ := newTestApplication(repo)
+
+	_, err := app.ListTransactions(context.Background(), entities.TransactionFilter{WalletId: "non-existent-id"})
+	if !errors.Is(err, entities.ErrWalletNotFound) {
+		t.Fatalf("expected ErrWalletNotFound, got %v", err)
+	}
+}
@@ -1,10 +1,14 @@
 package application
 
 import (
+	"TestProject/source/config"
 	"TestProject/source/internal/entities"
 	"context"
 	"log/slog"
+	"net/http"
+	"time"
 
+	"github.com/shopspring/decimal"
 	"go.uber.org/fx"
 )
 
@@ -15,6 +19,16 @@ var Module = fx.Module(
 	fx.Provide(
 		New,
 	),
+	fx.Invoke(
+		RegisterIdempotencyKeyCleanup,
+		RegisterHoldExpiry,
+		RegisterReconciliation,
+		RegisterScheduleWorker,
+		RegisterBalanceSnapshots,
+		RegisterInterestAccrual,
+		RegisterOutboxRelay,
+		RegisterWebhookDelivery,
+	),
 	fx.Decorate(
 		func(log *slog.Logger) *slog.Logger {
 			return log.With(slog.String("module", moduleName))
@@ -23,24 +37,235 @@ var Module = fx.Module(
 )
 
 type Application struct {
-	log        *slog.Logger
-	WalletRepo WalletRepo
+	log                    *slog.Logger
+	idempotencyTTL         time.Duration
+	holdConf               config.HoldConfig
+	reconciliationInterval time.Duration
+	defaultLimits          entities.VelocityLimits
+	schedulePollInterval   time.Duration
+	snapshotInterval       time.Duration
+	interestInterval       time.Duration
+	outboxConf             config.OutboxConfig
+	webhookConf            config.WebhookConfig
+	clock                  func() time.Time // time.Now unless a test replaces it
+	Transactor             Transactor
+	WalletRepo             WalletRepo
+	TransactionRepo        TransactionRepo
+	IdempotencyRepo        IdempotencyRepo
+	HoldRepo               HoldRepo
+	JournalRepo            JournalRepo
+	LimitRepo              LimitRepo
+	ScheduleRepo           ScheduleRepo
+	SnapshotRepo           SnapshotRepo
+	FeeRepo                FeeRepo
+	InterestRepo           InterestRepo
+	CustomerRepo           CustomerRepo
+	OutboxRepo             OutboxRepo
+	Publisher              EventPublisher
+	WebhookRepo            WebhookRepo
+	WebhookClient          WebhookClient
+	Subscriber             EventSubscriber
 }
 
 func New(
 	log *slog.Logger,
+	idempotencyConf config.IdempotencyConfig,
+	holdConf config.HoldConfig,
+	reconciliationConf config.ReconciliationConfig,
+	limitsConf config.LimitsConfig,
+	scheduleConf config.ScheduleConfig,
+	snapshotConf config.SnapshotConfig,
+	interestConf config.InterestConfig,
+	outboxConf config.OutboxConfig,
+	webhookConf config.WebhookConfig,
+	transactor Transactor,
 	walletRepo WalletRepo,
+	transactionRepo TransactionRepo,
+	idempotencyRepo IdempotencyRepo,
+	holdRepo HoldRepo,
+	journalRepo JournalRepo,
+	limitRepo LimitRepo,
+	scheduleRepo ScheduleRepo,
+	snapshotRepo SnapshotRepo,
+	feeRepo FeeRepo,
+	interestRepo InterestRepo,
+	customerRepo CustomerRepo,
+	outboxRepo OutboxRepo,
+	publisher EventPublisher,
+	webhookRepo WebhookRepo,
+	webhookClient WebhookClient,
+	subscriber EventSubscriber,
 ) *Application {
 	return &Application{
-		log:        log,
-		WalletRepo: walletRepo,
+		log:                    log,
+		idempotencyTTL:         idempotencyConf.KeyTTL,
+		holdConf:               holdConf,
+		reconciliationInterval: reconciliationConf.Interval,
+		defaultLimits:          defaultLimits(limitsConf),
+		schedulePollInterval:   scheduleConf.PollInterval,
+		snapshotInterval:       snapshotConf.Interval,
+		interestInterval:       interestConf.Interval,
+		outboxConf:             outboxConf,
+		webhookConf:            webhookConf,
+		clock:                  time.Now,
+		Transactor:             transactor,
+		WalletRepo:             walletRepo,
+		TransactionRepo:        transactionRepo,
+		IdempotencyRepo:        idempotencyRepo,
+		HoldRepo:               holdRepo,
+		JournalRepo:            journalRepo,
+		LimitRepo:              limitRepo,
+		ScheduleRepo:           scheduleRepo,
+		SnapshotRepo:           snapshotRepo,
+		FeeRepo:                feeRepo,
+		InterestRepo:           interestRepo,
+		CustomerRepo:           customerRepo,
+		OutboxRepo:             outboxRepo,
+		Publisher:              publisher,
+		WebhookRepo:            webhookRepo,
+		WebhookClient:          webhookClient,
+		Subscriber:             subscriber,
 	}
 }
 
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+	WithinSavepoint(ctx context.Context, fn func(ctx context.Context) error) error
+	InTransaction(ctx context.Context) bool
+}
+
 type WalletRepo interface {
 	Create(ctx context.Context, wallet entities.Wallet) (entities.Wallet, error)
 	GetByID(ctx context.Context, walletID string) (entities.Wallet, error)
 	GetByIDForUpdate(ctx context.Context, walletID string) (entities.Wallet, error)
+	GetByIDForShare(ctx context.Context, walletID string) (entities.Wallet, error)
 	Update(ctx context.Context, wallet entities.Wallet) (entities.Wallet, error)
 	UpdateWithLock(ctx context.Context, walletID string, updateFn func(*entities.Wallet) error) (entities.Wallet, error)
+	UpdateManyWithLock(ctx context.Context, walletIDs []string, updateFn func(map[string]*entities.Wallet) error) (map[string]entities.Wallet, error)
+	LockMany(ctx context.Context, walletIDs []string) error
+	ListByOwner(ctx context.Context, ownerID string) ([]entities.Wallet, error)
+}
+
+type TransactionRepo interface {
+	Create(ctx context.Context, transaction entities.Transaction) (entities.Transaction, error)
+	GetByID(ctx context.Context, transactionID string) (entities.Transaction, error)
+	SumByReference(ctx context.Context, referenceID, operationType string) (decimal.Decimal, error)
+	List(ctx context.Context, filter entities.TransactionFilter) ([]entities.Transaction, error)
+	Usage(ctx context.Context, walletID string, operationTypes []string, since time.Time) (entities.Usage, error)
+	Stream(ctx context.Context, walletID string, from, to time.Time, fn func(entities.Transaction) error) error
+}
+
+type IdempotencyRepo interface {
+	Acquire(ctx context.Context, record entities.IdempotencyRecord) (entities.IdempotencyRecord, error)
+	Complete(ctx context.Context, record entities.IdempotencyRecord) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+type HoldRepo interface {
+	Create(ctx context.Context, hold entities.Hold) (entities.Hold, error)
+	GetByID(ctx context.Context, holdID string) (entities.Hold, error)
+	Update(ctx context.Context, hold entities.Hold) (entities.Hold, error)
+	ListExpired(ctx context.Context, now time.Time, limit int) ([]entities.Hold, error)
+	Usage(ctx context.Context, walletID string, since time.Time, exceptID string) (entities.Usage, error)
+}
+
+type JournalRepo interface {
+	Create(ctx context.Context, entry entities.JournalEntry) error
+	Balances(ctx context.Context, accountType string) ([]entities.AccountBalance, error)
+	WalletBalanceChecks(ctx context.Context, afterID string, limit int) ([]entities.BalanceCheck, error)
+	WalletPostingsSum(ctx context.Context, walletID string, from, to time.Time) (decimal.Decimal, error)
+}
+
+type LimitRepo interface {
+	GetByWalletID(ctx context.Context, walletID string) (entities.VelocityLimits, error)
+	Save(ctx context.Context, limits entities.VelocityLimits) (entities.VelocityLimits, error)
+	Delete(ctx context.Context, walletID string) error
+}
+
+type ScheduleRepo interface {
+	Create(ctx context.Context, schedule entities.Schedule) (entities.Schedule, error)
+	GetByID(ctx context.Context, scheduleID string) (entities.Schedule, error)
+	GetByIDForUpdate(ctx context.Context, scheduleID string) (entities.Schedule, error)
+	ClaimDue(ctx context.Context, now time.Time) (entities.Schedule, error)
+	Update(ctx context.Context, schedule entities.Schedule) (entities.Schedule, error)
+	CreateRun(ctx context.Context, run entities.ScheduleRun) (entities.ScheduleRun, error)
+	ListRuns(ctx context.Context, scheduleID string, limit int) ([]entities.ScheduleRun, error)
+}
+
+type SnapshotRepo interface {
+	TakeDay(ctx context.Context, day, from time.Time) (int64, error)
+	LastDay(ctx context.Context) (time.Time, error)
+	Latest(ctx context.Context, walletID string, before time.Time) (entities.BalanceSnapshot, error)
+}
+
+type FeeRepo interface {
+	Effective(ctx context.Context, walletID, operationType, currency string) (entities.FeeRule, error)
+	List(ctx context.Context, walletID string) ([]entities.FeeRule, error)
+	Save(ctx context.Context, rule entities.FeeRule) (entities.FeeRule, error)
+	Delete(ctx context.Context, walletID, operationType, currency string) error
+}
+
+type InterestRepo interface {
+	GetByWalletID(ctx context.Context, walletID string) (entities.InterestPlan, error)
+	GetByWalletIDForUpdate(ctx context.Context, walletID string) (entities.InterestPlan, error)
+	ClaimDue(ctx context.Context, before time.Time) (entities.InterestPlan, error)
+	Save(ctx context.Context, plan entities.InterestPlan) (entities.InterestPlan, error)
+	Delete(ctx context.Context, walletID string) error
+	CreateAccrual(ctx context.Context, accrual entities.InterestAccrual) (bool, error)
+	CreatePayout(ctx context.Context, payout entities.InterestPayout) (entities.InterestPayout, error)
+	ListPayouts(ctx context.Context, walletID string, limit int) ([]entities.InterestPayout, error)
+}
+
+type CustomerRepo interface {
+	Create(ctx context.Context, customer entities.Customer) (entities.Customer, error)
+	GetByID(ctx context.Context, customerID string) (entities.Customer, error)
+	List(ctx context.Context, afterID string, limit int) ([]entities.Customer, error)
+	Update(ctx context.Context, customer entities.Customer) (entities.Customer, error)
+	Delete(ctx context.Context, customerID string) error
+}
+
+type OutboxRepo interface {
+	Create(ctx context.Context, event entities.WalletEvent) (entities.WalletEvent, error)
+	ClaimUnpublished(ctx context.Context, limit int, skipWallets []string) ([]entities.WalletEvent, error)
+	Notify(ctx context.Context, sequences []int64) error
+	MarkPublished(ctx context.Context, sequences []int64, now time.Time) error
+	MarkFailed(ctx context.Context, sequence int64, reason string) error
+	DeletePublished(ctx context.Context, before time.Time) (int64, error)
+	ListByWallet(ctx context.Context, walletID string, afterSequence int64, limit int) ([]entities.WalletEvent, error)
+	HasEvent(ctx context.Context, walletID string, sequence int64) (bool, error)
+	LastSequence(ctx context.Context, walletID string) (int64, error)
+}
+
+// EventPublisher delivers wallet events to other services. Publish returns
+// only once the event is delivered; an error makes the relay try again.
+type EventPublisher interface {
+	Publish(ctx context.Context, event entities.WalletEvent) error
+}
+
+// EventSubscriber delivers the wallet events committed by any replica. The
+// channel is closed when events may have been missed; the caller then has
+// to subscribe again and catch up from the outbox.
+type EventSubscriber interface {
+	Subscribe(walletID string) (<-chan entities.WalletEvent, func())
+}
+
+type WebhookRepo interface {
+	Create(ctx context.Context, webhook entities.Webhook) (entities.Webhook, error)
+	GetByID(ctx context.Context, webhookID string) (entities.Webhook, error)
+	List(ctx context.Context, walletID string) ([]entities.Webhook, error)
+	ListForWallet(ctx context.Context, walletID string) ([]entities.Webhook, error)
+	Delete(ctx context.Context, webhookID string) error
+	CreateDeliveries(ctx context.Context, deliveries []entities.WebhookDelivery) error
+	ClaimDueDelivery(ctx context.Context, now time.Time, skipWebhooks []string) (entities.WebhookDelivery, error)
+	GetDeliveryForUpdate(ctx context.Context, deliveryID string) (entities.WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery entities.WebhookDelivery) (entities.WebhookDelivery, error)
+	ListDeliveries(ctx context.Context, filter entities.WebhookDeliveryFilter) ([]entities.WebhookDelivery, error)
+}
+
+// WebhookClient sends one webhook request and returns the response status.
+// An error means no response was received. CheckURL fails with
+// entities.ErrWebhookURLNotAllowed for URLs the client refuses to send to.
+type WebhookClient interface {
+	CheckURL(ctx context.Context, url string) error
+	Post(ctx context.Context, url string, header http.Header, body []byte) (int, error)
 }
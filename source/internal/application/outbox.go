@@ -0,0 +1,170 @@
+package application
+
+import (
+	"TestProject/source/internal/entities"
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.uber.org/fx"
+)
+
+const (
+	outboxBatchSize       = 100
+	outboxCleanupInterval = time.Hour
+)
+
+// recordTransaction adds the transaction to the wallet history and its
+// WalletCredited or WalletDebited event to the outbox. It has to run in the
+// database transaction that changed the balance, so that the event is
+// published exactly when the change is committed.
+func (a *Application) recordTransaction(ctx context.Context, transaction entities.Transaction) (entities.Transaction, error) {
+	transaction, err := a.TransactionRepo.Create(ctx, transaction)
+	if err != nil {
+		return entities.Transaction{}, err
+	}
+
+	event := entities.NewWalletEvent(transaction, walletCredits(transaction.OperationType))
+	_, err = a.OutboxRepo.Create(ctx, event)
+	if err != nil {
+		return entities.Transaction{}, fmt.Errorf("error writing wallet event: %w", err)
+	}
+
+	return transaction, nil
+}
+
+// RelayOutbox publishes unpublished events in sequence order until the
+// outbox is drained and returns how many it published. An event is marked
+// published only after the publisher accepted it, so a crash in between
+// publishes it again. When an event fails, the later events of its wallet
+// wait for the next run to keep their order; other wallets go on.
+func (a *Application) RelayOutbox(ctx context.Context) (int, error) {
+	published := 0
+	// Wallets whose event failed are left out of the later batches of the
+	// run, so their waiting events cannot fill a batch and starve the rest.
+	blocked := make(map[string]bool)
+
+	for {
+		batch, full, err := a.relayOutboxBatch(ctx, blocked)
+		published += batch
+		if err != nil || !full {
+			return published, err
+		}
+	}
+}
+
+// relayOutboxBatch publishes one batch of events of the wallets not in
+// blocked, adds the wallets whose event failed to blocked and reports whether
+// the batch was full, i.e. whether another one may follow.
+func (a *Application) relayOutboxBatch(ctx context.Context, blocked map[string]bool) (int, bool, error) {
+	published := 0
+	full := false
+
+	err := a.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		skipWallets := make([]string, 0, len(blocked))
+		for walletID := range blocked {
+			skipWallets = append(skipWallets, walletID)
+		}
+		events, err := a.OutboxRepo.ClaimUnpublished(ctx, outboxBatchSize, skipWallets)
+		if err != nil {
+			return fmt.Errorf("error claiming outbox events: %w", err)
+		}
+		full = len(events) == outboxBatchSize
+		if len(events) == 0 {
+			return nil
+		}
+
+		// Wallet streams learn about committed events from this
+		// notification, whether or not publishing succeeds. Events claimed
+		// again after a failure are announced again and skipped by the
+		// streams by sequence.
+		claimed := make([]int64, 0, len(events))
+		for _, event := range events {
+			claimed = append(claimed, event.Sequence)
+		}
+		err = a.OutboxRepo.Notify(ctx, claimed)
+		if err != nil {
+			return fmt.Errorf("error notifying outbox events: %w", err)
+		}
+
+		sequences := make([]int64, 0, len(events))
+		for _, event := range events {
+			if blocked[event.WalletId] {
+				continue
+			}
+
+			err = a.Publisher.Publish(ctx, event)
+			if err != nil {
+				a.log.WarnContext(ctx, "error publishing wallet event",
+					slog.Int64("sequence", event.Sequence),
+					slog.String("wallet_id", event.WalletId),
+					slog.String("error", err.Error()))
+				blocked[event.WalletId] = true
+
+				err = a.OutboxRepo.MarkFailed(ctx, event.Sequence, err.Error())
+				if err != nil {
+					return fmt.Errorf("error recording failed outbox event: %w", err)
+				}
+				continue
+			}
+
+			err = a.enqueueWebhooks(ctx, event)
+			if err != nil {
+				return err
+			}
+			sequences = append(sequences, event.Sequence)
+		}
+		if len(sequences) == 0 {
+			return nil
+		}
+
+		err = a.OutboxRepo.MarkPublished(ctx, sequences, time.Now().UTC().Truncate(time.Microsecond))
+		if err != nil {
+			return fmt.Errorf("error marking outbox events published: %w", err)
+		}
+
+		published = len(sequences)
+		return nil
+	})
+	if err != nil {
+		return 0, false, err
+	}
+
+	return published, full, nil
+}
+
+// PurgePublishedEvents removes events published longer than the outbox
+// retention ago.
+func (a *Application) PurgePublishedEvents(ctx context.Context) (int64, error) {
+	deleted, err := a.OutboxRepo.DeletePublished(ctx, time.Now().UTC().Add(-a.outboxConf.Retention))
+	if err != nil {
+		return 0, fmt.Errorf("error purging outbox events: %w", err)
+	}
+	return deleted, nil
+}
+
+// RegisterOutboxRelay periodically publishes wallet events and removes old
+// published ones. A zero relay interval disables both, and with them the
+// live events of wallet streams.
+func RegisterOutboxRelay(lc fx.Lifecycle, app *Application) {
+	if app.outboxConf.RelayInterval <= 0 {
+		return
+	}
+
+	runPeriodically(lc, app.outboxConf.RelayInterval, func(ctx context.Context) {
+		_, err := app.RelayOutbox(ctx)
+		if err != nil {
+			app.log.ErrorContext(ctx, "error relaying outbox", slog.String("error", err.Error()))
+		}
+	})
+
+	runPeriodically(lc, outboxCleanupInterval, func(ctx context.Context) {
+		deleted, err := app.PurgePublishedEvents(ctx)
+		if err != nil {
+			app.log.ErrorContext(ctx, "error purging outbox events", slog.String("error", err.Error()))
+			return
+		}
+		app.log.InfoContext(ctx, "purged outbox events", slog.Int64("deleted", deleted))
+	})
+}
@@ -0,0 +1,333 @@
+package application
+
+import (
+	"TestProject/source/internal/entities"
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type mockOutboxRepo struct {
+	mu        sync.Mutex
+	events    []entities.WalletEvent
+	published map[int64]time.Time
+	failures  map[int64]string
+	notified  [][]int64
+}
+
+func newMockOutboxRepo() *mockOutboxRepo {
+	return &mockOutboxRepo{
+		published: make(map[int64]time.Time),
+		failures:  make(map[int64]string),
+	}
+}
+
+func (m *mockOutboxRepo) Create(ctx context.Context, event entities.WalletEvent) (entities.WalletEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	event.Sequence = int64(len(m.events) + 1)
+	m.events = append(m.events, event)
+	return event, nil
+}
+
+func (m *mockOutboxRepo) ClaimUnpublished(ctx context.Context, limit int, skipWallets []string) ([]entities.WalletEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var result []entities.WalletEvent
+	for _, event := range m.events {
+		if _, ok := m.published[event.Sequence]; ok {
+			continue
+		}
+		if slices.Contains(skipWallets, event.WalletId) {
+			continue
+		}
+		if len(result) == limit {
+			break
+		}
+		result = append(result, event)
+	}
+	return result, nil
+}
+
+func (m *mockOutboxRepo) Notify(ctx context.Context, sequences []int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.notified = append(m.notified, sequences)
+	return nil
+}
+
+func (m *mockOutboxRepo) MarkPublished(ctx context.Context, sequences []int64, now time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, sequence := range sequences {
+		m.published[sequence] = now
+	}
+	return nil
+}
+
+func (m *mockOutboxRepo) MarkFailed(ctx context.Context, sequence int64, reason string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.failures[sequence] = reason
+	return nil
+}
+
+func (m *mockOutboxRepo) DeletePublished(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockOutboxRepo) ListByWallet(ctx context.Context, walletID string, afterSequence int64, limit int) ([]entities.WalletEvent, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var result []entities.WalletEvent
+	for _, event := range m.events {
+		if event.WalletId == walletID && event.Sequence > afterSequence && len(result) < limit {
+			result = append(result, event)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockOutboxRepo) HasEvent(ctx context.Context, walletID string, sequence int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, event := range m.events {
+		if event.WalletId == walletID && event.Sequence == sequence {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *mockOutboxRepo) LastSequence(ctx context.Context, walletID string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var sequence int64
+	for _, event := range m.events {
+		if event.WalletId == walletID {
+			sequence = event.Sequence
+		}
+	}
+	return sequence, nil
+}
+
+// mockPublisher fails the events in failing once each and every event of
+// failingWallet.
+type mockPublisher struct {
+	mu            sync.Mutex
+	published     []entities.WalletEvent
+	failing       map[string]bool
+	failingWallet string
+}
+
+func (m *mockPublisher) Publish(ctx context.Context, event entities.WalletEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if event.WalletId == m.failingWallet {
+		return errors.New("broker rejected the event")
+	}
+	if m.failing[event.ID] {
+		delete(m.failing, event.ID)
+		return errors.New("broker unavailable")
+	}
+	m.published = append(m.published, event)
+	return nil
+}
+
+func TestApplication_WalletEvents(t *testing.T) {
+	repo := newMockWalletRepo()
+	app, _ := newTestApplication(repo)
+	outbox := app.OutboxRepo.(*mockOutboxRepo)
+	ctx := context.Background()
+
+	from := entities.NewWallet()
+	to := entities.NewWallet()
+	repo.wallets[from.ID] = from
+	repo.wallets[to.ID] = to
+
+	deposit, err := app.ProcessTransaction(ctx, entities.Transaction{WalletId: from.ID, OperationType: deposit, Amount: dec("100")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = app.ProcessTransaction(ctx, entities.Transaction{WalletId: from.ID, OperationType: withdraw, Amount: dec("30")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = app.Transfer(ctx, entities.Transfer{FromWalletId: from.ID, ToWalletId: to.ID, Amount: dec("20")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = app.ProcessTransaction(ctx, entities.Transaction{WalletId: from.ID, OperationType: withdraw, Amount: dec("1000")})
+	if !errors.Is(err, entities.ErrInsufficientFunds) {
+		t.Fatalf("expected ErrInsufficientFunds, got %v", err)
+	}
+
+	expected := []struct {
+		eventType string
+		walletID  string
+		balance   string
+	}{
+		{entities.EventWalletCredited, from.ID, "100"},
+		{entities.EventWalletDebited, from.ID, "70"},
+		{entities.EventWalletDebited, from.ID, "50"},
+		{entities.EventWalletCredited, to.ID, "20"},
+	}
+	if len(outbox.events) != len(expected) {
+		t.Fatalf("expected %d events, got %d", len(expected), len(outbox.events))
+	}
+	for i, want := range expected {
+		event := outbox.events[i]
+		if event.Type != want.eventType || event.WalletId != want.walletID || !event.Balance.Equal(dec(want.balance)) {
+			t.Errorf("event %d: expected %s on %s with balance %s, got %+v", i, want.eventType, want.walletID, want.balance, event)
+		}
+	}
+	if outbox.events[0].TransactionId != deposit.ID || !outbox.events[0].Amount.Equal(dec("100")) {
+		t.Errorf("unexpected deposit event: %+v", outbox.events[0])
+	}
+}
+
+func TestApplication_RelayOutbox_KeepsWalletOrder(t *testing.T) {
+	app, _ := newTestApplication(newMockWalletRepo())
+	app.log = slog.New(slog.DiscardHandler)
+	outbox := app.OutboxRepo.(*mockOutboxRepo)
+	ctx := context.Background()
+
+	walletA, walletB := entities.NewWallet().ID, entities.NewWallet().ID
+	var events []entities.WalletEvent
+	for _, walletID := range []string{walletA, walletB, walletA, walletB} {
+		event, _ := outbox.Create(ctx, entities.NewWalletEvent(entities.Transaction{
+			ID:            entities.NewTransaction().ID,
+			WalletId:      walletID,
+			OperationType: deposit,
+			Amount:        decimal.NewFromInt(1),
+		}, true))
+		events = append(events, event)
+	}
+
+	publisher := &mockPublisher{failing: map[string]bool{events[0].ID: true}}
+	app.Publisher = publisher
+
+	published, err := app.RelayOutbox(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if published != 2 {
+		t.Fatalf("expected 2 events published, got %d", published)
+	}
+	if _, ok := outbox.failures[events[0].Sequence]; !ok {
+		t.Error("expected the failed event to be recorded")
+	}
+	if _, ok := outbox.published[events[2].Sequence]; ok {
+		t.Error("expected the second event of the failed wallet to wait")
+	}
+
+	published, err = app.RelayOutbox(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if published != 2 {
+		t.Fatalf("expected 2 events published, got %d", published)
+	}
+
+	var order []int64
+	for _, event := range publisher.published {
+		order = append(order, event.Sequence)
+	}
+	want := []int64{2, 4, 1, 3}
+	if len(order) != len(want) {
+		t.Fatalf("expected order %v, got %v", want, order)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("expected order %v, got %v", want, order)
+		}
+	}
+}
+
+func TestApplication_RelayOutbox_DrainsBatches(t *testing.T) {
+	app, _ := newTestApplication(newMockWalletRepo())
+	outbox := app.OutboxRepo.(*mockOutboxRepo)
+	publisher := &mockPublisher{}
+	app.Publisher = publisher
+	ctx := context.Background()
+
+	total := outboxBatchSize*2 + 10
+	for range total {
+		_, _ = outbox.Create(ctx, entities.NewWalletEvent(entities.Transaction{WalletId: entities.NewWallet().ID}, false))
+	}
+
+	published, err := app.RelayOutbox(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if published != total || len(publisher.published) != total {
+		t.Errorf("expected %d events published, got %d", total, published)
+	}
+	for i, event := range publisher.published {
+		if event.Sequence != int64(i+1) {
+			t.Fatalf("expected sequence %d at %d, got %d", i+1, i, event.Sequence)
+		}
+	}
+	// One notification per batch announces the streams every claimed event.
+	if len(outbox.notified) != 3 {
+		t.Fatalf("expected 3 notifications, got %d", len(outbox.notified))
+	}
+	announced := 0
+	for _, sequences := range outbox.notified {
+		announced += len(sequences)
+	}
+	if announced != total {
+		t.Errorf("expected %d events announced, got %d", total, announced)
+	}
+}
+
+func TestApplication_RelayOutbox_FailingWalletDoesNotStarveOthers(t *testing.T) {
+	app, _ := newTestApplication(newMockWalletRepo())
+	app.log = slog.New(slog.DiscardHandler)
+	outbox := app.OutboxRepo.(*mockOutboxRepo)
+	ctx := context.Background()
+
+	// More failing events than fit in a batch come before the other wallet's.
+	failing, other := entities.NewWallet().ID, entities.NewWallet().ID
+	for range outboxBatchSize + 5 {
+		_, _ = outbox.Create(ctx, entities.NewWalletEvent(entities.Transaction{WalletId: failing}, true))
+	}
+	for range 3 {
+		_, _ = outbox.Create(ctx, entities.NewWalletEvent(entities.Transaction{WalletId: other}, true))
+	}
+
+	publisher := &mockPublisher{failingWallet: failing}
+	app.Publisher = publisher
+
+	published, err := app.RelayOutbox(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if published != 3 {
+		t.Fatalf("expected the other wallet's 3 events published, got %d", published)
+	}
+
+	for _, event := range publisher.published {
+		if event.WalletId != other {
+			t.Errorf("expected only the other wallet's events, got one of %s", event.WalletId)
+		}
+	}
+	if len(outbox.failures) != 1 {
+		t.Errorf("expected only the first failing event attempted, got %d failures", len(outbox.failures))
+	}
+}
@@ -0,0 +1,38 @@
+package application
+
+import (
+	"TestProject/source/internal/entities"
+	"context"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// SetOverdraftLimit lets the wallet balance go down to -limit. The limit
+// cannot be lowered below the credit the wallet already uses.
+func (a *Application) SetOverdraftLimit(ctx context.Context, walletID string, limit decimal.Decimal) (entities.Wallet, error) {
+	if limit.IsNegative() {
+		return entities.Wallet{}, fmt.Errorf("%w: overdraft limit cannot be negative", entities.ErrInvalidAmount)
+	}
+
+	wallet, err := a.WalletRepo.UpdateWithLock(ctx, walletID, func(w *entities.Wallet) error {
+		if w.Status == entities.WalletClosed {
+			return entities.ErrWalletClosed
+		}
+		err := entities.ValidateAmount(limit, w.Currency)
+		if err != nil {
+			return err
+		}
+		if w.Balance.Add(limit).IsNegative() {
+			return fmt.Errorf("%w: balance is %s", entities.ErrOverdraftInUse, w.Balance.String())
+		}
+
+		w.OverdraftLimit = limit
+		return nil
+	})
+	if err != nil {
+		return entities.Wallet{}, fmt.Errorf("error setting overdraft limit: %w", err)
+	}
+
+	return wallet, nil
+}
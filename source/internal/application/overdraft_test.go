@@ -0,0 +1,87 @@
+package application
+
+import (
+	"TestProject/source/internal/entities"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestApplication_ProcessTransaction_WithdrawIntoOverdraft(t *testing.T) {
+	repo := newMockWalletRepo()
+	app, _ := newTestApplication(repo)
+	ctx := context.Background()
+
+	wallet := entities.NewWallet()
+	wallet.Balance = decimal.NewFromInt(100)
+	repo.wallets[wallet.ID] = wallet
+
+	wallet, err := app.SetOverdraftLimit(ctx, wallet.ID, decimal.NewFromInt(500))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !wallet.RemainingCredit().Equal(decimal.NewFromInt(500)) {
+		t.Errorf("expected remaining credit 500, got %s", wallet.RemainingCredit().String())
+	}
+
+	result, err := app.ProcessTransaction(ctx, entities.Transaction{
+		WalletId: wallet.ID, OperationType: "WITHDRAW", Amount: decimal.NewFromInt(400),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Balance.Equal(decimal.NewFromInt(-300)) {
+		t.Errorf("expected balance -300, got %s", result.Balance.String())
+	}
+	if credit := repo.wallets[wallet.ID].RemainingCredit(); !credit.Equal(decimal.NewFromInt(200)) {
+		t.Errorf("expected remaining credit 200, got %s", credit.String())
+	}
+
+	_, err = app.ProcessTransaction(ctx, entities.Transaction{
+		WalletId: wallet.ID, OperationType: "WITHDRAW", Amount: decimal.NewFromInt(201),
+	})
+	if !errors.Is(err, entities.ErrInsufficientFunds) {
+		t.Fatalf("expected ErrInsufficientFunds below the floor, got %v", err)
+	}
+
+	result, err = app.ProcessTransaction(ctx, entities.Transaction{
+		WalletId: wallet.ID, OperationType: "WITHDRAW", Amount: decimal.NewFromInt(200),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Balance.Equal(decimal.NewFromInt(-500)) {
+		t.Errorf("expected balance -500, got %s", result.Balance.String())
+	}
+}
+
+func TestApplication_SetOverdraftLimit_BelowUsedCredit(t *testing.T) {
+	repo := newMockWalletRepo()
+	app, _ := newTestApplication(repo)
+	ctx := context.Background()
+
+	wallet := entities.NewWallet()
+	wallet.Balance = decimal.NewFromInt(-300)
+	wallet.OverdraftLimit = decimal.NewFromInt(500)
+	repo.wallets[wallet.ID] = wallet
+
+	_, err := app.SetOverdraftLimit(ctx, wallet.ID, decimal.NewFromInt(200))
+	if !errors.Is(err, entities.ErrOverdraftInUse) {
+		t.Fatalf("expected ErrOverdraftInUse, got %v", err)
+	}
+
+	_, err = app.SetOverdraftLimit(ctx, wallet.ID, decimal.NewFromInt(-1))
+	if !errors.Is(err, entities.ErrInvalidAmount) {
+		t.Fatalf("expected ErrInvalidAmount, got %v", err)
+	}
+
+	wallet, err = app.SetOverdraftLimit(ctx, wallet.ID, decimal.NewFromInt(300))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !wallet.RemainingCredit().IsZero() {
+		t.Errorf("expected no remaining credit, got %s", wallet.RemainingCredit().String())
+	}
+}
@@ -0,0 +1,41 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/fx"
+)
+
+// runPeriodically calls fn every interval from app start until app stop.
+// A stopped app waits for the running call to return.
+func runPeriodically(lc fx.Lifecycle, interval time.Duration, fn func(ctx context.Context)) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+						fn(ctx)
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+			<-done
+			return nil
+		},
+	})
+}
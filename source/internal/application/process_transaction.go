@@ -4,25 +4,112 @@ import (
 	"TestProject/source/internal/entities"
 	"context"
 	"fmt"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 const (
-	deposit  = "DEPOSIT"
-	withdraw = "WITHDRAW"
+	deposit     = "DEPOSIT"
+	withdraw    = "WITHDRAW"
+	transferIn  = "TRANSFER_IN"
+	transferOut = "TRANSFER_OUT"
+	capture     = "CAPTURE"
+	interest    = "INTEREST"
+
+	// transferEntry is the journal operation type of a transfer; its two
+	// ledger entries are TRANSFER_OUT and TRANSFER_IN.
+	transferEntry = "TRANSFER"
+
+	depositReversal  = "DEPOSIT_REVERSAL"
+	withdrawReversal = "WITHDRAW_REVERSAL"
 )
 
+// processedOperations are the operation types ProcessTransaction accepts.
+// INTEREST only comes from the interest payout job.
+var processedOperations = map[string]bool{deposit: true, withdraw: true, interest: true}
+
 func (a *Application) ProcessTransaction(ctx context.Context, transaction entities.Transaction) (entities.Transaction, error) {
-	wallet, err := a.WalletRepo.UpdateWithLock(ctx, transaction.WalletId, func(w *entities.Wallet) error {
-		switch transaction.OperationType {
-		case deposit:
-			w.Balance = w.Balance.Add(transaction.Amount)
-		case withdraw:
-			if w.Balance.LessThan(transaction.Amount) {
-				return entities.ErrInsufficientFunds
-			}
-			w.Balance = w.Balance.Sub(transaction.Amount)
-		default:
-			return fmt.Errorf("%w: %s", entities.ErrInvalidOperation, transaction.OperationType)
+	return a.processTransaction(ctx, transaction, true)
+}
+
+// processTransaction is ProcessTransaction; chargeFee false skips the fee
+// rules, for the opening balance of a new wallet.
+func (a *Application) processTransaction(ctx context.Context, transaction entities.Transaction, chargeFee bool) (entities.Transaction, error) {
+	if transaction.ID == "" {
+		transaction.ID = uuid.NewString()
+	}
+	transaction.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+
+	var entry entities.JournalEntry
+
+	err := a.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		wallet, err := a.WalletRepo.UpdateWithLock(ctx, transaction.WalletId, func(w *entities.Wallet) error {
+			if transaction.Currency != "" && transaction.Currency != w.Currency {
+				return fmt.Errorf("%w: wallet is %s, got %s", entities.ErrCurrencyMismatch, w.Currency, transaction.Currency)
+			}
+			err := checkWalletStatus(*w, transaction.OperationType)
+			if err != nil {
+				return err
+			}
+			err = entities.ValidateAmount(transaction.Amount, w.Currency)
+			if err != nil {
+				return err
+			}
+
+			transaction.Currency = w.Currency
+			transaction.BalanceBefore = w.Balance
+
+			if !processedOperations[transaction.OperationType] {
+				return fmt.Errorf("%w: %s", entities.ErrInvalidOperation, transaction.OperationType)
+			}
+			if chargeFee && feeOperations[transaction.OperationType] {
+				transaction.Fee, err = a.transactionFee(ctx, transaction)
+				if err != nil {
+					return err
+				}
+			}
+
+			// The fee is taken in the same update, so the amount and the fee
+			// are checked against one balance.
+			switch transaction.OperationType {
+			case deposit:
+				if w.Spendable().Add(transaction.Amount).LessThan(transaction.Fee) {
+					return entities.ErrInsufficientFunds
+				}
+			case withdraw:
+				if w.Spendable().LessThan(transaction.Amount.Add(transaction.Fee)) {
+					return entities.ErrInsufficientFunds
+				}
+				err = a.checkVelocity(ctx, w.ID, transaction.Amount, transaction.CreatedAt, "")
+				if err != nil {
+					return err
+				}
+			}
+
+			entry, err = singleWalletEntry(transaction)
+			if err != nil {
+				return err
+			}
+			err = applyJournal(entry, map[string]*entities.Wallet{w.ID: w})
+			if err != nil {
+				return err
+			}
+			return entities.CheckAmountRange(w.Balance)
+		})
+		if err != nil {
+			return err
+		}
+
+		transaction.Balance = wallet.Balance
+
+		transaction, err = a.recordTransaction(ctx, transaction)
+		if err != nil {
+			return fmt.Errorf("error recording transaction: %w", err)
+		}
+		err = a.JournalRepo.Create(ctx, entry)
+		if err != nil {
+			return fmt.Errorf("error recording journal entry: %w", err)
 		}
 		return nil
 	})
@@ -30,7 +117,5 @@ func (a *Application) ProcessTransaction(ctx context.Context, transaction entiti
 		return entities.Transaction{}, fmt.Errorf("error processing transaction: %w", err)
 	}
 
-	transaction.Balance = wallet.Balance
-
 	return transaction, nil
 }
@@ -11,7 +11,7 @@ import (
 
 func TestApplication_ProcessTransaction_ConcurrentDeposits(t *testing.T) {
 	repo := newMockWalletRepo()
-	app := &Application{WalletRepo: repo}
+	app, _ := newTestApplication(repo)
 	ctx := context.Background()
 
 	wallet := entities.NewWallet()
@@ -56,7 +56,7 @@ func TestApplication_ProcessTransaction_ConcurrentDeposits(t *testing.T) {
 
 func TestApplication_ProcessTransaction_ConcurrentMixed(t *testing.T) {
 	repo := newMockWalletRepo()
-	app := &Application{WalletRepo: repo}
+	app, _ := newTestApplication(repo)
 	ctx := context.Background()
 
 	wallet := entities.NewWallet()
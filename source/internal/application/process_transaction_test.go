@@ -4,16 +4,20 @@ import (
 	"TestProject/source/internal/entities"
 	"context"
 	"errors"
+	"slices"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/shopspring/decimal"
 )
 
 type mockWalletRepo struct {
-	mu      sync.RWMutex
-	wallets map[string]entities.Wallet
-	errors  map[string]error
+	mu            sync.RWMutex
+	wallets       map[string]entities.Wallet
+	errors        map[string]error
+	lockManyCalls int
 }
 
 func newMockWalletRepo() *mockWalletRepo {
@@ -43,7 +47,7 @@ func (m *mockWalletRepo) GetByID(ctx context.Context, walletID string) (entities
 	}
 	wallet, ok := m.wallets[walletID]
 	if !ok {
-		return entities.Wallet{}, errors.New("wallet not found")
+		return entities.Wallet{}, entities.ErrWalletNotFound
 	}
 	return wallet, nil
 }
@@ -52,6 +56,10 @@ func (m *mockWalletRepo) GetByIDForUpdate(ctx context.Context, walletID string)
 	return m.GetByID(ctx, walletID)
 }
 
+func (m *mockWalletRepo) GetByIDForShare(ctx context.Context, walletID string) (entities.Wallet, error) {
+	return m.GetByID(ctx, walletID)
+}
+
 func (m *mockWalletRepo) Update(ctx context.Context, wallet entities.Wallet) (entities.Wallet, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -72,7 +80,7 @@ func (m *mockWalletRepo) UpdateWithLock(ctx context.Context, walletID string, up
 	}
 	wallet, ok := m.wallets[walletID]
 	if !ok {
-		return entities.Wallet{}, errors.New("wallet not found")
+		return entities.Wallet{}, entities.ErrWalletNotFound
 	}
 
 	err := updateFn(&wallet)
@@ -84,9 +92,186 @@ func (m *mockWalletRepo) UpdateWithLock(ctx context.Context, walletID string, up
 	return wallet, nil
 }
 
+type mockTransactor struct{}
+
+func (mockTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (mockTransactor) WithinSavepoint(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (mockTransactor) InTransaction(ctx context.Context) bool {
+	return false
+}
+
+type mockTransactionRepo struct {
+	mu           sync.Mutex
+	transactions []entities.Transaction
+}
+
+func (m *mockTransactionRepo) Create(ctx context.Context, transaction entities.Transaction) (entities.Transaction, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.transactions = append(m.transactions, transaction)
+	return transaction, nil
+}
+
+func (m *mockTransactionRepo) GetByID(ctx context.Context, transactionID string) (entities.Transaction, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, transaction := range m.transactions {
+		if transaction.ID == transactionID {
+			return transaction, nil
+		}
+	}
+	return entities.Transaction{}, entities.ErrTransactionNotFound
+}
+
+func (m *mockTransactionRepo) SumByReference(ctx context.Context, referenceID, operationType string) (decimal.Decimal, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sum := decimal.Zero
+	for _, transaction := range m.transactions {
+		if transaction.ReferenceId == referenceID && transaction.OperationType == operationType {
+			sum = sum.Add(transaction.Amount)
+		}
+	}
+	return sum, nil
+}
+
+func (m *mockTransactionRepo) List(ctx context.Context, filter entities.TransactionFilter) ([]entities.Transaction, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var result []entities.Transaction
+	for i := len(m.transactions) - 1; i >= 0 && len(result) < filter.Limit; i-- {
+		transaction := m.transactions[i]
+		if transaction.WalletId != filter.WalletId {
+			continue
+		}
+		if filter.OperationType != "" && transaction.OperationType != filter.OperationType {
+			continue
+		}
+		if filter.Cursor != nil && !transaction.CreatedAt.Before(filter.Cursor.CreatedAt) &&
+			(!transaction.CreatedAt.Equal(filter.Cursor.CreatedAt) || transaction.ID >= filter.Cursor.ID) {
+			continue
+		}
+		result = append(result, transaction)
+	}
+	return result, nil
+}
+
+func (m *mockTransactionRepo) Usage(ctx context.Context, walletID string, operationTypes []string, since time.Time) (entities.Usage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var usage entities.Usage
+	for _, transaction := range m.transactions {
+		if transaction.WalletId == walletID && slices.Contains(operationTypes, transaction.OperationType) &&
+			!transaction.CreatedAt.Before(since) {
+			usage.Amount = usage.Amount.Add(transaction.Amount)
+			usage.Count++
+		}
+	}
+	return usage, nil
+}
+
+func (m *mockTransactionRepo) Stream(ctx context.Context, walletID string, from, to time.Time, fn func(entities.Transaction) error) error {
+	m.mu.Lock()
+	var matched []entities.Transaction
+	for _, transaction := range m.transactions {
+		if transaction.WalletId == walletID && !transaction.CreatedAt.Before(from) && transaction.CreatedAt.Before(to) {
+			matched = append(matched, transaction)
+		}
+	}
+	m.mu.Unlock()
+
+	slices.SortStableFunc(matched, func(a, b entities.Transaction) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	for _, transaction := range matched {
+		err := fn(transaction)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newTestApplication(walletRepo *mockWalletRepo) (*Application, *mockTransactionRepo) {
+	transactionRepo := &mockTransactionRepo{}
+	return &Application{
+		Transactor:      mockTransactor{},
+		WalletRepo:      walletRepo,
+		TransactionRepo: transactionRepo,
+		HoldRepo:        newMockHoldRepo(),
+		JournalRepo:     newMockJournalRepo(walletRepo),
+		LimitRepo:       newMockLimitRepo(),
+		SnapshotRepo:    &mockSnapshotRepo{},
+		FeeRepo:         newMockFeeRepo(),
+		InterestRepo:    newMockInterestRepo(),
+		CustomerRepo:    newMockCustomerRepo(),
+		OutboxRepo:      newMockOutboxRepo(),
+		WebhookRepo:     newMockWebhookRepo(),
+	}, transactionRepo
+}
+
+func (m *mockWalletRepo) UpdateManyWithLock(ctx context.Context, walletIDs []string, updateFn func(map[string]*entities.Wallet) error) (map[string]entities.Wallet, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	locked := make(map[string]*entities.Wallet, len(walletIDs))
+	for _, id := range walletIDs {
+		wallet, ok := m.wallets[id]
+		if !ok {
+			return nil, entities.ErrWalletNotFound
+		}
+		locked[id] = &wallet
+	}
+
+	err := updateFn(locked)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]entities.Wallet, len(locked))
+	for id, wallet := range locked {
+		m.wallets[id] = *wallet
+		result[id] = *wallet
+	}
+	return result, nil
+}
+
+func (m *mockWalletRepo) LockMany(ctx context.Context, walletIDs []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lockManyCalls++
+	return nil
+}
+
+func (m *mockWalletRepo) ListByOwner(ctx context.Context, ownerID string) ([]entities.Wallet, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var result []entities.Wallet
+	for _, wallet := range m.wallets {
+		if wallet.OwnerId == ownerID {
+			result = append(result, wallet)
+		}
+	}
+	slices.SortFunc(result, func(a, b entities.Wallet) int { return strings.Compare(a.ID, b.ID) })
+	return result, nil
+}
+
 func TestApplication_ProcessTransaction_Deposit(t *testing.T) {
 	repo := newMockWalletRepo()
-	app := &Application{WalletRepo: repo}
+	app, _ := newTestApplication(repo)
 	ctx := context.Background()
 
 	wallet := entities.NewWallet()
@@ -117,7 +302,7 @@ func TestApplication_ProcessTransaction_Deposit(t *testing.T) {
 
 func TestApplication_ProcessTransaction_Withdraw(t *testing.T) {
 	repo := newMockWalletRepo()
-	app := &Application{WalletRepo: repo}
+	app, _ := newTestApplication(repo)
 	ctx := context.Background()
 
 	wallet := entities.NewWallet()
@@ -143,7 +328,7 @@ func TestApplication_ProcessTransaction_Withdraw(t *testing.T) {
 
 func TestApplication_ProcessTransaction_InsufficientFunds(t *testing.T) {
 	repo := newMockWalletRepo()
-	app := &Application{WalletRepo: repo}
+	app, _ := newTestApplication(repo)
 	ctx := context.Background()
 
 	wallet := entities.NewWallet()
@@ -174,7 +359,7 @@ func TestApplication_ProcessTransaction_InsufficientFunds(t *testing.T) {
 
 func TestApplication_ProcessTransaction_InvalidOperation(t *testing.T) {
 	repo := newMockWalletRepo()
-	app := &Application{WalletRepo: repo}
+	app, _ := newTestApplication(repo)
 	ctx := context.Background()
 
 	wallet := entities.NewWallet()
@@ -199,7 +384,7 @@ func TestApplication_ProcessTransaction_InvalidOperation(t *testing.T) {
 
 func TestApplication_ProcessTransaction_WalletNotFound(t *testing.T) {
 	repo := newMockWalletRepo()
-	app := &Application{WalletRepo: repo}
+	app, _ := newTestApplication(repo)
 	ctx := context.Background()
 
 	transaction := entities.Transaction{
@@ -213,3 +398,155 @@ func TestApplication_ProcessTransaction_WalletNotFound(t *testing.T) {
 		t.Fatal("expected error for wallet not found")
 	}
 }
+
+func TestApplication_ProcessTransaction_RecordsLedgerEntry(t *testing.T) {
+	repo := newMockWalletRepo()
+	app, ledger := newTestApplication(repo)
+	ctx := context.Background()
+
+	wallet := entities.NewWallet()
+	wallet.Balance = decimal.NewFromFloat(1000.0)
+	repo.wallets[wallet.ID] = wallet
+
+	transaction := entities.NewTransaction()
+	transaction.WalletId = wallet.ID
+	transaction.OperationType = "WITHDRAW"
+	transaction.Amount = decimal.NewFromFloat(250.0)
+
+	result, err := app.ProcessTransaction(ctx, transaction)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.ID != transaction.ID {
+		t.Errorf("expected transaction id %s, got %s", transaction.ID, result.ID)
+	}
+	if result.CreatedAt.IsZero() {
+		t.Error("expected created_at to be set")
+	}
+
+	if len(ledger.transactions) != 1 {
+		t.Fatalf("expected 1 ledger entry, got %d", len(ledger.transactions))
+	}
+	entry := ledger.transactions[0]
+	if entry.ID != result.ID {
+		t.Errorf("expected ledger entry id %s, got %s", result.ID, entry.ID)
+	}
+	if !entry.BalanceBefore.Equal(decimal.NewFromFloat(1000.0)) {
+		t.Errorf("expected balance before 1000.0, got %s", entry.BalanceBefore.String())
+	}
+	if !entry.Balance.Equal(decimal.NewFromFloat(750.0)) {
+		t.Errorf("expected balance after 750.0, got %s", entry.Balance.String())
+	}
+}
+
+func TestApplication_ProcessTransaction_FailedNotRecorded(t *testing.T) {
+	repo := newMockWalletRepo()
+	app, ledger := newTestApplication(repo)
+	ctx := context.Background()
+
+	wallet := entities.NewWallet()
+	wallet.Balance = decimal.NewFromFloat(100.0)
+	repo.wallets[wallet.ID] = wallet
+
+	transaction := entities.Transaction{
+		WalletId:      wallet.ID,
+		OperationType: "WITHDRAW",
+		Amount:        decimal.NewFromFloat(500.0),
+	}
+
+	_, err := app.ProcessTransaction(ctx, transaction)
+	if !errors.Is(err, entities.ErrInsufficientFunds) {
+		t.Fatalf("expected ErrInsufficientFunds, got %v", err)
+	}
+
+	if len(ledger.transactions) != 0 {
+		t.Errorf("expected no ledger entries, got %d", len(ledger.transactions))
+	}
+}
+
+func TestApplication_ProcessTransaction_CurrencyPrecision(t *testing.T) {
+	tests := []struct {
+		name     string
+		currency string
+		amount   string
+		wantErr  error
+	}{
+		{name: "JPY whole amount", currency: "JPY", amount: "100", wantErr: nil},
+		{name: "JPY trailing zeros", currency: "JPY", amount: "100.00", wantErr: nil},
+		{name: "JPY fractional amount", currency: "JPY", amount: "100.5", wantErr: entities.ErrInvalidAmountPrecision},
+		{name: "BHD three decimals", currency: "BHD", amount: "1.125", wantErr: nil},
+		{name: "BHD four decimals", currency: "BHD", amount: "1.1255", wantErr: entities.ErrInvalidAmountPrecision},
+		{name: "USD three decimals", currency: "USD", amount: "1.125", wantErr: entities.ErrInvalidAmountPrecision},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockWalletRepo()
+			app, _ := newTestApplication(repo)
+
+			wallet := entities.NewWallet()
+			wallet.Currency = tt.currency
+			repo.wallets[wallet.ID] = wallet
+
+			_, err := app.ProcessTransaction(context.Background(), entities.Transaction{
+				WalletId:      wallet.ID,
+				OperationType: "DEPOSIT",
+				Amount:        decimal.RequireFromString(tt.amount),
+			})
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestApplication_ProcessTransaction_CurrencyMismatch(t *testing.T) {
+	repo := newMockWalletRepo()
+	app, ledger := newTestApplication(repo)
+
+	wallet := entities.NewWallet()
+	wallet.Currency = "EUR"
+	repo.wallets[wallet.ID] = wallet
+
+	_, err := app.ProcessTransaction(context.Background(), entities.Transaction{
+		WalletId:      wallet.ID,
+		OperationType: "DEPOSIT",
+		Currency:      "USD",
+		Amount:        decimal.NewFromFloat(10.0),
+	})
+	if !errors.Is(err, entities.ErrCurrencyMismatch) {
+		t.Fatalf("expected ErrCurrencyMismatch, got %v", err)
+	}
+	if len(ledger.transactions) != 0 {
+		t.Errorf("expected no ledger entries, got %d", len(ledger.transactions))
+	}
+}
+
+func TestApplication_CreateWallet_Currency(t *testing.T) {
+	repo := newMockWalletRepo()
+	app, _ := newTestApplication(repo)
+	ctx := context.Background()
+
+	wallet := entities.NewWallet()
+	wallet.Currency = "KWD"
+	wallet.Balance = decimal.RequireFromString("10.125")
+
+	created, err := app.CreateWallet(ctx, wallet)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.Currency != "KWD" {
+		t.Errorf("expected currency KWD, got %s", created.Currency)
+	}
+
+	wallet = entities.NewWallet()
+	wallet.Currency = "XXX"
+	_, err = app.CreateWallet(ctx, wallet)
+	if !errors.Is(err, entities.ErrUnsupportedCurrency) {
+		t.Errorf("expected ErrUnsupportedCurrency, got %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+package application
+
+import (
+	"TestProject/source/internal/entities"
+	"context"
+	"expvar"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/fx"
+)
+
+const reconciliationBatchSize = 500
+
+// Reconciliation metrics, served by the HTTP server at /debug/vars.
+var (
+	reconciliationRuns           = expvar.NewInt("reconciliation_runs")
+	reconciliationWalletsChecked = expvar.NewInt("reconciliation_wallets_checked")
+	reconciliationDriftedWallets = expvar.NewInt("reconciliation_drifted_wallets")
+	reconciliationLastRun        = expvar.NewInt("reconciliation_last_run_unix")
+)
+
+// Reconcile recomputes every wallet balance from its journal postings and
+// reports the wallets whose stored balance differs from it.
+func (a *Application) Reconcile(ctx context.Context) (entities.ReconciliationReport, error) {
+	report := entities.ReconciliationReport{
+		StartedAt:   time.Now().UTC(),
+		Drifts:      []entities.BalanceDrift{},
+		DriftTotals: make(map[string]decimal.Decimal),
+	}
+
+	afterID := ""
+	for {
+		checks, err := a.JournalRepo.WalletBalanceChecks(ctx, afterID, reconciliationBatchSize)
+		if err != nil {
+			return entities.ReconciliationReport{}, fmt.Errorf("error checking wallet balances: %w", err)
+		}
+
+		for _, check := range checks {
+			report.WalletsChecked++
+			drift := check.Drift()
+			if drift.IsZero() {
+				continue
+			}
+			report.Drifts = append(report.Drifts, entities.BalanceDrift{BalanceCheck: check, Drift: drift})
+			report.DriftTotals[check.Currency] = report.DriftTotals[check.Currency].Add(drift)
+		}
+
+		if len(checks) < reconciliationBatchSize {
+			break
+		}
+		afterID = checks[len(checks)-1].WalletId
+	}
+	report.FinishedAt = time.Now().UTC()
+
+	reconciliationRuns.Add(1)
+	reconciliationWalletsChecked.Set(int64(report.WalletsChecked))
+	reconciliationDriftedWallets.Set(int64(len(report.Drifts)))
+	reconciliationLastRun.Set(report.FinishedAt.Unix())
+
+	return report, nil
+}
+
+// logReconciliation writes the report as one summary record plus one record
+// per drifted wallet.
+func (a *Application) logReconciliation(ctx context.Context, report entities.ReconciliationReport) {
+	if report.Consistent() {
+		a.log.InfoContext(ctx, "reconciliation finished",
+			slog.Int("wallets_checked", report.WalletsChecked),
+			slog.Duration("duration", report.FinishedAt.Sub(report.StartedAt)),
+		)
+		return
+	}
+
+	for _, drift := range report.Drifts {
+		a.log.WarnContext(ctx, "wallet balance drift",
+			slog.String("wallet_id", drift.WalletId),
+			slog.String("currency", drift.Currency),
+			slog.String("balance", drift.Balance.String()),
+			slog.String("ledger_balance", drift.LedgerBalance.String()),
+			slog.String("drift", drift.Drift.String()),
+		)
+	}
+	a.log.ErrorContext(ctx, "reconciliation found drift",
+		slog.Int("wallets_checked", report.WalletsChecked),
+		slog.Int("drifted_wallets", len(report.Drifts)),
+		slog.Any("drift_totals", report.DriftTotals),
+		slog.Duration("duration", report.FinishedAt.Sub(report.StartedAt)),
+	)
+}
+
+// RegisterReconciliation periodically reconciles wallet balances. A zero
+// interval disables the job.
+func RegisterReconciliation(lc fx.Lifecycle, app *Application) {
+	if app.reconciliationInterval <= 0 {
+		return
+	}
+
+	runPeriodically(lc, app.reconciliationInterval, func(ctx context.Context) {
+		report, err := app.Reconcile(ctx)
+		if err != nil {
+			app.log.ErrorContext(ctx, "error reconciling balances", slog.String("error", err.Error()))
+			return
+		}
+		app.logReconciliation(ctx, report)
+	})
+}
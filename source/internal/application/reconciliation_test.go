@@ -0,0 +1,60 @@
+package application
+
+import (
+	"TestProject/source/internal/entities"
+	"context"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestApplication_Reconcile(t *testing.T) {
+	repo := newMockWalletRepo()
+	app, _ := newTestApplication(repo)
+	ctx := context.Background()
+
+	var wallets []entities.Wallet
+	for i := 0; i < 3; i++ {
+		wallet := entities.NewWallet()
+		wallet.Balance = decimal.NewFromInt(100)
+		wallet, err := app.CreateWallet(ctx, wallet)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		wallets = append(wallets, wallet)
+	}
+
+	report, err := app.Reconcile(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.WalletsChecked != 3 || !report.Consistent() {
+		t.Fatalf("expected 3 consistent wallets, got %d checked and %d drifts", report.WalletsChecked, len(report.Drifts))
+	}
+
+	// An update that bypasses the journal.
+	corrupted := repo.wallets[wallets[1].ID]
+	corrupted.Balance = decimal.NewFromInt(125)
+	_, err = repo.Update(ctx, corrupted)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	report, err = app.Reconcile(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(report.Drifts) != 1 {
+		t.Fatalf("expected 1 drift, got %d", len(report.Drifts))
+	}
+	drift := report.Drifts[0]
+	if drift.WalletId != corrupted.ID || !drift.Drift.Equal(decimal.NewFromInt(25)) {
+		t.Errorf("expected drift 25 on %s, got %s on %s", corrupted.ID, drift.Drift.String(), drift.WalletId)
+	}
+	if !report.DriftTotals[corrupted.Currency].Equal(decimal.NewFromInt(25)) {
+		t.Errorf("expected drift total 25, got %s", report.DriftTotals[corrupted.Currency].String())
+	}
+	if reconciliationDriftedWallets.Value() != 1 {
+		t.Errorf("expected drifted wallets metric 1, got %d", reconciliationDriftedWallets.Value())
+	}
+}
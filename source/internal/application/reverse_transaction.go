@@ -0,0 +1,149 @@
+package application
+
+import (
+	"TestProject/source/internal/entities"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// reversalTypes maps reversible operations to the operation type of the
+// compensating ledger entry.
+var reversalTypes = map[string]string{
+	deposit:  depositReversal,
+	withdraw: withdrawReversal,
+}
+
+// ReverseTransaction posts a compensating entry for a DEPOSIT or WITHDRAW
+// that references the original. amount reverses part of the original; nil
+// reverses whatever has not been reversed yet. The reversal refunds the
+// matching share of the original fee from the FEES account. Reversing a
+// deposit takes the money back and, like a withdrawal, fails with
+// ErrInsufficientFunds when the spendable balance cannot cover it.
+// adminOverride ignores holds and a frozen wallet, but still stops at the
+// -OverdraftLimit floor the database enforces: to claw back money already
+// spent, back office raises the overdraft limit first.
+func (a *Application) ReverseTransaction(ctx context.Context, transactionID string, amount *decimal.Decimal, adminOverride bool) (entities.Transaction, error) {
+	var (
+		reversal entities.Transaction
+		entry    entities.JournalEntry
+	)
+
+	err := a.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		original, err := a.TransactionRepo.GetByID(ctx, transactionID)
+		if err != nil {
+			return err
+		}
+
+		reversalType, ok := reversalTypes[original.OperationType]
+		if !ok {
+			return fmt.Errorf("%w: %s", entities.ErrNotReversible, original.OperationType)
+		}
+
+		reversal = entities.Transaction{
+			ID:            uuid.NewString(),
+			WalletId:      original.WalletId,
+			OperationType: reversalType,
+			ReferenceId:   original.ID,
+			CreatedAt:     time.Now().UTC().Truncate(time.Microsecond),
+		}
+
+		wallet, err := a.WalletRepo.UpdateWithLock(ctx, original.WalletId, func(w *entities.Wallet) error {
+			// Reversals of one transaction are serialized by the wallet lock.
+			reversed, err := a.TransactionRepo.SumByReference(ctx, original.ID, reversalType)
+			if err != nil {
+				return fmt.Errorf("error summing reversals: %w", err)
+			}
+			remaining := original.Amount.Sub(reversed)
+
+			reversal.Amount = remaining
+			if amount != nil {
+				reversal.Amount = *amount
+			}
+			if !reversal.Amount.IsPositive() || reversal.Amount.GreaterThan(remaining) {
+				return fmt.Errorf("%w: remaining %s", entities.ErrReversalExceedsOriginal, remaining.String())
+			}
+			// adminOverride lets back office reverse on a frozen wallet, but
+			// never on a closed one.
+			err = checkWalletStatus(*w, reversalType)
+			if err != nil && !(adminOverride && errors.Is(err, entities.ErrWalletFrozen)) {
+				return err
+			}
+			err = entities.ValidateAmount(reversal.Amount, w.Currency)
+			if err != nil {
+				return err
+			}
+
+			scale, err := entities.CurrencyScale(w.Currency)
+			if err != nil {
+				return err
+			}
+
+			reversal.Currency = w.Currency
+			reversal.Fee = feeRefund(original, reversed, reversal.Amount, scale).Neg()
+			reversal.BalanceBefore = w.Balance
+
+			// A reversal takes back only money the wallet can spend, net of
+			// the refunded fee. adminOverride ignores holds, but never goes
+			// below -OverdraftLimit.
+			if reversalType == depositReversal {
+				covered := w.Spendable()
+				if adminOverride {
+					covered = w.Balance.Add(w.OverdraftLimit)
+				}
+				if covered.LessThan(reversal.Amount.Add(reversal.Fee)) {
+					return entities.ErrInsufficientFunds
+				}
+			}
+
+			entry, err = singleWalletEntry(reversal)
+			if err != nil {
+				return err
+			}
+			err = applyJournal(entry, map[string]*entities.Wallet{w.ID: w})
+			if err != nil {
+				return err
+			}
+			return entities.CheckAmountRange(w.Balance)
+		})
+		if err != nil {
+			return err
+		}
+
+		reversal.Balance = wallet.Balance
+
+		reversal, err = a.recordTransaction(ctx, reversal)
+		if err != nil {
+			return fmt.Errorf("error recording transaction: %w", err)
+		}
+		err = a.JournalRepo.Create(ctx, entry)
+		if err != nil {
+			return fmt.Errorf("error recording journal entry: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return entities.Transaction{}, fmt.Errorf("error reversing transaction: %w", err)
+	}
+
+	return reversal, nil
+}
+
+// feeRefund returns the part of the original fee refunded by reversing amount
+// after reversed has already been reversed. Each reversal refunds the fee
+// share of everything reversed so far minus what earlier reversals refunded,
+// so partial reversals never round past the original fee and the one that
+// completes the original refunds exactly what is left.
+func feeRefund(original entities.Transaction, reversed, amount decimal.Decimal, scale int32) decimal.Decimal {
+	if !original.Fee.IsPositive() {
+		return decimal.Zero
+	}
+	share := func(part decimal.Decimal) decimal.Decimal {
+		return original.Fee.Mul(part).Div(original.Amount).Round(scale)
+	}
+	return share(reversed.Add(amount)).Sub(share(reversed))
+}
@@ -0,0 +1,293 @@
+package application
+
+import (
+	"TestProject/source/internal/entities"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestApplication_ReverseTransaction_PartialRefunds(t *testing.T) {
+	repo := newMockWalletRepo()
+	app, _ := newTestApplication(repo)
+	ctx := context.Background()
+
+	wallet := entities.NewWallet()
+	wallet.Balance = decimal.NewFromFloat(1000.0)
+	repo.wallets[wallet.ID] = wallet
+
+	withdrawal, err := app.ProcessTransaction(ctx, entities.Transaction{
+		WalletId:      wallet.ID,
+		OperationType: "WITHDRAW",
+		Amount:        decimal.NewFromFloat(300.0),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	first := decimal.NewFromFloat(100.0)
+	reversal, err := app.ReverseTransaction(ctx, withdrawal.ID, &first, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reversal.ReferenceId != withdrawal.ID || reversal.OperationType != "WITHDRAW_REVERSAL" {
+		t.Errorf("unexpected reversal: %+v", reversal)
+	}
+	if !reversal.Balance.Equal(decimal.NewFromFloat(800.0)) {
+		t.Errorf("expected balance 800.0, got %s", reversal.Balance.String())
+	}
+
+	tooMuch := decimal.NewFromFloat(250.0)
+	_, err = app.ReverseTransaction(ctx, withdrawal.ID, &tooMuch, false)
+	if !errors.Is(err, entities.ErrReversalExceedsOriginal) {
+		t.Errorf("expected ErrReversalExceedsOriginal, got %v", err)
+	}
+
+	rest, err := app.ReverseTransaction(ctx, withdrawal.ID, nil, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !rest.Amount.Equal(decimal.NewFromFloat(200.0)) {
+		t.Errorf("expected remaining reversal 200.0, got %s", rest.Amount.String())
+	}
+
+	_, err = app.ReverseTransaction(ctx, withdrawal.ID, nil, false)
+	if !errors.Is(err, entities.ErrReversalExceedsOriginal) {
+		t.Errorf("expected fully reversed transaction to be rejected, got %v", err)
+	}
+
+	if !repo.wallets[wallet.ID].Balance.Equal(decimal.NewFromFloat(1000.0)) {
+		t.Errorf("expected balance 1000.0, got %s", repo.wallets[wallet.ID].Balance.String())
+	}
+}
+
+func TestApplication_ReverseTransaction_DepositInsufficientFunds(t *testing.T) {
+	repo := newMockWalletRepo()
+	app, _ := newTestApplication(repo)
+	ctx := context.Background()
+
+	wallet := entities.NewWallet()
+	repo.wallets[wallet.ID] = wallet
+
+	deposit, err := app.ProcessTransaction(ctx, entities.Transaction{
+		WalletId:      wallet.ID,
+		OperationType: "DEPOSIT",
+		Amount:        decimal.NewFromFloat(500.0),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = app.ProcessTransaction(ctx, entities.Transaction{
+		WalletId:      wallet.ID,
+		OperationType: "WITHDRAW",
+		Amount:        decimal.NewFromFloat(400.0),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = app.ReverseTransaction(ctx, deposit.ID, nil, false)
+	if !errors.Is(err, entities.ErrInsufficientFunds) {
+		t.Fatalf("expected ErrInsufficientFunds, got %v", err)
+	}
+
+	// Like a withdrawal, the reversal may use the overdraft.
+	_, err = app.SetOverdraftLimit(ctx, wallet.ID, decimal.NewFromFloat(400.0))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reversal, err := app.ReverseTransaction(ctx, deposit.ID, nil, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reversal.Balance.Equal(decimal.NewFromFloat(-400.0)) {
+		t.Errorf("expected balance -400.0, got %s", reversal.Balance.String())
+	}
+}
+
+func TestApplication_ReverseTransaction_RefundsFee(t *testing.T) {
+	repo := newMockWalletRepo()
+	app, _ := newTestApplication(repo)
+	ctx := context.Background()
+
+	wallet := entities.NewWallet()
+	wallet.Currency = "USD"
+	repo.wallets[wallet.ID] = wallet
+
+	for _, operationType := range []string{"DEPOSIT", "WITHDRAW"} {
+		_, err := app.SetFeeRule(ctx, entities.FeeRule{
+			OperationType: operationType,
+			Currency:      "USD",
+			Type:          entities.FeeFlat,
+			Flat:          dec("1"),
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	feesBalance := func() decimal.Decimal {
+		balances, err := app.SystemAccountBalances(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, balance := range balances {
+			if balance.AccountId == entities.SystemFees {
+				return balance.Balance
+			}
+		}
+		return decimal.Zero
+	}
+
+	deposit, err := app.ProcessTransaction(ctx, entities.Transaction{WalletId: wallet.ID, OperationType: "DEPOSIT", Amount: dec("100")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	withdrawal, err := app.ProcessTransaction(ctx, entities.Transaction{WalletId: wallet.ID, OperationType: "WITHDRAW", Amount: dec("30")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 100 - 1 - 30 - 1
+	if !withdrawal.Balance.Equal(dec("68")) {
+		t.Fatalf("expected balance 68, got %s", withdrawal.Balance.String())
+	}
+
+	// A third of the withdrawal refunds a third of its fee, rounded to cents;
+	// the rest refunds what is left so the whole fee comes back.
+	part := dec("10")
+	first, err := app.ReverseTransaction(ctx, withdrawal.ID, &part, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !first.Fee.Equal(dec("-0.33")) || !first.Balance.Equal(dec("78.33")) {
+		t.Errorf("expected fee -0.33 and balance 78.33, got %s and %s", first.Fee.String(), first.Balance.String())
+	}
+	rest, err := app.ReverseTransaction(ctx, withdrawal.ID, nil, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !rest.Fee.Equal(dec("-0.67")) || !rest.Balance.Equal(dec("99")) {
+		t.Errorf("expected fee -0.67 and balance 99, got %s and %s", rest.Fee.String(), rest.Balance.String())
+	}
+
+	// The wallet holds 99 after the deposit fee, which is enough to reverse
+	// the deposit of 100 once the fee is refunded.
+	reversal, err := app.ReverseTransaction(ctx, deposit.ID, nil, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reversal.Fee.Equal(dec("-1")) || !reversal.Balance.IsZero() {
+		t.Errorf("expected fee -1 and balance 0, got %s and %s", reversal.Fee.String(), reversal.Balance.String())
+	}
+
+	if fees := feesBalance(); !fees.IsZero() {
+		t.Errorf("expected all fees refunded from %s, got %s", entities.SystemFees, fees.String())
+	}
+}
+
+func TestApplication_ReverseTransaction_OverrideStopsAtOverdraftFloor(t *testing.T) {
+	repo := newMockWalletRepo()
+	app, _ := newTestApplication(repo)
+	ctx := context.Background()
+
+	// Without an overdraft the override cannot take the balance below zero;
+	// back office raises the overdraft limit first to claw back spent money.
+	wallet := entities.NewWallet()
+	repo.wallets[wallet.ID] = wallet
+
+	deposit, err := app.ProcessTransaction(ctx, entities.Transaction{
+		WalletId:      wallet.ID,
+		OperationType: "DEPOSIT",
+		Amount:        decimal.NewFromFloat(500.0),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = app.ProcessTransaction(ctx, entities.Transaction{
+		WalletId:      wallet.ID,
+		OperationType: "WITHDRAW",
+		Amount:        decimal.NewFromFloat(200.0),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = app.ReverseTransaction(ctx, deposit.ID, nil, true)
+	if !errors.Is(err, entities.ErrInsufficientFunds) {
+		t.Fatalf("expected ErrInsufficientFunds, got %v", err)
+	}
+
+	_, err = app.SetOverdraftLimit(ctx, wallet.ID, decimal.NewFromFloat(200.0))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reversal, err := app.ReverseTransaction(ctx, deposit.ID, nil, true)
+	if err != nil {
+		t.Fatalf("unexpected error with admin override: %v", err)
+	}
+	if !reversal.Balance.Equal(decimal.NewFromFloat(-200.0)) {
+		t.Errorf("expected balance -200.0, got %s", reversal.Balance.String())
+	}
+}
+
+func TestApplication_ReverseTransaction_OverrideIgnoresHolds(t *testing.T) {
+	app, repo, _, wallet := newHoldTestApplication(0)
+	ctx := context.Background()
+
+	deposit, err := app.ProcessTransaction(ctx, entities.Transaction{
+		WalletId:      wallet.ID,
+		OperationType: "DEPOSIT",
+		Amount:        decimal.NewFromFloat(500.0),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	hold := entities.NewHold()
+	hold.WalletId = wallet.ID
+	hold.Amount = decimal.NewFromFloat(300.0)
+	_, err = app.CreateHold(ctx, hold)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = app.ReverseTransaction(ctx, deposit.ID, nil, false)
+	if !errors.Is(err, entities.ErrInsufficientFunds) {
+		t.Fatalf("expected ErrInsufficientFunds, got %v", err)
+	}
+
+	reversal, err := app.ReverseTransaction(ctx, deposit.ID, nil, true)
+	if err != nil {
+		t.Fatalf("unexpected error with admin override: %v", err)
+	}
+	if !reversal.Balance.IsZero() {
+		t.Errorf("expected balance 0, got %s", reversal.Balance.String())
+	}
+	if !repo.wallets[wallet.ID].Reserved.Equal(decimal.NewFromFloat(300.0)) {
+		t.Errorf("expected the hold to stay reserved, got %s", repo.wallets[wallet.ID].Reserved.String())
+	}
+}
+
+func TestApplication_ReverseTransaction_NotReversible(t *testing.T) {
+	repo := newMockWalletRepo()
+	app, ledger := newTestApplication(repo)
+
+	ledger.transactions = append(ledger.transactions, entities.Transaction{
+		ID:            "reversal-id",
+		OperationType: "DEPOSIT_REVERSAL",
+		Amount:        decimal.NewFromFloat(10.0),
+	})
+
+	_, err := app.ReverseTransaction(context.Background(), "reversal-id", nil, false)
+	if !errors.Is(err, entities.ErrNotReversible) {
+		t.Fatalf("expected ErrNotReversible, got %v", err)
+	}
+
+	_, err = app.ReverseTransaction(context.Background(), "missing-id", nil, false)
+	if !errors.Is(err, entities.ErrTransactionNotFound) {
+		t.Fatalf("expected ErrTransactionNotFound, got %v", err)
+	}
+}
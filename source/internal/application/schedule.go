@@ -0,0 +1,234 @@
+package application
+
+import (
+	"TestProject/source/internal/entities"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/fx"
+)
+
+const scheduleBatchSize = 100
+
+// CreateSchedule registers a future-dated or recurring DEPOSIT or WITHDRAW.
+// Without StartAt the first run is due immediately.
+func (a *Application) CreateSchedule(ctx context.Context, schedule entities.Schedule) (entities.Schedule, error) {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	if schedule.OperationType != deposit && schedule.OperationType != withdraw {
+		return entities.Schedule{}, fmt.Errorf("%w: %s", entities.ErrInvalidOperation, schedule.OperationType)
+	}
+	switch schedule.Frequency {
+	case entities.FrequencyOnce, entities.FrequencyDaily, entities.FrequencyWeekly, entities.FrequencyMonthly:
+	default:
+		return entities.Schedule{}, fmt.Errorf("%w: frequency %s", entities.ErrInvalidSchedule, schedule.Frequency)
+	}
+	if !schedule.Amount.IsPositive() {
+		return entities.Schedule{}, fmt.Errorf("%w: amount must be greater than zero", entities.ErrInvalidAmount)
+	}
+	if schedule.StartAt.IsZero() {
+		schedule.StartAt = now
+	}
+	schedule.StartAt = schedule.StartAt.UTC().Truncate(time.Microsecond)
+	if schedule.EndAt != nil && schedule.EndAt.Before(schedule.StartAt) {
+		return entities.Schedule{}, fmt.Errorf("%w: end_at is before start_at", entities.ErrInvalidSchedule)
+	}
+
+	wallet, err := a.WalletRepo.GetByID(ctx, schedule.WalletId)
+	if err != nil {
+		return entities.Schedule{}, err
+	}
+	if schedule.Currency != "" && schedule.Currency != wallet.Currency {
+		return entities.Schedule{}, fmt.Errorf("%w: wallet is %s, got %s", entities.ErrCurrencyMismatch, wallet.Currency, schedule.Currency)
+	}
+	err = entities.ValidateAmount(schedule.Amount, wallet.Currency)
+	if err != nil {
+		return entities.Schedule{}, err
+	}
+
+	schedule.Currency = wallet.Currency
+	schedule.Status = entities.ScheduleActive
+	schedule.Occurrence = 0
+	schedule.NextRunAt = schedule.StartAt
+	schedule.CreatedAt = now
+	schedule.UpdatedAt = now
+
+	schedule, err = a.ScheduleRepo.Create(ctx, schedule)
+	if err != nil {
+		return entities.Schedule{}, fmt.Errorf("error creating schedule: %w", err)
+	}
+	return schedule, nil
+}
+
+// PauseSchedule stops an active schedule from running until it is resumed.
+func (a *Application) PauseSchedule(ctx context.Context, scheduleID string) (entities.Schedule, error) {
+	return a.changeSchedule(ctx, scheduleID, func(s *entities.Schedule, now time.Time) error {
+		if s.Status != entities.ScheduleActive {
+			return fmt.Errorf("%w: schedule is %s", entities.ErrScheduleNotActive, s.Status)
+		}
+		s.Status = entities.SchedulePaused
+		return nil
+	})
+}
+
+// ResumeSchedule reactivates a paused schedule. Recurring runs missed while
+// it was paused are skipped; a paused one-off run becomes due immediately.
+func (a *Application) ResumeSchedule(ctx context.Context, scheduleID string) (entities.Schedule, error) {
+	return a.changeSchedule(ctx, scheduleID, func(s *entities.Schedule, now time.Time) error {
+		if s.Status != entities.SchedulePaused {
+			return fmt.Errorf("%w: schedule is %s", entities.ErrScheduleNotActive, s.Status)
+		}
+		s.Status = entities.ScheduleActive
+		if s.Frequency != entities.FrequencyOnce && !s.NextRunAt.After(now) {
+			s.Advance(now)
+		}
+		return nil
+	})
+}
+
+// CancelSchedule stops a schedule for good.
+func (a *Application) CancelSchedule(ctx context.Context, scheduleID string) (entities.Schedule, error) {
+	return a.changeSchedule(ctx, scheduleID, func(s *entities.Schedule, now time.Time) error {
+		if s.Status != entities.ScheduleActive && s.Status != entities.SchedulePaused {
+			return fmt.Errorf("%w: schedule is %s", entities.ErrScheduleNotActive, s.Status)
+		}
+		s.Status = entities.ScheduleCancelled
+		return nil
+	})
+}
+
+// changeSchedule applies fn to the locked schedule. The lock waits for a
+// worker that is running the schedule, so a run and a status change never
+// interleave.
+func (a *Application) changeSchedule(ctx context.Context, scheduleID string, fn func(*entities.Schedule, time.Time) error) (entities.Schedule, error) {
+	var schedule entities.Schedule
+
+	err := a.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		schedule, err = a.ScheduleRepo.GetByIDForUpdate(ctx, scheduleID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now().UTC().Truncate(time.Microsecond)
+		err = fn(&schedule, now)
+		if err != nil {
+			return err
+		}
+		schedule.UpdatedAt = now
+
+		schedule, err = a.ScheduleRepo.Update(ctx, schedule)
+		if err != nil {
+			return fmt.Errorf("error updating schedule: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return entities.Schedule{}, fmt.Errorf("error changing schedule: %w", err)
+	}
+
+	return schedule, nil
+}
+
+// RunDueSchedules runs every schedule that is due and returns how many runs
+// it made.
+func (a *Application) RunDueSchedules(ctx context.Context) (int, error) {
+	runs := 0
+
+	for runs < scheduleBatchSize {
+		ran, err := a.runNextSchedule(ctx)
+		if err != nil {
+			return runs, err
+		}
+		if !ran {
+			break
+		}
+		runs++
+	}
+
+	return runs, nil
+}
+
+// runNextSchedule claims one due schedule, runs it, records the run and
+// advances the schedule, all in one database transaction. The claim keeps
+// the schedule row locked until commit and other workers skip locked rows,
+// so a run can't happen twice. A failed operation, such as insufficient
+// funds, is recorded and the schedule still advances; a database failure
+// rolls everything back and the run is retried on the next tick.
+func (a *Application) runNextSchedule(ctx context.Context) (bool, error) {
+	ran := false
+
+	err := a.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		now := time.Now().UTC().Truncate(time.Microsecond)
+
+		schedule, err := a.ScheduleRepo.ClaimDue(ctx, now)
+		if errors.Is(err, entities.ErrScheduleNotFound) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error claiming schedule: %w", err)
+		}
+
+		run := entities.ScheduleRun{
+			ID:          uuid.NewString(),
+			ScheduleId:  schedule.ID,
+			Occurrence:  schedule.Occurrence,
+			ScheduledAt: schedule.NextRunAt,
+			CreatedAt:   now,
+		}
+
+		transaction, err := a.ProcessTransaction(ctx, entities.Transaction{
+			WalletId:      schedule.WalletId,
+			OperationType: schedule.OperationType,
+			Currency:      schedule.Currency,
+			Amount:        schedule.Amount,
+			ReferenceId:   schedule.ID,
+		})
+		if err != nil {
+			run.Status = entities.RunFailed
+			run.Error = err.Error()
+		} else {
+			run.Status = entities.RunSucceeded
+			run.TransactionId = transaction.ID
+		}
+
+		_, err = a.ScheduleRepo.CreateRun(ctx, run)
+		if err != nil {
+			return fmt.Errorf("error recording schedule run: %w", err)
+		}
+
+		schedule.Advance(now)
+		schedule.UpdatedAt = now
+		_, err = a.ScheduleRepo.Update(ctx, schedule)
+		if err != nil {
+			return fmt.Errorf("error updating schedule: %w", err)
+		}
+
+		ran = true
+		return nil
+	})
+
+	return ran, err
+}
+
+// RegisterScheduleWorker periodically runs due schedules. A zero poll
+// interval disables it.
+func RegisterScheduleWorker(lc fx.Lifecycle, app *Application) {
+	if app.schedulePollInterval <= 0 {
+		return
+	}
+
+	runPeriodically(lc, app.schedulePollInterval, func(ctx context.Context) {
+		runs, err := app.RunDueSchedules(ctx)
+		if err != nil {
+			app.log.ErrorContext(ctx, "error running schedules", slog.String("error", err.Error()))
+		}
+		if runs > 0 {
+			app.log.InfoContext(ctx, "ran schedules", slog.Int("runs", runs))
+		}
+	})
+}
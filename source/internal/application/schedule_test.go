@@ -0,0 +1,247 @@
+package application
+
+import (
+	"TestProject/source/internal/entities"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type mockScheduleRepo struct {
+	mu        sync.Mutex
+	schedules map[string]entities.Schedule
+	runs      []entities.ScheduleRun
+}
+
+func newMockScheduleRepo() *mockScheduleRepo {
+	return &mockScheduleRepo{schedules: make(map[string]entities.Schedule)}
+}
+
+func (m *mockScheduleRepo) Create(ctx context.Context, schedule entities.Schedule) (entities.Schedule, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.schedules[schedule.ID] = schedule
+	return schedule, nil
+}
+
+func (m *mockScheduleRepo) GetByID(ctx context.Context, scheduleID string) (entities.Schedule, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	schedule, ok := m.schedules[scheduleID]
+	if !ok {
+		return entities.Schedule{}, entities.ErrScheduleNotFound
+	}
+	return schedule, nil
+}
+
+func (m *mockScheduleRepo) GetByIDForUpdate(ctx context.Context, scheduleID string) (entities.Schedule, error) {
+	return m.GetByID(ctx, scheduleID)
+}
+
+func (m *mockScheduleRepo) ClaimDue(ctx context.Context, now time.Time) (entities.Schedule, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var due *entities.Schedule
+	for _, schedule := range m.schedules {
+		if schedule.Status != entities.ScheduleActive || schedule.NextRunAt.After(now) {
+			continue
+		}
+		if due == nil || schedule.NextRunAt.Before(due.NextRunAt) {
+			due = &schedule
+		}
+	}
+	if due == nil {
+		return entities.Schedule{}, entities.ErrScheduleNotFound
+	}
+	return *due, nil
+}
+
+func (m *mockScheduleRepo) Update(ctx context.Context, schedule entities.Schedule) (entities.Schedule, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.schedules[schedule.ID] = schedule
+	return schedule, nil
+}
+
+func (m *mockScheduleRepo) CreateRun(ctx context.Context, run entities.ScheduleRun) (entities.ScheduleRun, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, existing := range m.runs {
+		if existing.ScheduleId == run.ScheduleId && existing.Occurrence == run.Occurrence {
+			return entities.ScheduleRun{}, errors.New("duplicate schedule run")
+		}
+	}
+	m.runs = append(m.runs, run)
+	return run, nil
+}
+
+func (m *mockScheduleRepo) ListRuns(ctx context.Context, scheduleID string, limit int) ([]entities.ScheduleRun, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var result []entities.ScheduleRun
+	for i := len(m.runs) - 1; i >= 0 && len(result) < limit; i-- {
+		if m.runs[i].ScheduleId == scheduleID {
+			result = append(result, m.runs[i])
+		}
+	}
+	return result, nil
+}
+
+func newScheduleTestApplication(balance int64) (*Application, *mockWalletRepo, *mockScheduleRepo, entities.Wallet) {
+	repo := newMockWalletRepo()
+	app, _ := newTestApplication(repo)
+	schedules := newMockScheduleRepo()
+	app.ScheduleRepo = schedules
+
+	wallet := entities.NewWallet()
+	wallet.Balance = decimal.NewFromInt(balance)
+	repo.wallets[wallet.ID] = wallet
+
+	return app, repo, schedules, wallet
+}
+
+func TestSchedule_OccurrenceAt_MonthlyKeepsDayOfMonth(t *testing.T) {
+	schedule := entities.Schedule{
+		Frequency: entities.FrequencyMonthly,
+		StartAt:   time.Date(2024, time.January, 31, 9, 0, 0, 0, time.UTC),
+	}
+
+	expected := []time.Time{
+		time.Date(2024, time.January, 31, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, time.February, 29, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, time.March, 31, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, time.April, 30, 9, 0, 0, 0, time.UTC),
+	}
+	for n, want := range expected {
+		if got := schedule.OccurrenceAt(n); !got.Equal(want) {
+			t.Errorf("occurrence %d: expected %s, got %s", n, want, got)
+		}
+	}
+}
+
+func TestApplication_RunDueSchedules(t *testing.T) {
+	app, repo, schedules, wallet := newScheduleTestApplication(0)
+	ctx := context.Background()
+
+	schedule := entities.NewSchedule()
+	schedule.WalletId = wallet.ID
+	schedule.OperationType = "DEPOSIT"
+	schedule.Amount = decimal.NewFromInt(100)
+	schedule.Frequency = entities.FrequencyMonthly
+	schedule.StartAt = time.Now().Add(-time.Minute)
+	schedule, err := app.CreateSchedule(ctx, schedule)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	runs, err := app.RunDueSchedules(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if runs != 1 {
+		t.Fatalf("expected 1 run, got %d", runs)
+	}
+	if !repo.wallets[wallet.ID].Balance.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("expected balance 100, got %s", repo.wallets[wallet.ID].Balance.String())
+	}
+
+	run := schedules.runs[0]
+	if run.Status != entities.RunSucceeded || run.TransactionId == "" || run.Occurrence != 0 {
+		t.Errorf("unexpected run %+v", run)
+	}
+
+	stored := schedules.schedules[schedule.ID]
+	if stored.Occurrence != 1 || !stored.NextRunAt.Equal(schedule.OccurrenceAt(1)) {
+		t.Errorf("expected schedule to advance to occurrence 1, got %d at %s", stored.Occurrence, stored.NextRunAt)
+	}
+
+	runs, err = app.RunDueSchedules(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if runs != 0 {
+		t.Errorf("expected no runs before the next occurrence, got %d", runs)
+	}
+}
+
+func TestApplication_RunDueSchedules_RecordsFailure(t *testing.T) {
+	app, _, schedules, wallet := newScheduleTestApplication(10)
+	ctx := context.Background()
+
+	schedule := entities.NewSchedule()
+	schedule.WalletId = wallet.ID
+	schedule.OperationType = "WITHDRAW"
+	schedule.Amount = decimal.NewFromInt(50)
+	schedule.Frequency = entities.FrequencyOnce
+	schedule, err := app.CreateSchedule(ctx, schedule)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = app.RunDueSchedules(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(schedules.runs) != 1 || schedules.runs[0].Status != entities.RunFailed || schedules.runs[0].Error == "" {
+		t.Fatalf("expected one failed run, got %+v", schedules.runs)
+	}
+	if status := schedules.schedules[schedule.ID].Status; status != entities.ScheduleCompleted {
+		t.Errorf("expected one-off schedule to complete, got %s", status)
+	}
+}
+
+func TestApplication_PauseResumeCancelSchedule(t *testing.T) {
+	app, _, schedules, wallet := newScheduleTestApplication(0)
+	ctx := context.Background()
+
+	schedule := entities.NewSchedule()
+	schedule.WalletId = wallet.ID
+	schedule.OperationType = "DEPOSIT"
+	schedule.Amount = decimal.NewFromInt(10)
+	schedule.Frequency = entities.FrequencyDaily
+	schedule.StartAt = time.Now().Add(-72 * time.Hour)
+	schedule, err := app.CreateSchedule(ctx, schedule)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = app.PauseSchedule(ctx, schedule.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	runs, _ := app.RunDueSchedules(ctx)
+	if runs != 0 {
+		t.Fatalf("expected paused schedule not to run, got %d runs", runs)
+	}
+
+	resumed, err := app.ResumeSchedule(ctx, schedule.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resumed.NextRunAt.After(time.Now()) {
+		t.Errorf("expected missed runs to be skipped, next run at %s", resumed.NextRunAt)
+	}
+
+	_, err = app.CancelSchedule(ctx, schedule.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = app.ResumeSchedule(ctx, schedule.ID)
+	if !errors.Is(err, entities.ErrScheduleNotActive) {
+		t.Errorf("expected ErrScheduleNotActive, got %v", err)
+	}
+	if len(schedules.runs) != 0 {
+		t.Errorf("expected no runs, got %d", len(schedules.runs))
+	}
+}
@@ -0,0 +1,105 @@
+package application
+
+import (
+	"TestProject/source/internal/entities"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/fx"
+)
+
+// snapshotGrace keeps a finished day open for a while, so operations that
+// started before midnight and commit after it still land in its snapshot.
+const snapshotGrace = 5 * time.Minute
+
+// GetBalanceAt rebuilds the balance of a wallet at asOf from the latest
+// daily snapshot before it plus the postings made since that snapshot.
+func (a *Application) GetBalanceAt(ctx context.Context, walletID string, asOf time.Time) (entities.HistoricalBalance, error) {
+	asOf = asOf.UTC()
+	if asOf.After(time.Now()) {
+		return entities.HistoricalBalance{}, entities.ErrAsOfInFuture
+	}
+
+	wallet, err := a.WalletRepo.GetByID(ctx, walletID)
+	if err != nil {
+		return entities.HistoricalBalance{}, err
+	}
+
+	balance := decimal.Zero
+	var from time.Time
+
+	snapshot, err := a.SnapshotRepo.Latest(ctx, walletID, asOf)
+	switch {
+	case err == nil:
+		balance = snapshot.Balance
+		from = snapshot.Day.AddDate(0, 0, 1)
+	case !errors.Is(err, entities.ErrSnapshotNotFound):
+		return entities.HistoricalBalance{}, fmt.Errorf("error getting balance snapshot: %w", err)
+	}
+
+	sum, err := a.JournalRepo.WalletPostingsSum(ctx, walletID, from, asOf)
+	if err != nil {
+		return entities.HistoricalBalance{}, fmt.Errorf("error summing postings: %w", err)
+	}
+
+	return entities.HistoricalBalance{
+		WalletId: wallet.ID,
+		Currency: wallet.Currency,
+		Balance:  balance.Add(sum),
+		AsOf:     asOf,
+	}, nil
+}
+
+// TakeBalanceSnapshots stores daily snapshots for every finished day not
+// taken yet and returns the number of days taken. The first run snapshots
+// only the last finished day, from the whole journal.
+func (a *Application) TakeBalanceSnapshots(ctx context.Context, now time.Time) (int, error) {
+	cutoff := now.UTC().Add(-snapshotGrace).Truncate(24 * time.Hour)
+
+	var day, from time.Time
+
+	last, err := a.SnapshotRepo.LastDay(ctx)
+	switch {
+	case err == nil:
+		day = last.AddDate(0, 0, 1)
+		from = day
+	case errors.Is(err, entities.ErrSnapshotNotFound):
+		day = cutoff.AddDate(0, 0, -1)
+	default:
+		return 0, fmt.Errorf("error getting last snapshot day: %w", err)
+	}
+
+	days := 0
+	for ; day.Before(cutoff); day = day.AddDate(0, 0, 1) {
+		_, err = a.SnapshotRepo.TakeDay(ctx, day, from)
+		if err != nil {
+			return days, fmt.Errorf("error taking snapshots for %s: %w", day.Format(time.DateOnly), err)
+		}
+		from = day.AddDate(0, 0, 1)
+		days++
+	}
+
+	return days, nil
+}
+
+// RegisterBalanceSnapshots periodically takes daily balance snapshots. A
+// zero interval disables the job.
+func RegisterBalanceSnapshots(lc fx.Lifecycle, app *Application) {
+	if app.snapshotInterval <= 0 {
+		return
+	}
+
+	runPeriodically(lc, app.snapshotInterval, func(ctx context.Context) {
+		days, err := app.TakeBalanceSnapshots(ctx, time.Now())
+		if err != nil {
+			app.log.ErrorContext(ctx, "error taking balance snapshots", slog.String("error", err.Error()))
+		}
+		if days > 0 {
+			app.log.InfoContext(ctx, "balance snapshots taken", slog.Int("days", days))
+		}
+	})
+}
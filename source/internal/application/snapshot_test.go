@@ -0,0 +1,151 @@
+package application
+
+import (
+	"TestProject/source/internal/entities"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type takenDay struct {
+	day  time.Time
+	from time.Time
+}
+
+type mockSnapshotRepo struct {
+	snapshots []entities.BalanceSnapshot
+	taken     []takenDay
+}
+
+func (m *mockSnapshotRepo) TakeDay(ctx context.Context, day, from time.Time) (int64, error) {
+	m.taken = append(m.taken, takenDay{day: day, from: from})
+	return 0, nil
+}
+
+func (m *mockSnapshotRepo) LastDay(ctx context.Context) (time.Time, error) {
+	if len(m.taken) == 0 {
+		return time.Time{}, entities.ErrSnapshotNotFound
+	}
+	return m.taken[len(m.taken)-1].day, nil
+}
+
+func (m *mockSnapshotRepo) Latest(ctx context.Context, walletID string, before time.Time) (entities.BalanceSnapshot, error) {
+	var latest *entities.BalanceSnapshot
+	for i, snapshot := range m.snapshots {
+		if snapshot.WalletId != walletID || snapshot.Day.AddDate(0, 0, 1).After(before) {
+			continue
+		}
+		if latest == nil || snapshot.Day.After(latest.Day) {
+			latest = &m.snapshots[i]
+		}
+	}
+	if latest == nil {
+		return entities.BalanceSnapshot{}, entities.ErrSnapshotNotFound
+	}
+	return *latest, nil
+}
+
+func walletEntry(walletID string, amount float64, createdAt time.Time) entities.JournalEntry {
+	return entities.JournalEntry{
+		ID:            createdAt.String(),
+		OperationType: "DEPOSIT",
+		Postings: []entities.Posting{
+			entities.WalletPosting(walletID, "USD", decimal.NewFromFloat(amount)),
+		},
+		CreatedAt: createdAt,
+	}
+}
+
+func TestApplication_GetBalanceAt(t *testing.T) {
+	repo := newMockWalletRepo()
+	app, _ := newTestApplication(repo)
+	journal := app.JournalRepo.(*mockJournalRepo)
+	snapshots := &mockSnapshotRepo{}
+	app.SnapshotRepo = snapshots
+	ctx := context.Background()
+
+	wallet := entities.NewWallet()
+	repo.wallets[wallet.ID] = wallet
+
+	jan30 := time.Date(2025, time.January, 30, 0, 0, 0, 0, time.UTC)
+	journal.entries = []entities.JournalEntry{
+		walletEntry(wallet.ID, 100, jan30.Add(10*time.Hour)),
+		walletEntry(wallet.ID, 50, jan30.Add(34*time.Hour)),
+		walletEntry(wallet.ID, -30, jan30.Add(58*time.Hour)),
+	}
+
+	monthEnd := time.Date(2025, time.January, 31, 23, 59, 59, 0, time.UTC)
+	balance, err := app.GetBalanceAt(ctx, wallet.ID, monthEnd)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !balance.Balance.Equal(decimal.NewFromFloat(150.0)) {
+		t.Errorf("expected balance 150.0 from the journal, got %s", balance.Balance.String())
+	}
+
+	// The snapshot replaces the postings up to the end of its day. Its
+	// balance differs from the journal so that double counting shows up.
+	snapshots.snapshots = []entities.BalanceSnapshot{
+		{WalletId: wallet.ID, Day: jan30, Balance: decimal.NewFromFloat(1000.0)},
+	}
+
+	balance, err = app.GetBalanceAt(ctx, wallet.ID, monthEnd)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !balance.Balance.Equal(decimal.NewFromFloat(1050.0)) {
+		t.Errorf("expected balance 1050.0 from the snapshot, got %s", balance.Balance.String())
+	}
+
+	balance, err = app.GetBalanceAt(ctx, wallet.ID, jan30.Add(12*time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !balance.Balance.Equal(decimal.NewFromFloat(100.0)) {
+		t.Errorf("expected the snapshot to be ignored before its day ends, got %s", balance.Balance.String())
+	}
+
+	_, err = app.GetBalanceAt(ctx, wallet.ID, time.Now().Add(time.Hour))
+	if !errors.Is(err, entities.ErrAsOfInFuture) {
+		t.Errorf("expected ErrAsOfInFuture, got %v", err)
+	}
+}
+
+func TestApplication_TakeBalanceSnapshots(t *testing.T) {
+	app, _ := newTestApplication(newMockWalletRepo())
+	snapshots := &mockSnapshotRepo{}
+	app.SnapshotRepo = snapshots
+	ctx := context.Background()
+
+	now := time.Date(2025, time.March, 10, 0, 2, 0, 0, time.UTC)
+	days, err := app.TakeBalanceSnapshots(ctx, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Inside the grace period March 9 is still open.
+	if days != 1 {
+		t.Fatalf("expected 1 day, got %d", days)
+	}
+	first := snapshots.taken[0]
+	if !first.day.Equal(time.Date(2025, time.March, 8, 0, 0, 0, 0, time.UTC)) || !first.from.IsZero() {
+		t.Errorf("expected the first run to take March 8 from the whole journal, got %+v", first)
+	}
+
+	days, err = app.TakeBalanceSnapshots(ctx, now.Add(49*time.Hour))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if days != 3 {
+		t.Fatalf("expected 3 days, got %d", days)
+	}
+	for i, taken := range snapshots.taken[1:] {
+		day := time.Date(2025, time.March, 9+i, 0, 0, 0, 0, time.UTC)
+		if !taken.day.Equal(day) || !taken.from.Equal(day) {
+			t.Errorf("expected day %s from its start, got %+v", day.Format(time.DateOnly), taken)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package application
+
+import (
+	"TestProject/source/internal/entities"
+	"context"
+	"fmt"
+	"time"
+)
+
+// StatementWriter receives a statement while it is read from the ledger, so
+// that it can be streamed without keeping the lines in memory.
+type StatementWriter interface {
+	// Begin gets the statement with its opening balance.
+	Begin(statement entities.Statement) error
+	Line(line entities.StatementLine) error
+	// End gets the statement with its closing balance and totals.
+	End(statement entities.Statement) error
+}
+
+// WriteStatement writes the operations of a wallet created in [from, to),
+// oldest first. The opening balance is the balance right before from; the
+// running balance adds each operation and its fee to it.
+func (a *Application) WriteStatement(ctx context.Context, walletID string, from, to time.Time, w StatementWriter) error {
+	from, to = from.UTC(), to.UTC()
+	if !from.Before(to) {
+		return fmt.Errorf("%w: from must be before to", entities.ErrInvalidStatementPeriod)
+	}
+	if now := time.Now().UTC(); to.After(now) {
+		to = now
+	}
+
+	// Timestamps are stored with microsecond precision, so this is the
+	// last instant before the period.
+	opening, err := a.GetBalanceAt(ctx, walletID, from.Add(-time.Microsecond))
+	if err != nil {
+		return err
+	}
+
+	statement := entities.Statement{
+		WalletId:       walletID,
+		Currency:       opening.Currency,
+		From:           from,
+		To:             to,
+		OpeningBalance: opening.Balance,
+		ClosingBalance: opening.Balance,
+		Totals:         make(map[string]entities.StatementTotal),
+	}
+
+	err = w.Begin(statement)
+	if err != nil {
+		return err
+	}
+
+	err = a.TransactionRepo.Stream(ctx, walletID, from, to, func(transaction entities.Transaction) error {
+		change := transaction.Amount
+		if !walletCredits(transaction.OperationType) {
+			change = change.Neg()
+		}
+		change = change.Sub(transaction.Fee)
+		statement.ClosingBalance = statement.ClosingBalance.Add(change)
+
+		total := statement.Totals[transaction.OperationType]
+		total.Count++
+		total.Amount = total.Amount.Add(transaction.Amount)
+		statement.Totals[transaction.OperationType] = total
+
+		if !transaction.Fee.IsZero() {
+			fees := statement.Totals[entities.StatementFeeTotal]
+			fees.Count++
+			fees.Amount = fees.Amount.Add(transaction.Fee)
+			statement.Totals[entities.StatementFeeTotal] = fees
+		}
+
+		return w.Line(entities.StatementLine{
+			Transaction:    transaction,
+			Change:         change,
+			RunningBalance: statement.ClosingBalance,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("error streaming transactions: %w", err)
+	}
+
+	return w.End(statement)
+}
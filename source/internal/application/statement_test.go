@@ -0,0 +1,109 @@
+package application
+
+import (
+	"TestProject/source/internal/entities"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type recordingStatementWriter struct {
+	begin entities.Statement
+	lines []entities.StatementLine
+	end   entities.Statement
+}
+
+func (w *recordingStatementWriter) Begin(statement entities.Statement) error {
+	w.begin = statement
+	return nil
+}
+
+func (w *recordingStatementWriter) Line(line entities.StatementLine) error {
+	w.lines = append(w.lines, line)
+	return nil
+}
+
+func (w *recordingStatementWriter) End(statement entities.Statement) error {
+	w.end = statement
+	return nil
+}
+
+func TestApplication_WriteStatement(t *testing.T) {
+	repo := newMockWalletRepo()
+	app, ledger := newTestApplication(repo)
+	journal := app.JournalRepo.(*mockJournalRepo)
+	ctx := context.Background()
+
+	wallet := entities.NewWallet()
+	wallet.Currency = "USD"
+	repo.wallets[wallet.ID] = wallet
+
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	record := func(operationType string, amount float64, createdAt time.Time) {
+		change := decimal.NewFromFloat(amount)
+		if !walletCredits(operationType) {
+			change = change.Neg()
+		}
+		journal.entries = append(journal.entries, walletEntry(wallet.ID, change.InexactFloat64(), createdAt))
+		ledger.transactions = append(ledger.transactions, entities.Transaction{
+			ID:            createdAt.String(),
+			WalletId:      wallet.ID,
+			OperationType: operationType,
+			Currency:      "USD",
+			Amount:        decimal.NewFromFloat(amount),
+			CreatedAt:     createdAt,
+		})
+	}
+	record("DEPOSIT", 100, start.Add(time.Hour))
+	record("WITHDRAW", 30, start.Add(26*time.Hour))
+	record("DEPOSIT", 50, start.Add(30*time.Hour))
+	record("TRANSFER_IN", 20, start.Add(40*time.Hour))
+	record("DEPOSIT", 1000, start.Add(72*time.Hour))
+
+	w := &recordingStatementWriter{}
+	err := app.WriteStatement(ctx, wallet.ID, start.Add(24*time.Hour), start.Add(48*time.Hour), w)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !w.begin.OpeningBalance.Equal(decimal.NewFromFloat(100.0)) || w.begin.Currency != "USD" {
+		t.Errorf("expected opening balance 100.0 USD, got %s %s", w.begin.OpeningBalance.String(), w.begin.Currency)
+	}
+
+	expected := []float64{70, 120, 140}
+	if len(w.lines) != len(expected) {
+		t.Fatalf("expected %d lines, got %d", len(expected), len(w.lines))
+	}
+	for i, balance := range expected {
+		if !w.lines[i].RunningBalance.Equal(decimal.NewFromFloat(balance)) {
+			t.Errorf("line %d: expected running balance %v, got %s", i, balance, w.lines[i].RunningBalance.String())
+		}
+	}
+	if !w.lines[0].Change.Equal(decimal.NewFromFloat(-30.0)) {
+		t.Errorf("expected the withdrawal to change the balance by -30, got %s", w.lines[0].Change.String())
+	}
+
+	if !w.end.ClosingBalance.Equal(decimal.NewFromFloat(140.0)) {
+		t.Errorf("expected closing balance 140.0, got %s", w.end.ClosingBalance.String())
+	}
+	deposits := w.end.Totals["DEPOSIT"]
+	if deposits.Count != 1 || !deposits.Amount.Equal(decimal.NewFromFloat(50.0)) {
+		t.Errorf("unexpected DEPOSIT totals %+v", deposits)
+	}
+	if len(w.end.Totals) != 3 {
+		t.Errorf("expected totals for 3 operation types, got %d", len(w.end.Totals))
+	}
+}
+
+func TestApplication_WriteStatement_InvalidPeriod(t *testing.T) {
+	app, _ := newTestApplication(newMockWalletRepo())
+
+	now := time.Now()
+	err := app.WriteStatement(context.Background(), "wallet-id", now, now.Add(-time.Hour), &recordingStatementWriter{})
+	if !errors.Is(err, entities.ErrInvalidStatementPeriod) {
+		t.Errorf("expected ErrInvalidStatementPeriod, got %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package application
+
+import (
+	"TestProject/source/internal/entities"
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	streamBatchSize = 100
+	// streamHeartbeatInterval keeps idle streams open through proxies and
+	// detects clients that went away.
+	streamHeartbeatInterval = 15 * time.Second
+)
+
+// WalletStreamWriter receives a wallet stream. Event IDs are the outbox
+// sequences, so the last one received resumes the stream.
+type WalletStreamWriter interface {
+	// Snapshot gets the wallet as of the event with the given sequence, 0
+	// when the wallet has no events yet.
+	Snapshot(wallet entities.Wallet, sequence int64) error
+	Event(event entities.WalletEvent) error
+	Heartbeat() error
+}
+
+// StreamWallet writes the wallet's balance changes as they are committed
+// until ctx is done. With lastEventID it first replays the events after
+// it; without one, or when that event was already purged from the outbox,
+// it starts with a snapshot of the wallet. ErrStreamInterrupted means
+// events may have been missed and the client should resume.
+func (a *Application) StreamWallet(ctx context.Context, walletID string, lastEventID *int64, w WalletStreamWriter) error {
+	// Subscribing before reading the outbox makes sure no event committed
+	// in between is lost; events seen twice are skipped by sequence.
+	events, unsubscribe := a.Subscriber.Subscribe(walletID)
+	defer unsubscribe()
+
+	after, err := a.startWalletStream(ctx, walletID, lastEventID, w)
+	if err != nil {
+		return err
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-events:
+			if !ok {
+				return entities.ErrStreamInterrupted
+			}
+			if event.Sequence <= after {
+				continue
+			}
+			err = w.Event(event)
+			if err != nil {
+				return err
+			}
+			after = event.Sequence
+		case <-heartbeat.C:
+			err = w.Heartbeat()
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// startWalletStream writes the replay or the snapshot and returns the
+// sequence the live events continue after.
+func (a *Application) startWalletStream(ctx context.Context, walletID string, lastEventID *int64, w WalletStreamWriter) (int64, error) {
+	if lastEventID != nil {
+		// The replay reads only the outbox, which has no events for an
+		// unknown wallet either.
+		_, err := a.WalletRepo.GetByID(ctx, walletID)
+		if err != nil {
+			return 0, fmt.Errorf("error getting wallet: %w", err)
+		}
+
+		retained := *lastEventID == 0
+		if !retained {
+			retained, err = a.OutboxRepo.HasEvent(ctx, walletID, *lastEventID)
+			if err != nil {
+				return 0, fmt.Errorf("error checking last event: %w", err)
+			}
+		}
+		if retained {
+			return a.replayWalletEvents(ctx, walletID, *lastEventID, w)
+		}
+	}
+
+	var wallet entities.Wallet
+	var sequence int64
+
+	// The shared lock holds back writers of this wallet until its latest
+	// event is read, so the balance and the sequence match, without
+	// serializing the streams that connect at the same time.
+	err := a.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		wallet, err = a.WalletRepo.GetByIDForShare(ctx, walletID)
+		if err != nil {
+			return err
+		}
+		sequence, err = a.OutboxRepo.LastSequence(ctx, walletID)
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error getting wallet snapshot: %w", err)
+	}
+
+	err = w.Snapshot(wallet, sequence)
+	if err != nil {
+		return 0, err
+	}
+	return sequence, nil
+}
+
+func (a *Application) replayWalletEvents(ctx context.Context, walletID string, after int64, w WalletStreamWriter) (int64, error) {
+	for {
+		events, err := a.OutboxRepo.ListByWallet(ctx, walletID, after, streamBatchSize)
+		if err != nil {
+			return 0, fmt.Errorf("error replaying wallet events: %w", err)
+		}
+		for _, event := range events {
+			err = w.Event(event)
+			if err != nil {
+				return 0, err
+			}
+			after = event.Sequence
+		}
+		if len(events) < streamBatchSize {
+			return after, nil
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package application
+
+import (
+	"TestProject/source/internal/entities"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// mockSubscriber hands out one subscription, fed by the test.
+type mockSubscriber struct {
+	events     chan entities.WalletEvent
+	subscribed chan struct{}
+}
+
+func newMockSubscriber() *mockSubscriber {
+	return &mockSubscriber{
+		events:     make(chan entities.WalletEvent, 16),
+		subscribed: make(chan struct{}),
+	}
+}
+
+func (m *mockSubscriber) Subscribe(walletID string) (<-chan entities.WalletEvent, func()) {
+	close(m.subscribed)
+	return m.events, func() {}
+}
+
+func (m *mockSubscriber) publish(event entities.WalletEvent) {
+	m.events <- event
+}
+
+type recordingStreamWriter struct {
+	messages chan string
+}
+
+func (w *recordingStreamWriter) Snapshot(wallet entities.Wallet, sequence int64) error {
+	w.messages <- fmt.Sprintf("snapshot %d %s", sequence, wallet.Balance)
+	return nil
+}
+
+func (w *recordingStreamWriter) Event(event entities.WalletEvent) error {
+	w.messages <- fmt.Sprintf("event %d %s", event.Sequence, event.Balance)
+	return nil
+}
+
+func (w *recordingStreamWriter) Heartbeat() error {
+	return nil
+}
+
+// startStream runs StreamWallet until the returned cancel is called; its
+// result is sent to done.
+func startStream(app *Application, walletID string, lastEventID *int64) (*recordingStreamWriter, context.CancelFunc, chan error) {
+	writer := &recordingStreamWriter{messages: make(chan string, 16)}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- app.StreamWallet(ctx, walletID, lastEventID, writer)
+	}()
+	return writer, cancel, done
+}
+
+func expectMessages(t *testing.T, writer *recordingStreamWriter, want ...string) {
+	t.Helper()
+
+	for _, message := range want {
+		select {
+		case got := <-writer.messages:
+			if got != message {
+				t.Fatalf("expected %q, got %q", message, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %q", message)
+		}
+	}
+}
+
+func TestApplication_StreamWallet_SnapshotAndLiveEvents(t *testing.T) {
+	repo := newMockWalletRepo()
+	app, _ := newTestApplication(repo)
+	outbox := app.OutboxRepo.(*mockOutboxRepo)
+	subscriber := newMockSubscriber()
+	app.Subscriber = subscriber
+	ctx := context.Background()
+
+	wallet := entities.NewWallet()
+	repo.wallets[wallet.ID] = wallet
+
+	_, err := app.ProcessTransaction(ctx, entities.Transaction{WalletId: wallet.ID, OperationType: deposit, Amount: dec("100")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	writer, cancel, done := startStream(app, wallet.ID, nil)
+	defer cancel()
+	expectMessages(t, writer, "snapshot 1 100")
+
+	_, err = app.ProcessTransaction(ctx, entities.Transaction{WalletId: wallet.ID, OperationType: deposit, Amount: dec("50")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The first event is already in the snapshot and must be skipped.
+	subscriber.publish(outbox.events[0])
+	subscriber.publish(outbox.events[1])
+	expectMessages(t, writer, "event 2 150")
+
+	close(subscriber.events)
+	select {
+	case err = <-done:
+		if !errors.Is(err, entities.ErrStreamInterrupted) {
+			t.Fatalf("expected ErrStreamInterrupted, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the stream to end when the subscription is closed")
+	}
+}
+
+func TestApplication_StreamWallet_Resume(t *testing.T) {
+	repo := newMockWalletRepo()
+	app, _ := newTestApplication(repo)
+	ctx := context.Background()
+
+	wallet := entities.NewWallet()
+	other := entities.NewWallet()
+	repo.wallets[wallet.ID] = wallet
+	repo.wallets[other.ID] = other
+
+	for _, walletID := range []string{wallet.ID, other.ID, wallet.ID, wallet.ID} {
+		_, err := app.ProcessTransaction(ctx, entities.Transaction{WalletId: walletID, OperationType: deposit, Amount: dec("10")})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	t.Run("replays the events after the last one received", func(t *testing.T) {
+		app.Subscriber = newMockSubscriber()
+		lastEventID := int64(1)
+
+		writer, cancel, _ := startStream(app, wallet.ID, &lastEventID)
+		defer cancel()
+		expectMessages(t, writer, "event 3 20", "event 4 30")
+	})
+
+	t.Run("starts over with a snapshot when the event is gone", func(t *testing.T) {
+		app.Subscriber = newMockSubscriber()
+		// Sequence 2 belongs to another wallet, as if it was purged.
+		lastEventID := int64(2)
+
+		writer, cancel, _ := startStream(app, wallet.ID, &lastEventID)
+		defer cancel()
+		expectMessages(t, writer, "snapshot 4 30")
+	})
+}
+
+func TestApplication_StreamWallet_UnknownWallet(t *testing.T) {
+	app, _ := newTestApplication(newMockWalletRepo())
+
+	for _, lastEventID := range []*int64{nil, new(int64)} {
+		app.Subscriber = newMockSubscriber()
+
+		_, cancel, done := startStream(app, "missing-wallet", lastEventID)
+		select {
+		case err := <-done:
+			if !errors.Is(err, entities.ErrWalletNotFound) {
+				t.Errorf("expected ErrWalletNotFound with last event id %v, got %v", lastEventID, err)
+			}
+		case <-time.After(time.Second):
+			t.Errorf("expected the stream to fail with last event id %v", lastEventID)
+		}
+		cancel()
+	}
+}
@@ -0,0 +1,121 @@
+package application
+
+import (
+	"TestProject/source/internal/entities"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Transfer debits one wallet and credits another in a single database
+// transaction and writes a ledger entry for each side.
+func (a *Application) Transfer(ctx context.Context, transfer entities.Transfer) (entities.Transfer, error) {
+	if transfer.FromWalletId == transfer.ToWalletId {
+		return entities.Transfer{}, entities.ErrSameWalletTransfer
+	}
+	if transfer.ID == "" {
+		transfer.ID = uuid.NewString()
+	}
+	transfer.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+
+	out := entities.Transaction{
+		ID:            uuid.NewString(),
+		WalletId:      transfer.FromWalletId,
+		OperationType: transferOut,
+		Amount:        transfer.Amount,
+		ReferenceId:   transfer.ID,
+		CreatedAt:     transfer.CreatedAt,
+	}
+	in := entities.Transaction{
+		ID:            uuid.NewString(),
+		WalletId:      transfer.ToWalletId,
+		OperationType: transferIn,
+		Amount:        transfer.Amount,
+		ReferenceId:   transfer.ID,
+		CreatedAt:     transfer.CreatedAt,
+	}
+
+	var entry entities.JournalEntry
+
+	err := a.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		walletIDs := []string{transfer.FromWalletId, transfer.ToWalletId}
+		wallets, err := a.WalletRepo.UpdateManyWithLock(ctx, walletIDs, func(w map[string]*entities.Wallet) error {
+			from, to := w[transfer.FromWalletId], w[transfer.ToWalletId]
+
+			if from.Currency != to.Currency {
+				return fmt.Errorf("%w: cannot transfer %s to %s", entities.ErrCurrencyMismatch, from.Currency, to.Currency)
+			}
+			if transfer.Currency != "" && transfer.Currency != from.Currency {
+				return fmt.Errorf("%w: wallets are %s, got %s", entities.ErrCurrencyMismatch, from.Currency, transfer.Currency)
+			}
+			err := from.CheckDebit()
+			if err != nil {
+				return err
+			}
+			err = to.CheckCredit()
+			if err != nil {
+				return err
+			}
+			err = entities.ValidateAmount(transfer.Amount, from.Currency)
+			if err != nil {
+				return err
+			}
+
+			transfer.Currency = from.Currency
+			out.Currency = from.Currency
+			in.Currency = to.Currency
+			if from.Spendable().LessThan(transfer.Amount) {
+				return entities.ErrInsufficientFunds
+			}
+			err = a.checkVelocity(ctx, from.ID, transfer.Amount, transfer.CreatedAt, "")
+			if err != nil {
+				return err
+			}
+
+			out.BalanceBefore = from.Balance
+			in.BalanceBefore = to.Balance
+
+			entry = entities.JournalEntry{
+				ID:            transfer.ID,
+				OperationType: transferEntry,
+				Postings: []entities.Posting{
+					entities.WalletPosting(from.ID, from.Currency, transfer.Amount.Neg()),
+					entities.WalletPosting(to.ID, to.Currency, transfer.Amount),
+				},
+				CreatedAt: transfer.CreatedAt,
+			}
+			err = applyJournal(entry, w)
+			if err != nil {
+				return err
+			}
+			return entities.CheckAmountRange(to.Balance)
+		})
+		if err != nil {
+			return err
+		}
+
+		transfer.FromBalance = wallets[transfer.FromWalletId].Balance
+		transfer.ToBalance = wallets[transfer.ToWalletId].Balance
+		out.Balance = transfer.FromBalance
+		in.Balance = transfer.ToBalance
+
+		for _, transaction := range []entities.Transaction{out, in} {
+			_, err = a.recordTransaction(ctx, transaction)
+			if err != nil {
+				return fmt.Errorf("error recording transaction: %w", err)
+			}
+		}
+		err = a.JournalRepo.Create(ctx, entry)
+		if err != nil {
+			return fmt.Errorf("error recording journal entry: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return entities.Transfer{}, fmt.Errorf("error processing transfer: %w", err)
+	}
+
+	return transfer, nil
+}
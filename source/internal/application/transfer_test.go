@@ -0,0 +1,170 @@
+package application
+
+import (
+	"TestProject/source/internal/entities"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestApplication_Transfer(t *testing.T) {
+	repo := newMockWalletRepo()
+	app, ledger := newTestApplication(repo)
+	ctx := context.Background()
+
+	from := entities.NewWallet()
+	from.Balance = decimal.NewFromFloat(1000.0)
+	to := entities.NewWallet()
+	to.Balance = decimal.NewFromFloat(200.0)
+	repo.wallets[from.ID] = from
+	repo.wallets[to.ID] = to
+
+	transfer := entities.NewTransfer()
+	transfer.FromWalletId = from.ID
+	transfer.ToWalletId = to.ID
+	transfer.Amount = decimal.NewFromFloat(300.0)
+
+	result, err := app.Transfer(ctx, transfer)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !result.FromBalance.Equal(decimal.NewFromFloat(700.0)) {
+		t.Errorf("expected from balance 700.0, got %s", result.FromBalance.String())
+	}
+	if !result.ToBalance.Equal(decimal.NewFromFloat(500.0)) {
+		t.Errorf("expected to balance 500.0, got %s", result.ToBalance.String())
+	}
+
+	if len(ledger.transactions) != 2 {
+		t.Fatalf("expected 2 ledger entries, got %d", len(ledger.transactions))
+	}
+	for _, entry := range ledger.transactions {
+		if entry.ReferenceId != result.ID {
+			t.Errorf("expected ledger entry to reference transfer %s, got %s", result.ID, entry.ReferenceId)
+		}
+	}
+	if ledger.transactions[0].OperationType != "TRANSFER_OUT" || ledger.transactions[1].OperationType != "TRANSFER_IN" {
+		t.Errorf("unexpected ledger operation types %s, %s",
+			ledger.transactions[0].OperationType, ledger.transactions[1].OperationType)
+	}
+}
+
+func TestApplication_Transfer_InsufficientFunds(t *testing.T) {
+	repo := newMockWalletRepo()
+	app, ledger := newTestApplication(repo)
+	ctx := context.Background()
+
+	from := entities.NewWallet()
+	from.Balance = decimal.NewFromFloat(100.0)
+	to := entities.NewWallet()
+	repo.wallets[from.ID] = from
+	repo.wallets[to.ID] = to
+
+	_, err := app.Transfer(ctx, entities.Transfer{
+		FromWalletId: from.ID,
+		ToWalletId:   to.ID,
+		Amount:       decimal.NewFromFloat(500.0),
+	})
+	if !errors.Is(err, entities.ErrInsufficientFunds) {
+		t.Fatalf("expected ErrInsufficientFunds, got %v", err)
+	}
+
+	if !repo.wallets[from.ID].Balance.Equal(decimal.NewFromFloat(100.0)) || !repo.wallets[to.ID].Balance.IsZero() {
+		t.Error("expected balances to stay unchanged")
+	}
+	if len(ledger.transactions) != 0 {
+		t.Errorf("expected no ledger entries, got %d", len(ledger.transactions))
+	}
+}
+
+func TestApplication_Transfer_SameWallet(t *testing.T) {
+	repo := newMockWalletRepo()
+	app, _ := newTestApplication(repo)
+
+	wallet := entities.NewWallet()
+	repo.wallets[wallet.ID] = wallet
+
+	_, err := app.Transfer(context.Background(), entities.Transfer{
+		FromWalletId: wallet.ID,
+		ToWalletId:   wallet.ID,
+		Amount:       decimal.NewFromFloat(1.0),
+	})
+	if !errors.Is(err, entities.ErrSameWalletTransfer) {
+		t.Fatalf("expected ErrSameWalletTransfer, got %v", err)
+	}
+}
+
+func TestApplication_Transfer_ConcurrentOpposite(t *testing.T) {
+	repo := newMockWalletRepo()
+	app, _ := newTestApplication(repo)
+	ctx := context.Background()
+
+	a := entities.NewWallet()
+	a.Balance = decimal.NewFromFloat(1000.0)
+	b := entities.NewWallet()
+	b.Balance = decimal.NewFromFloat(1000.0)
+	repo.wallets[a.ID] = a
+	repo.wallets[b.ID] = b
+
+	const numTransfers = 50
+	amount := decimal.NewFromFloat(10.0)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, numTransfers*2)
+
+	for i := 0; i < numTransfers; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, err := app.Transfer(ctx, entities.Transfer{FromWalletId: a.ID, ToWalletId: b.ID, Amount: amount})
+			if err != nil {
+				errs <- err
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := app.Transfer(ctx, entities.Transfer{FromWalletId: b.ID, ToWalletId: a.ID, Amount: amount})
+			if err != nil {
+				errs <- err
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("unexpected error in concurrent transfer: %v", err)
+	}
+
+	total := repo.wallets[a.ID].Balance.Add(repo.wallets[b.ID].Balance)
+	if !total.Equal(decimal.NewFromFloat(2000.0)) {
+		t.Errorf("expected total balance 2000.0, got %s", total.String())
+	}
+}
+
+func TestApplication_Transfer_CurrencyMismatch(t *testing.T) {
+	repo := newMockWalletRepo()
+	app, _ := newTestApplication(repo)
+
+	from := entities.NewWallet()
+	from.Currency = "USD"
+	from.Balance = decimal.NewFromFloat(100.0)
+	to := entities.NewWallet()
+	to.Currency = "EUR"
+	repo.wallets[from.ID] = from
+	repo.wallets[to.ID] = to
+
+	_, err := app.Transfer(context.Background(), entities.Transfer{
+		FromWalletId: from.ID,
+		ToWalletId:   to.ID,
+		Amount:       decimal.NewFromFloat(10.0),
+	})
+	if !errors.Is(err, entities.ErrCurrencyMismatch) {
+		t.Fatalf("expected ErrCurrencyMismatch, got %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package application
+
+import (
+	"TestProject/source/internal/entities"
+	"context"
+	"fmt"
+	"time"
+)
+
+// checkWalletStatus reports whether a frozen or closed wallet blocks the
+// operation.
+func checkWalletStatus(w entities.Wallet, operationType string) error {
+	if walletCredits(operationType) {
+		return w.CheckCredit()
+	}
+	return w.CheckDebit()
+}
+
+// FreezeWallet blocks outgoing money (FreezeDebit) or every balance change
+// (FreezeAll). Freezing a frozen wallet changes its mode.
+func (a *Application) FreezeWallet(ctx context.Context, walletID, mode, reason string) (entities.Wallet, error) {
+	if mode != entities.FreezeDebit && mode != entities.FreezeAll {
+		return entities.Wallet{}, fmt.Errorf("%w: freeze mode %s", entities.ErrInvalidStatusTransition, mode)
+	}
+
+	return a.changeWalletStatus(ctx, walletID, reason, func(w *entities.Wallet) error {
+		w.Status = entities.WalletFrozen
+		w.FreezeMode = mode
+		return nil
+	})
+}
+
+// UnfreezeWallet makes a frozen wallet active again.
+func (a *Application) UnfreezeWallet(ctx context.Context, walletID, reason string) (entities.Wallet, error) {
+	return a.changeWalletStatus(ctx, walletID, reason, func(w *entities.Wallet) error {
+		if w.Status != entities.WalletFrozen {
+			return fmt.Errorf("%w: wallet is %s", entities.ErrInvalidStatusTransition, w.Status)
+		}
+		w.Status = entities.WalletActive
+		w.FreezeMode = ""
+		return nil
+	})
+}
+
+// CloseWallet permanently closes a wallet with zero balance and no active
+// holds.
+func (a *Application) CloseWallet(ctx context.Context, walletID, reason string) (entities.Wallet, error) {
+	return a.changeWalletStatus(ctx, walletID, reason, func(w *entities.Wallet) error {
+		if !w.Balance.IsZero() || !w.Reserved.IsZero() {
+			return fmt.Errorf("%w: balance %s, reserved %s", entities.ErrWalletNotEmpty, w.Balance.String(), w.Reserved.String())
+		}
+		w.Status = entities.WalletClosed
+		w.FreezeMode = ""
+		return nil
+	})
+}
+
+// changeWalletStatus applies fn to the locked wallet and records the reason.
+// A closed wallet never changes status again.
+func (a *Application) changeWalletStatus(ctx context.Context, walletID, reason string, fn func(*entities.Wallet) error) (entities.Wallet, error) {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	wallet, err := a.WalletRepo.UpdateWithLock(ctx, walletID, func(w *entities.Wallet) error {
+		if w.Status == entities.WalletClosed {
+			return entities.ErrWalletClosed
+		}
+		err := fn(w)
+		if err != nil {
+			return err
+		}
+		w.StatusReason = reason
+		w.StatusChangedAt = &now
+		return nil
+	})
+	if err != nil {
+		return entities.Wallet{}, fmt.Errorf("error changing wallet status: %w", err)
+	}
+
+	return wallet, nil
+}
@@ -0,0 +1,134 @@
+package application
+
+import (
+	"TestProject/source/internal/entities"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestApplication_FreezeWallet_DebitMode(t *testing.T) {
+	repo := newMockWalletRepo()
+	app, _ := newTestApplication(repo)
+	ctx := context.Background()
+
+	wallet := entities.NewWallet()
+	wallet.Balance = decimal.NewFromInt(100)
+	repo.wallets[wallet.ID] = wallet
+
+	frozen, err := app.FreezeWallet(ctx, wallet.ID, entities.FreezeDebit, "KYC_REVIEW")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if frozen.Status != entities.WalletFrozen || frozen.StatusReason != "KYC_REVIEW" || frozen.StatusChangedAt == nil {
+		t.Errorf("unexpected wallet status %+v", frozen)
+	}
+
+	_, err = app.ProcessTransaction(ctx, entities.Transaction{
+		WalletId: wallet.ID, OperationType: "DEPOSIT", Amount: decimal.NewFromInt(10),
+	})
+	if err != nil {
+		t.Errorf("expected deposit to a debit-frozen wallet to succeed, got %v", err)
+	}
+
+	_, err = app.ProcessTransaction(ctx, entities.Transaction{
+		WalletId: wallet.ID, OperationType: "WITHDRAW", Amount: decimal.NewFromInt(10),
+	})
+	if !errors.Is(err, entities.ErrWalletFrozen) {
+		t.Errorf("expected ErrWalletFrozen for withdraw, got %v", err)
+	}
+
+	_, err = app.CreateHold(ctx, entities.Hold{WalletId: wallet.ID, Amount: decimal.NewFromInt(10)})
+	if !errors.Is(err, entities.ErrWalletFrozen) {
+		t.Errorf("expected ErrWalletFrozen for hold, got %v", err)
+	}
+}
+
+func TestApplication_FreezeWallet_AllMode(t *testing.T) {
+	repo := newMockWalletRepo()
+	app, _ := newTestApplication(repo)
+	ctx := context.Background()
+
+	from := entities.NewWallet()
+	from.Balance = decimal.NewFromInt(100)
+	to := entities.NewWallet()
+	repo.wallets[from.ID] = from
+	repo.wallets[to.ID] = to
+
+	_, err := app.FreezeWallet(ctx, to.ID, entities.FreezeAll, "COURT_ORDER")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = app.ProcessTransaction(ctx, entities.Transaction{
+		WalletId: to.ID, OperationType: "DEPOSIT", Amount: decimal.NewFromInt(10),
+	})
+	if !errors.Is(err, entities.ErrWalletFrozen) {
+		t.Errorf("expected ErrWalletFrozen for deposit, got %v", err)
+	}
+
+	transfer := entities.NewTransfer()
+	transfer.FromWalletId = from.ID
+	transfer.ToWalletId = to.ID
+	transfer.Amount = decimal.NewFromInt(10)
+	_, err = app.Transfer(ctx, transfer)
+	if !errors.Is(err, entities.ErrWalletFrozen) {
+		t.Errorf("expected ErrWalletFrozen for transfer, got %v", err)
+	}
+
+	active, err := app.UnfreezeWallet(ctx, to.ID, "COURT_ORDER_LIFTED")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if active.Status != entities.WalletActive || active.FreezeMode != "" {
+		t.Errorf("expected active wallet, got %s/%s", active.Status, active.FreezeMode)
+	}
+	_, err = app.Transfer(ctx, transfer)
+	if err != nil {
+		t.Errorf("expected transfer after unfreeze to succeed, got %v", err)
+	}
+}
+
+func TestApplication_CloseWallet(t *testing.T) {
+	repo := newMockWalletRepo()
+	app, _ := newTestApplication(repo)
+	ctx := context.Background()
+
+	wallet := entities.NewWallet()
+	wallet.Balance = decimal.NewFromInt(10)
+	repo.wallets[wallet.ID] = wallet
+
+	_, err := app.CloseWallet(ctx, wallet.ID, "CUSTOMER_REQUEST")
+	if !errors.Is(err, entities.ErrWalletNotEmpty) {
+		t.Fatalf("expected ErrWalletNotEmpty, got %v", err)
+	}
+
+	_, err = app.ProcessTransaction(ctx, entities.Transaction{
+		WalletId: wallet.ID, OperationType: "WITHDRAW", Amount: decimal.NewFromInt(10),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	closed, err := app.CloseWallet(ctx, wallet.ID, "CUSTOMER_REQUEST")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if closed.Status != entities.WalletClosed {
+		t.Errorf("expected CLOSED, got %s", closed.Status)
+	}
+
+	_, err = app.ProcessTransaction(ctx, entities.Transaction{
+		WalletId: wallet.ID, OperationType: "DEPOSIT", Amount: decimal.NewFromInt(10),
+	})
+	if !errors.Is(err, entities.ErrWalletClosed) {
+		t.Errorf("expected ErrWalletClosed for deposit, got %v", err)
+	}
+
+	_, err = app.FreezeWallet(ctx, wallet.ID, entities.FreezeAll, "FRAUD")
+	if !errors.Is(err, entities.ErrWalletClosed) {
+		t.Errorf("expected ErrWalletClosed when changing status, got %v", err)
+	}
+}
@@ -0,0 +1,385 @@
+package application
+
+import (
+	"TestProject/source/internal/entities"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/fx"
+)
+
+const (
+	webhookBatchSize = 100
+	// webhookWorkers is how many deliveries are sent at the same time.
+	webhookWorkers = 10
+	// maxWebhookBackoff caps the wait between two attempts of a delivery.
+	maxWebhookBackoff = 6 * time.Hour
+	// minDeliveryLease is how long a claimed delivery is hidden from other
+	// workers at least, in case WEBHOOK_TIMEOUT is zero.
+	minDeliveryLease = time.Minute
+)
+
+// CreateWebhook registers a webhook for the events of webhook.WalletId, or
+// of every wallet when it is empty. URLs that resolve to loopback or private
+// addresses are rejected with ErrWebhookURLNotAllowed.
+func (a *Application) CreateWebhook(ctx context.Context, webhook entities.Webhook) (entities.Webhook, error) {
+	err := a.WebhookClient.CheckURL(ctx, webhook.URL)
+	if err != nil {
+		return entities.Webhook{}, fmt.Errorf("error checking webhook url: %w", err)
+	}
+
+	if webhook.WalletId != "" {
+		_, err := a.WalletRepo.GetByID(ctx, webhook.WalletId)
+		if err != nil {
+			return entities.Webhook{}, fmt.Errorf("error getting wallet: %w", err)
+		}
+	}
+	webhook.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+
+	webhook, err = a.WebhookRepo.Create(ctx, webhook)
+	if err != nil {
+		return entities.Webhook{}, fmt.Errorf("error creating webhook: %w", err)
+	}
+	return webhook, nil
+}
+
+func (a *Application) GetWebhook(ctx context.Context, webhookID string) (entities.Webhook, error) {
+	webhook, err := a.WebhookRepo.GetByID(ctx, webhookID)
+	if err != nil {
+		return entities.Webhook{}, fmt.Errorf("error getting webhook: %w", err)
+	}
+	return webhook, nil
+}
+
+// ListWebhooks returns the webhooks of a wallet, or all webhooks when
+// walletID is empty.
+func (a *Application) ListWebhooks(ctx context.Context, walletID string) ([]entities.Webhook, error) {
+	webhooks, err := a.WebhookRepo.List(ctx, walletID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing webhooks: %w", err)
+	}
+	return webhooks, nil
+}
+
+// DeleteWebhook deletes the webhook and its deliveries, including those
+// not sent yet.
+func (a *Application) DeleteWebhook(ctx context.Context, webhookID string) error {
+	err := a.WebhookRepo.Delete(ctx, webhookID)
+	if err != nil {
+		return fmt.Errorf("error deleting webhook: %w", err)
+	}
+	return nil
+}
+
+// enqueueWebhooks queues a delivery of the event to every webhook of its
+// wallet. The outbox relay calls it for each published event, so an event
+// is queued once even if it is published again.
+func (a *Application) enqueueWebhooks(ctx context.Context, event entities.WalletEvent) error {
+	webhooks, err := a.WebhookRepo.ListForWallet(ctx, event.WalletId)
+	if err != nil {
+		return fmt.Errorf("error listing webhooks: %w", err)
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error encoding wallet event: %w", err)
+	}
+
+	now := a.now()
+	deliveries := make([]entities.WebhookDelivery, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		deliveries = append(deliveries, entities.WebhookDelivery{
+			ID:            uuid.NewString(),
+			WebhookId:     webhook.ID,
+			EventId:       event.ID,
+			EventType:     event.Type,
+			WalletId:      event.WalletId,
+			Payload:       payload,
+			Status:        entities.DeliveryPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		})
+	}
+
+	err = a.WebhookRepo.CreateDeliveries(ctx, deliveries)
+	if err != nil {
+		return fmt.Errorf("error queueing webhook deliveries: %w", err)
+	}
+	return nil
+}
+
+// webhookRun is the state shared by the workers of one DeliverWebhooks run.
+type webhookRun struct {
+	mu        sync.Mutex
+	busy      map[string]bool
+	claimed   int
+	attempted int
+	err       error
+}
+
+// DeliverWebhooks sends up to webhookBatchSize due deliveries with
+// webhookWorkers requests at a time and returns how many it attempted. Each
+// webhook has at most one request in flight, so a slow receiver holds up one
+// worker and its own deliveries only. A claimed delivery is leased for twice
+// the request timeout, so a worker that dies while sending leaves it to be
+// retried; the request itself is sent outside of any database transaction.
+// Sending takes up to the timeout, so every step reads the clock again.
+func (a *Application) DeliverWebhooks(ctx context.Context) (int, error) {
+	run := &webhookRun{busy: make(map[string]bool)}
+
+	var wg sync.WaitGroup
+	for range webhookWorkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a.deliverWebhooks(ctx, run)
+		}()
+	}
+	wg.Wait()
+
+	return run.attempted, run.err
+}
+
+// deliverWebhooks is one worker of the run: it sends deliveries until none
+// is left for it or the run fails.
+func (a *Application) deliverWebhooks(ctx context.Context, run *webhookRun) {
+	for {
+		delivery, webhook, ok := a.nextDelivery(ctx, run)
+		if !ok {
+			return
+		}
+
+		statusCode, sendErr := a.sendDelivery(ctx, webhook, delivery)
+		if ctx.Err() != nil {
+			// Shutting down: the lease runs out and the attempt is repeated.
+			run.mu.Lock()
+			run.err = ctx.Err()
+			run.mu.Unlock()
+			return
+		}
+
+		err := a.recordDeliveryAttempt(ctx, delivery.ID, statusCode, sendErr)
+
+		run.mu.Lock()
+		run.attempted++
+		delete(run.busy, webhook.ID)
+		if err != nil && run.err == nil {
+			run.err = err
+		}
+		run.mu.Unlock()
+		if err != nil {
+			return
+		}
+	}
+}
+
+// nextDelivery claims a due delivery of a webhook that has no request in
+// flight. It reports false when the run is over for the worker.
+func (a *Application) nextDelivery(ctx context.Context, run *webhookRun) (entities.WebhookDelivery, entities.Webhook, bool) {
+	run.mu.Lock()
+	defer run.mu.Unlock()
+
+	if run.err != nil || run.claimed >= webhookBatchSize {
+		return entities.WebhookDelivery{}, entities.Webhook{}, false
+	}
+
+	busy := make([]string, 0, len(run.busy))
+	for webhookID := range run.busy {
+		busy = append(busy, webhookID)
+	}
+	delivery, webhook, err := a.claimDelivery(ctx, busy)
+	if errors.Is(err, entities.ErrDeliveryNotFound) {
+		return entities.WebhookDelivery{}, entities.Webhook{}, false
+	}
+	if err != nil {
+		run.err = err
+		return entities.WebhookDelivery{}, entities.Webhook{}, false
+	}
+
+	run.claimed++
+	run.busy[webhook.ID] = true
+	return delivery, webhook, true
+}
+
+func (a *Application) claimDelivery(ctx context.Context, skipWebhooks []string) (entities.WebhookDelivery, entities.Webhook, error) {
+	var delivery entities.WebhookDelivery
+	var webhook entities.Webhook
+
+	err := a.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		now := a.now()
+
+		var err error
+		delivery, err = a.WebhookRepo.ClaimDueDelivery(ctx, now, skipWebhooks)
+		if err != nil {
+			return err
+		}
+		webhook, err = a.WebhookRepo.GetByID(ctx, delivery.WebhookId)
+		if err != nil {
+			return fmt.Errorf("error getting webhook: %w", err)
+		}
+
+		delivery.NextAttemptAt = now.Add(max(2*a.webhookConf.Timeout, minDeliveryLease))
+		delivery.UpdatedAt = now
+		delivery, err = a.WebhookRepo.UpdateDelivery(ctx, delivery)
+		if err != nil {
+			return fmt.Errorf("error leasing webhook delivery: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return entities.WebhookDelivery{}, entities.Webhook{}, fmt.Errorf("error claiming webhook delivery: %w", err)
+	}
+
+	return delivery, webhook, nil
+}
+
+// sendDelivery posts the payload signed with the webhook secret. Receivers
+// check X-Webhook-Signature against SignWebhook of X-Webhook-Timestamp and
+// the raw body, and use X-Webhook-Id to drop duplicates.
+func (a *Application) sendDelivery(ctx context.Context, webhook entities.Webhook, delivery entities.WebhookDelivery) (int, error) {
+	timestamp := a.now().Unix()
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set("X-Webhook-Id", delivery.ID)
+	header.Set("X-Webhook-Event", delivery.EventType)
+	header.Set("X-Webhook-Timestamp", strconv.FormatInt(timestamp, 10))
+	header.Set("X-Webhook-Signature", "sha256="+entities.SignWebhook(webhook.Secret, timestamp, delivery.Payload))
+
+	return a.WebhookClient.Post(ctx, webhook.URL, header, delivery.Payload)
+}
+
+// recordDeliveryAttempt marks the delivery succeeded on a 2xx response.
+// Otherwise it schedules the next attempt with exponential backoff, or
+// moves the delivery to the dead-letter list after the last attempt.
+func (a *Application) recordDeliveryAttempt(ctx context.Context, deliveryID string, statusCode int, sendErr error) error {
+	err := a.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		delivery, err := a.WebhookRepo.GetDeliveryForUpdate(ctx, deliveryID)
+		if err != nil {
+			return err
+		}
+
+		now := a.now()
+		delivery.Attempts++
+		delivery.LastStatusCode = statusCode
+		delivery.LastError = ""
+		delivery.UpdatedAt = now
+
+		switch {
+		case sendErr == nil && statusCode >= 200 && statusCode < 300:
+			delivery.Status = entities.DeliverySucceeded
+			delivery.DeliveredAt = &now
+		case int64(delivery.Attempts) >= a.webhookConf.MaxAttempts:
+			delivery.Status = entities.DeliveryDead
+		default:
+			delivery.NextAttemptAt = now.Add(a.webhookBackoff(delivery.Attempts))
+		}
+		if sendErr != nil {
+			delivery.LastError = sendErr.Error()
+		}
+
+		if delivery.Status == entities.DeliveryDead {
+			a.log.WarnContext(ctx, "webhook delivery failed for the last time",
+				slog.String("delivery_id", delivery.ID),
+				slog.String("webhook_id", delivery.WebhookId),
+				slog.Int("attempts", delivery.Attempts))
+		}
+
+		_, err = a.WebhookRepo.UpdateDelivery(ctx, delivery)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("error recording webhook delivery attempt: %w", err)
+	}
+	return nil
+}
+
+// now is the current time as stored in the database.
+func (a *Application) now() time.Time {
+	clock := a.clock
+	if clock == nil {
+		clock = time.Now
+	}
+	return clock().UTC().Truncate(time.Microsecond)
+}
+
+// webhookBackoff is the wait after the given number of failed attempts:
+// WEBHOOK_BACKOFF, doubled after each further attempt, up to
+// maxWebhookBackoff.
+func (a *Application) webhookBackoff(attempts int) time.Duration {
+	backoff := a.webhookConf.Backoff
+	for i := 1; i < attempts && backoff < maxWebhookBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxWebhookBackoff)
+}
+
+// ListWebhookDeliveries returns the latest deliveries, newest first. Filter
+// by entities.DeliveryDead to get the dead-letter list.
+func (a *Application) ListWebhookDeliveries(ctx context.Context, filter entities.WebhookDeliveryFilter) ([]entities.WebhookDelivery, error) {
+	deliveries, err := a.WebhookRepo.ListDeliveries(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("error listing webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// ReplayWebhookDelivery sends a succeeded or dead delivery again, with the
+// same payload and a fresh set of attempts.
+func (a *Application) ReplayWebhookDelivery(ctx context.Context, deliveryID string) (entities.WebhookDelivery, error) {
+	var delivery entities.WebhookDelivery
+
+	err := a.Transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		delivery, err = a.WebhookRepo.GetDeliveryForUpdate(ctx, deliveryID)
+		if err != nil {
+			return err
+		}
+		if delivery.Status == entities.DeliveryPending {
+			return entities.ErrDeliveryPending
+		}
+
+		now := a.now()
+		delivery.Status = entities.DeliveryPending
+		delivery.Attempts = 0
+		delivery.NextAttemptAt = now
+		delivery.LastStatusCode = 0
+		delivery.LastError = ""
+		delivery.DeliveredAt = nil
+		delivery.UpdatedAt = now
+
+		delivery, err = a.WebhookRepo.UpdateDelivery(ctx, delivery)
+		return err
+	})
+	if err != nil {
+		return entities.WebhookDelivery{}, fmt.Errorf("error replaying webhook delivery: %w", err)
+	}
+	return delivery, nil
+}
+
+// RegisterWebhookDelivery periodically sends due webhook deliveries. A zero
+// poll interval disables it.
+func RegisterWebhookDelivery(lc fx.Lifecycle, app *Application) {
+	if app.webhookConf.PollInterval <= 0 {
+		return
+	}
+
+	runPeriodically(lc, app.webhookConf.PollInterval, func(ctx context.Context) {
+		_, err := app.DeliverWebhooks(ctx)
+		if err != nil {
+			app.log.ErrorContext(ctx, "error delivering webhooks", slog.String("error", err.Error()))
+		}
+	})
+}
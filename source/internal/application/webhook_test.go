@@ -0,0 +1,619 @@
+package application
+
+import (
+	"TestProject/source/config"
+	"TestProject/source/internal/entities"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type mockWebhookRepo struct {
+	mu         sync.Mutex
+	webhooks   []entities.Webhook
+	deliveries []entities.WebhookDelivery
+}
+
+func newMockWebhookRepo() *mockWebhookRepo {
+	return &mockWebhookRepo{}
+}
+
+func (m *mockWebhookRepo) Create(ctx context.Context, webhook entities.Webhook) (entities.Webhook, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.webhooks = append(m.webhooks, webhook)
+	return webhook, nil
+}
+
+func (m *mockWebhookRepo) GetByID(ctx context.Context, webhookID string) (entities.Webhook, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, webhook := range m.webhooks {
+		if webhook.ID == webhookID {
+			return webhook, nil
+		}
+	}
+	return entities.Webhook{}, entities.ErrWebhookNotFound
+}
+
+func (m *mockWebhookRepo) List(ctx context.Context, walletID string) ([]entities.Webhook, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var result []entities.Webhook
+	for _, webhook := range m.webhooks {
+		if walletID == "" || webhook.WalletId == walletID {
+			result = append(result, webhook)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockWebhookRepo) ListForWallet(ctx context.Context, walletID string) ([]entities.Webhook, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var result []entities.Webhook
+	for _, webhook := range m.webhooks {
+		if webhook.WalletId == "" || webhook.WalletId == walletID {
+			result = append(result, webhook)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockWebhookRepo) Delete(ctx context.Context, webhookID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, webhook := range m.webhooks {
+		if webhook.ID == webhookID {
+			m.webhooks = append(m.webhooks[:i], m.webhooks[i+1:]...)
+			return nil
+		}
+	}
+	return entities.ErrWebhookNotFound
+}
+
+func (m *mockWebhookRepo) CreateDeliveries(ctx context.Context, deliveries []entities.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, delivery := range deliveries {
+		if m.findDelivery(func(d entities.WebhookDelivery) bool {
+			return d.WebhookId == delivery.WebhookId && d.EventId == delivery.EventId
+		}) >= 0 {
+			continue
+		}
+		m.deliveries = append(m.deliveries, delivery)
+	}
+	return nil
+}
+
+func (m *mockWebhookRepo) ClaimDueDelivery(ctx context.Context, now time.Time, skipWebhooks []string) (entities.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i := m.findDelivery(func(d entities.WebhookDelivery) bool {
+		return d.Status == entities.DeliveryPending && !d.NextAttemptAt.After(now) && !slices.Contains(skipWebhooks, d.WebhookId)
+	})
+	if i < 0 {
+		return entities.WebhookDelivery{}, entities.ErrDeliveryNotFound
+	}
+	return m.deliveries[i], nil
+}
+
+func (m *mockWebhookRepo) GetDeliveryForUpdate(ctx context.Context, deliveryID string) (entities.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i := m.findDelivery(func(d entities.WebhookDelivery) bool { return d.ID == deliveryID })
+	if i < 0 {
+		return entities.WebhookDelivery{}, entities.ErrDeliveryNotFound
+	}
+	return m.deliveries[i], nil
+}
+
+func (m *mockWebhookRepo) UpdateDelivery(ctx context.Context, delivery entities.WebhookDelivery) (entities.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i := m.findDelivery(func(d entities.WebhookDelivery) bool { return d.ID == delivery.ID })
+	if i < 0 {
+		return entities.WebhookDelivery{}, entities.ErrDeliveryNotFound
+	}
+	m.deliveries[i] = delivery
+	return delivery, nil
+}
+
+func (m *mockWebhookRepo) ListDeliveries(ctx context.Context, filter entities.WebhookDeliveryFilter) ([]entities.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var result []entities.WebhookDelivery
+	for i := len(m.deliveries) - 1; i >= 0 && len(result) < filter.Limit; i-- {
+		delivery := m.deliveries[i]
+		if filter.WebhookId != "" && delivery.WebhookId != filter.WebhookId {
+			continue
+		}
+		if filter.Status != "" && delivery.Status != filter.Status {
+			continue
+		}
+		result = append(result, delivery)
+	}
+	return result, nil
+}
+
+func (m *mockWebhookRepo) findDelivery(match func(entities.WebhookDelivery) bool) int {
+	for i, delivery := range m.deliveries {
+		if match(delivery) {
+			return i
+		}
+	}
+	return -1
+}
+
+type webhookRequest struct {
+	url    string
+	header http.Header
+	body   []byte
+}
+
+// mockWebhookClient answers every request with status, after calling
+// onPost if set. It refuses the URLs in rejected.
+type mockWebhookClient struct {
+	mu       sync.Mutex
+	status   int
+	onPost   func()
+	rejected map[string]bool
+	requests []webhookRequest
+}
+
+func (m *mockWebhookClient) CheckURL(ctx context.Context, url string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.rejected[url] {
+		return entities.ErrWebhookURLNotAllowed
+	}
+	return nil
+}
+
+func (m *mockWebhookClient) Post(ctx context.Context, url string, header http.Header, body []byte) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.onPost != nil {
+		m.onPost()
+	}
+	m.requests = append(m.requests, webhookRequest{url: url, header: header, body: body})
+	if m.status == 0 {
+		return 0, errors.New("connection refused")
+	}
+	return m.status, nil
+}
+
+// testClock is a clock for Application.clock that moves only when told.
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newTestClock() *testClock {
+	return &testClock{now: time.Now().UTC().Truncate(time.Microsecond)}
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newWebhookTestApplication(walletRepo *mockWalletRepo, client *mockWebhookClient) *Application {
+	app, _ := newTestApplication(walletRepo)
+	app.log = slog.New(slog.DiscardHandler)
+	app.Publisher = &mockPublisher{}
+	app.WebhookClient = client
+	app.webhookConf = config.WebhookConfig{Timeout: time.Second, MaxAttempts: 3, Backoff: time.Minute}
+	return app
+}
+
+func TestApplication_Webhooks_DeliverSignedEvents(t *testing.T) {
+	repo := newMockWalletRepo()
+	client := &mockWebhookClient{status: 200}
+	app := newWebhookTestApplication(repo, client)
+	ctx := context.Background()
+
+	wallet := entities.NewWallet()
+	other := entities.NewWallet()
+	repo.wallets[wallet.ID] = wallet
+	repo.wallets[other.ID] = other
+
+	own := entities.NewWebhook()
+	own.WalletId = wallet.ID
+	own.URL = "https://example.com/own"
+	own, err := app.CreateWebhook(ctx, own)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	global := entities.NewWebhook()
+	global.URL = "https://example.com/global"
+	global, err = app.CreateWebhook(ctx, global)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	foreign := entities.NewWebhook()
+	foreign.WalletId = other.ID
+	foreign.URL = "https://example.com/foreign"
+	_, err = app.CreateWebhook(ctx, foreign)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	missing := entities.NewWebhook()
+	missing.WalletId = entities.NewWallet().ID
+	_, err = app.CreateWebhook(ctx, missing)
+	if !errors.Is(err, entities.ErrWalletNotFound) {
+		t.Fatalf("expected ErrWalletNotFound, got %v", err)
+	}
+
+	_, err = app.ProcessTransaction(ctx, entities.Transaction{WalletId: wallet.ID, OperationType: deposit, Amount: dec("100")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = app.RelayOutbox(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// A second relay of the same event must not queue it again.
+	_, err = app.RelayOutbox(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	attempted, err := app.DeliverWebhooks(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if attempted != 2 {
+		t.Fatalf("expected 2 deliveries, got %d", attempted)
+	}
+
+	urls := make(map[string]bool)
+	for _, request := range client.requests {
+		urls[request.url] = true
+
+		timestamp, err := strconv.ParseInt(request.header.Get("X-Webhook-Timestamp"), 10, 64)
+		if err != nil {
+			t.Fatalf("invalid timestamp header: %v", err)
+		}
+		secret := own.Secret
+		if request.url == global.URL {
+			secret = global.Secret
+		}
+		signature := request.header.Get("X-Webhook-Signature")
+		if signature != "sha256="+entities.SignWebhook(secret, timestamp, request.body) {
+			t.Errorf("signature %s does not match the body", signature)
+		}
+		if request.header.Get("X-Webhook-Event") != entities.EventWalletCredited {
+			t.Errorf("unexpected event header %s", request.header.Get("X-Webhook-Event"))
+		}
+		if !strings.Contains(string(request.body), wallet.ID) {
+			t.Errorf("expected the event of %s, got %s", wallet.ID, request.body)
+		}
+	}
+	if !urls[own.URL] || !urls[global.URL] || urls[foreign.URL] {
+		t.Errorf("unexpected webhooks called: %v", urls)
+	}
+
+	deliveries, err := app.ListWebhookDeliveries(ctx, entities.WebhookDeliveryFilter{Status: entities.DeliverySucceeded, Limit: 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(deliveries) != 2 || deliveries[0].DeliveredAt == nil || deliveries[0].Attempts != 1 {
+		t.Errorf("expected 2 delivered deliveries, got %+v", deliveries)
+	}
+}
+
+func TestApplication_Webhooks_RetryDeadLetterAndReplay(t *testing.T) {
+	repo := newMockWalletRepo()
+	client := &mockWebhookClient{status: 503}
+	app := newWebhookTestApplication(repo, client)
+	clock := newTestClock()
+	app.clock = clock.Now
+	ctx := context.Background()
+
+	wallet := entities.NewWallet()
+	repo.wallets[wallet.ID] = wallet
+
+	webhook := entities.NewWebhook()
+	webhook.WalletId = wallet.ID
+	webhook.URL = "https://example.com/hook"
+	_, err := app.CreateWebhook(ctx, webhook)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = app.ProcessTransaction(ctx, entities.Transaction{WalletId: wallet.ID, OperationType: deposit, Amount: dec("10")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = app.RelayOutbox(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	now := clock.Now()
+	waits := []time.Duration{time.Minute, 2 * time.Minute}
+	for i, wait := range waits {
+		clock.Set(now)
+		attempted, err := app.DeliverWebhooks(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if attempted != 1 {
+			t.Fatalf("attempt %d: expected 1 delivery, got %d", i+1, attempted)
+		}
+
+		delivery := app.WebhookRepo.(*mockWebhookRepo).deliveries[0]
+		if delivery.Status != entities.DeliveryPending || !delivery.NextAttemptAt.Equal(now.Add(wait)) {
+			t.Fatalf("attempt %d: expected a retry after %s, got %+v", i+1, wait, delivery)
+		}
+
+		// Nothing is due before the backoff has passed.
+		clock.Set(now.Add(wait - time.Second))
+		attempted, _ = app.DeliverWebhooks(ctx)
+		if attempted != 0 {
+			t.Fatalf("attempt %d: expected no delivery during backoff, got %d", i+1, attempted)
+		}
+		now = now.Add(wait)
+	}
+
+	client.status = 0
+	clock.Set(now)
+	_, err = app.DeliverWebhooks(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	dead, err := app.ListWebhookDeliveries(ctx, entities.WebhookDeliveryFilter{Status: entities.DeliveryDead, Limit: 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(dead) != 1 || dead[0].Attempts != 3 || dead[0].LastError != "connection refused" || dead[0].LastStatusCode != 0 {
+		t.Fatalf("expected the delivery in the dead-letter list, got %+v", dead)
+	}
+
+	clock.Set(now.Add(24 * time.Hour))
+	attempted, _ := app.DeliverWebhooks(ctx)
+	if attempted != 0 {
+		t.Fatalf("expected dead deliveries not to be retried, got %d", attempted)
+	}
+
+	replayed, err := app.ReplayWebhookDelivery(ctx, dead[0].ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if replayed.Status != entities.DeliveryPending || replayed.Attempts != 0 {
+		t.Fatalf("expected a fresh pending delivery, got %+v", replayed)
+	}
+	_, err = app.ReplayWebhookDelivery(ctx, dead[0].ID)
+	if !errors.Is(err, entities.ErrDeliveryPending) {
+		t.Fatalf("expected ErrDeliveryPending, got %v", err)
+	}
+
+	client.status = 204
+	attempted, err = app.DeliverWebhooks(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if attempted != 1 {
+		t.Fatalf("expected the replayed delivery to be sent, got %d", attempted)
+	}
+	delivery := app.WebhookRepo.(*mockWebhookRepo).deliveries[0]
+	if delivery.Status != entities.DeliverySucceeded {
+		t.Fatalf("expected the replayed delivery to succeed, got %+v", delivery)
+	}
+	if string(client.requests[len(client.requests)-1].body) != string(client.requests[0].body) {
+		t.Error("expected the replay to send the original payload")
+	}
+}
+
+func TestApplication_Webhooks_ClockPerDelivery(t *testing.T) {
+	repo := newMockWalletRepo()
+	client := &mockWebhookClient{status: 500}
+	app := newWebhookTestApplication(repo, client)
+	clock := newTestClock()
+	app.clock = clock.Now
+	ctx := context.Background()
+
+	// Every request takes the whole timeout.
+	client.onPost = func() { clock.Advance(app.webhookConf.Timeout) }
+
+	// Both deliveries go to one webhook, which gets one request at a time.
+	wallet := entities.NewWallet()
+	repo.wallets[wallet.ID] = wallet
+	webhook := entities.NewWebhook()
+	webhook.WalletId = wallet.ID
+	webhook.URL = "https://example.com/hook"
+	_, err := app.CreateWebhook(ctx, webhook)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for range 2 {
+		_, err = app.ProcessTransaction(ctx, entities.Transaction{WalletId: wallet.ID, OperationType: deposit, Amount: dec("10")})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	_, err = app.RelayOutbox(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	start := clock.Now()
+	attempted, err := app.DeliverWebhooks(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if attempted != 2 {
+		t.Fatalf("expected 2 deliveries, got %d", attempted)
+	}
+
+	// The second request is signed when it is sent, after the first one.
+	second := client.requests[1].header.Get("X-Webhook-Timestamp")
+	if second != strconv.FormatInt(start.Add(app.webhookConf.Timeout).Unix(), 10) {
+		t.Errorf("expected the second request signed a timeout after the start, got %s", second)
+	}
+
+	// Each retry is scheduled from the end of its own attempt.
+	for i, delivery := range app.WebhookRepo.(*mockWebhookRepo).deliveries {
+		sentAt := start.Add(time.Duration(i+1) * app.webhookConf.Timeout)
+		if !delivery.NextAttemptAt.Equal(sentAt.Add(app.webhookConf.Backoff)) {
+			t.Errorf("delivery %d: expected the next attempt at %s, got %s", i, sentAt.Add(app.webhookConf.Backoff), delivery.NextAttemptAt)
+		}
+	}
+}
+
+// blockingWebhookClient holds the requests to slowURL until release is
+// closed and answers the others at once.
+type blockingWebhookClient struct {
+	slowURL string
+	release chan struct{}
+
+	mu       sync.Mutex
+	inFlight int
+	maxSlow  int
+	fast     int
+	fastDone chan struct{}
+}
+
+func (m *blockingWebhookClient) CheckURL(ctx context.Context, url string) error {
+	return nil
+}
+
+func (m *blockingWebhookClient) Post(ctx context.Context, url string, header http.Header, body []byte) (int, error) {
+	if url != m.slowURL {
+		m.mu.Lock()
+		m.fast++
+		if m.fast == 3 {
+			close(m.fastDone)
+		}
+		m.mu.Unlock()
+		return 200, nil
+	}
+
+	m.mu.Lock()
+	m.inFlight++
+	m.maxSlow = max(m.maxSlow, m.inFlight)
+	m.mu.Unlock()
+
+	<-m.release
+
+	m.mu.Lock()
+	m.inFlight--
+	m.mu.Unlock()
+	return 200, nil
+}
+
+func TestApplication_Webhooks_SlowReceiverDoesNotBlockOthers(t *testing.T) {
+	repo := newMockWalletRepo()
+	client := &blockingWebhookClient{
+		slowURL:  "https://example.com/slow",
+		release:  make(chan struct{}),
+		fastDone: make(chan struct{}),
+	}
+	app, _ := newTestApplication(repo)
+	app.log = slog.New(slog.DiscardHandler)
+	app.Publisher = &mockPublisher{}
+	app.WebhookClient = client
+	app.webhookConf = config.WebhookConfig{Timeout: time.Second, MaxAttempts: 3, Backoff: time.Minute}
+	ctx := context.Background()
+
+	wallet := entities.NewWallet()
+	repo.wallets[wallet.ID] = wallet
+	for _, url := range []string{client.slowURL, "https://example.com/fast"} {
+		webhook := entities.NewWebhook()
+		webhook.WalletId = wallet.ID
+		webhook.URL = url
+		_, err := app.CreateWebhook(ctx, webhook)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	for range 3 {
+		_, err := app.ProcessTransaction(ctx, entities.Transaction{WalletId: wallet.ID, OperationType: deposit, Amount: dec("10")})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	_, err := app.RelayOutbox(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	type result struct {
+		attempted int
+		err       error
+	}
+	done := make(chan result)
+	go func() {
+		attempted, err := app.DeliverWebhooks(ctx)
+		done <- result{attempted, err}
+	}()
+
+	select {
+	case <-client.fastDone:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the fast webhook to get its deliveries while the slow one hangs")
+	}
+	close(client.release)
+
+	got := <-done
+	if got.err != nil {
+		t.Fatalf("unexpected error: %v", got.err)
+	}
+	if got.attempted != 6 {
+		t.Errorf("expected 6 deliveries, got %d", got.attempted)
+	}
+	if client.maxSlow != 1 {
+		t.Errorf("expected one request at a time to the slow webhook, got %d", client.maxSlow)
+	}
+}
+
+func TestApplication_Webhooks_RejectedURL(t *testing.T) {
+	client := &mockWebhookClient{rejected: map[string]bool{"http://169.254.169.254/latest": true}}
+	app := newWebhookTestApplication(newMockWalletRepo(), client)
+
+	webhook := entities.NewWebhook()
+	webhook.URL = "http://169.254.169.254/latest"
+	_, err := app.CreateWebhook(context.Background(), webhook)
+	if !errors.Is(err, entities.ErrWebhookURLNotAllowed) {
+		t.Fatalf("expected ErrWebhookURLNotAllowed, got %v", err)
+	}
+	if len(app.WebhookRepo.(*mockWebhookRepo).webhooks) != 0 {
+		t.Error("expected the webhook not to be saved")
+	}
+}
@@ -0,0 +1,56 @@
+package entities
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/shopspring/decimal"
+)
+
+// MaxAmountIntegerDigits is how many digits before the decimal point the
+// balance and amount columns can hold.
+const MaxAmountIntegerDigits = 13
+
+var (
+	amountPattern = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+	amountLimit   = decimal.New(1, MaxAmountIntegerDigits)
+)
+
+// ParseAmount parses a plain decimal string such as "0.10" without going
+// through a float. Exponents, signs other than a leading minus, and
+// surrounding whitespace are rejected.
+func ParseAmount(value string) (decimal.Decimal, error) {
+	if !amountPattern.MatchString(value) {
+		return decimal.Decimal{}, fmt.Errorf("%w: %q is not a decimal number", ErrInvalidAmount, value)
+	}
+
+	amount, err := decimal.NewFromString(value)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("%w: %v", ErrInvalidAmount, err)
+	}
+
+	err = CheckAmountRange(amount)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+
+	return amount, nil
+}
+
+// CheckAmountRange rejects values that do not fit the storage columns.
+func CheckAmountRange(amount decimal.Decimal) error {
+	if amount.Abs().GreaterThanOrEqual(amountLimit) {
+		return fmt.Errorf("%w: %s has more than %d integer digits", ErrAmountOverflow, amount.String(), MaxAmountIntegerDigits)
+	}
+	return nil
+}
+
+// FormatAmount renders amount with exactly as many decimal places as the
+// currency uses, e.g. "10.50" for USD and "10" for JPY.
+func FormatAmount(amount decimal.Decimal, currency string) string {
+	scale, err := CurrencyScale(currency)
+	if err != nil {
+		return amount.String()
+	}
+	return amount.StringFixed(scale)
+}
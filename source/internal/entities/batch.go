@@ -0,0 +1,33 @@
+package entities
+
+import "fmt"
+
+// Batch modes. ALL_OR_NOTHING commits every operation of a batch or none of
+// them; BEST_EFFORT commits each operation on its own.
+const (
+	BatchAllOrNothing = "ALL_OR_NOTHING"
+	BatchBestEffort   = "BEST_EFFORT"
+)
+
+// BatchResult is the outcome of one operation of a batch, in request order.
+// Err is set when the operation failed.
+type BatchResult struct {
+	Index       int
+	Transaction Transaction
+	Err         error
+}
+
+// BatchItemError is returned for an ALL_OR_NOTHING batch and names the
+// operation that rolled the batch back.
+type BatchItemError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchItemError) Error() string {
+	return fmt.Sprintf("batch operation %d: %s", e.Index, e.Err.Error())
+}
+
+func (e *BatchItemError) Unwrap() error {
+	return e.Err
+}
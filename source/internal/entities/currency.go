@@ -0,0 +1,45 @@
+package entities
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// DefaultCurrency is used for wallets created without an explicit currency.
+const DefaultCurrency = "RUB"
+
+// currencyScales maps supported ISO 4217 codes to their minor-unit scale.
+var currencyScales = map[string]int32{
+	"AED": 2, "AMD": 2, "AUD": 2, "AZN": 2, "BHD": 3, "BYN": 2, "CAD": 2,
+	"CHF": 2, "CLP": 0, "CNY": 2, "CZK": 2, "DKK": 2, "EUR": 2, "GBP": 2,
+	"GEL": 2, "HKD": 2, "HUF": 2, "IDR": 2, "ILS": 2, "INR": 2, "IQD": 3,
+	"ISK": 0, "JOD": 3, "JPY": 0, "KGS": 2, "KRW": 0, "KWD": 3, "KZT": 2,
+	"LYD": 3, "MDL": 2, "MXN": 2, "NOK": 2, "NZD": 2, "OMR": 3, "PLN": 2,
+	"RUB": 2, "SAR": 2, "SEK": 2, "SGD": 2, "THB": 2, "TJS": 2, "TND": 3,
+	"TRY": 2, "UAH": 2, "USD": 2, "UZS": 2, "VND": 0, "ZAR": 2,
+}
+
+// CurrencyScale returns how many decimal places amounts in the currency may have.
+func CurrencyScale(currency string) (int32, error) {
+	scale, ok := currencyScales[currency]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnsupportedCurrency, currency)
+	}
+	return scale, nil
+}
+
+// ValidateAmount rejects amounts with more decimal places than the currency
+// allows or too large for storage. Trailing zeros are fine: 10.00 is a valid
+// JPY amount, 10.50 is not.
+func ValidateAmount(amount decimal.Decimal, currency string) error {
+	scale, err := CurrencyScale(currency)
+	if err != nil {
+		return err
+	}
+
+	if !amount.Equal(amount.Truncate(scale)) {
+		return fmt.Errorf("%w: %s allows %d decimal places, got %s", ErrInvalidAmountPrecision, currency, scale, amount.String())
+	}
+	return CheckAmountRange(amount)
+}
@@ -0,0 +1,55 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Customer owns wallets. Email, when set, is unique across customers.
+type Customer struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email,omitempty"`
+	Phone     string    `json:"phone,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func NewCustomer() Customer {
+	return Customer{ID: uuid.NewString()}
+}
+
+// CustomerWallets is a customer with all of its wallets. Totals sum the
+// wallets per currency, since balances in different currencies can't be
+// added up.
+type CustomerWallets struct {
+	Customer Customer
+	Wallets  []Wallet
+	Totals   []CurrencyTotal
+}
+
+type CurrencyTotal struct {
+	Currency  string          `json:"currency"`
+	Balance   decimal.Decimal `json:"balance"`
+	Available decimal.Decimal `json:"available"`
+}
+
+// TotalsByCurrency sums the balances of the wallets per currency, in the
+// order the currencies first appear.
+func TotalsByCurrency(wallets []Wallet) []CurrencyTotal {
+	index := make(map[string]int)
+	totals := make([]CurrencyTotal, 0)
+	for _, wallet := range wallets {
+		i, ok := index[wallet.Currency]
+		if !ok {
+			i = len(totals)
+			index[wallet.Currency] = i
+			totals = append(totals, CurrencyTotal{Currency: wallet.Currency})
+		}
+		totals[i].Balance = totals[i].Balance.Add(wallet.Balance)
+		totals[i].Available = totals[i].Available.Add(wallet.Available())
+	}
+	return totals
+}
@@ -3,6 +3,49 @@ package entities
 import "errors"
 
 var (
-	ErrInsufficientFunds = errors.New("insufficient funds")
-	ErrInvalidOperation  = errors.New("invalid operation type")
+	ErrInsufficientFunds       = errors.New("insufficient funds")
+	ErrInvalidOperation        = errors.New("invalid operation type")
+	ErrWalletNotFound          = errors.New("wallet not found")
+	ErrIdempotencyKeyReused    = errors.New("idempotency key reused with a different request")
+	ErrSameWalletTransfer      = errors.New("cannot transfer to the same wallet")
+	ErrUnsupportedCurrency     = errors.New("unsupported currency")
+	ErrCurrencyMismatch        = errors.New("currency does not match the wallet")
+	ErrInvalidAmountPrecision  = errors.New("amount has too many decimal places for the currency")
+	ErrInvalidAmount           = errors.New("invalid amount")
+	ErrAmountOverflow          = errors.New("amount is too large")
+	ErrHoldNotFound            = errors.New("hold not found")
+	ErrHoldNotActive           = errors.New("hold is not active")
+	ErrHoldExpired             = errors.New("hold has expired")
+	ErrCaptureExceedsHold      = errors.New("capture amount exceeds the held amount")
+	ErrTransactionNotFound     = errors.New("transaction not found")
+	ErrNotReversible           = errors.New("transaction cannot be reversed")
+	ErrReversalExceedsOriginal = errors.New("reversal amount exceeds the remaining amount of the original transaction")
+	ErrUnbalancedJournal       = errors.New("journal entry does not balance")
+	ErrWalletFrozen            = errors.New("wallet is frozen")
+	ErrWalletClosed            = errors.New("wallet is closed")
+	ErrWalletNotEmpty          = errors.New("wallet balance is not zero")
+	ErrInvalidStatusTransition = errors.New("invalid wallet status transition")
+	ErrOverdraftInUse          = errors.New("overdraft limit is below the credit already in use")
+	ErrLimitExceeded           = errors.New("velocity limit exceeded")
+	ErrLimitsNotFound          = errors.New("wallet limits not found")
+	ErrScheduleNotFound        = errors.New("schedule not found")
+	ErrScheduleNotActive       = errors.New("schedule cannot change from its current status")
+	ErrInvalidSchedule         = errors.New("invalid schedule")
+	ErrInvalidBatch            = errors.New("invalid batch")
+	ErrSnapshotNotFound        = errors.New("balance snapshot not found")
+	ErrAsOfInFuture            = errors.New("as_of is in the future")
+	ErrInvalidStatementPeriod  = errors.New("invalid statement period")
+	ErrFeeRuleNotFound         = errors.New("fee rule not found")
+	ErrInvalidFeeRule          = errors.New("invalid fee rule")
+	ErrInterestPlanNotFound    = errors.New("interest plan not found")
+	ErrInvalidInterestPlan     = errors.New("invalid interest plan")
+	ErrInterestPayoutFailed    = errors.New("interest payout failed")
+	ErrCustomerNotFound        = errors.New("customer not found")
+	ErrCustomerExists          = errors.New("customer with this email already exists")
+	ErrCustomerHasWallets      = errors.New("customer has wallets")
+	ErrWebhookNotFound         = errors.New("webhook not found")
+	ErrDeliveryNotFound        = errors.New("webhook delivery not found")
+	ErrDeliveryPending         = errors.New("webhook delivery is still pending")
+	ErrWebhookURLNotAllowed    = errors.New("webhook url is not allowed")
+	ErrStreamInterrupted       = errors.New("wallet stream interrupted")
 )
@@ -0,0 +1,52 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	EventWalletCredited = "WalletCredited"
+	EventWalletDebited  = "WalletDebited"
+)
+
+// WalletEvent announces a balance change to other services. Sequence is
+// the position in the outbox: the events of one wallet have increasing
+// sequences in the order their transactions committed. Consumers may get
+// an event more than once and should deduplicate by ID.
+type WalletEvent struct {
+	ID            string          `json:"id"`
+	Sequence      int64           `json:"sequence"`
+	Type          string          `json:"type"`
+	WalletId      string          `json:"wallet_id"`
+	TransactionId string          `json:"transaction_id"`
+	OperationType string          `json:"operation_type"`
+	Currency      string          `json:"currency"`
+	Amount        decimal.Decimal `json:"amount"`
+	Fee           decimal.Decimal `json:"fee"`
+	Balance       decimal.Decimal `json:"balance"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+}
+
+// NewWalletEvent describes transaction as a WalletCredited event when it
+// added money to the wallet and as WalletDebited otherwise.
+func NewWalletEvent(transaction Transaction, credit bool) WalletEvent {
+	eventType := EventWalletDebited
+	if credit {
+		eventType = EventWalletCredited
+	}
+	return WalletEvent{
+		ID:            uuid.NewString(),
+		Type:          eventType,
+		WalletId:      transaction.WalletId,
+		TransactionId: transaction.ID,
+		OperationType: transaction.OperationType,
+		Currency:      transaction.Currency,
+		Amount:        transaction.Amount,
+		Fee:           transaction.Fee,
+		Balance:       transaction.Balance,
+		OccurredAt:    transaction.CreatedAt,
+	}
+}
@@ -0,0 +1,115 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	FeeFlat       = "FLAT"
+	FeePercentage = "PERCENTAGE"
+	FeeTiered     = "TIERED"
+)
+
+var hundred = decimal.NewFromInt(100)
+
+// FeeRule prices one operation type in one currency. A rule without
+// WalletId is the default for every wallet in the currency; a wallet's own
+// rule replaces it. Min and Max cap the fee of any type; zero means no cap.
+type FeeRule struct {
+	WalletId      string          `json:"wallet_id,omitempty"`
+	OperationType string          `json:"operation_type"`
+	Currency      string          `json:"currency"`
+	Type          string          `json:"type"`
+	Flat          decimal.Decimal `json:"flat"`
+	Percent       decimal.Decimal `json:"percent"`
+	Tiers         []FeeTier       `json:"tiers,omitempty"`
+	Min           decimal.Decimal `json:"min"`
+	Max           decimal.Decimal `json:"max"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+// FeeTier applies to amounts from From up to the next tier's From. Its fee
+// is Flat plus Percent of the whole amount.
+type FeeTier struct {
+	From    decimal.Decimal `json:"from"`
+	Flat    decimal.Decimal `json:"flat"`
+	Percent decimal.Decimal `json:"percent"`
+}
+
+// Validate checks that the rule can price an amount: no negative values,
+// percentages up to 100, Max not below Min and tiers in ascending order.
+func (r FeeRule) Validate() error {
+	values := []decimal.Decimal{r.Flat, r.Percent, r.Min, r.Max}
+	for _, tier := range r.Tiers {
+		values = append(values, tier.From, tier.Flat, tier.Percent)
+	}
+	for _, value := range values {
+		if value.IsNegative() {
+			return fmt.Errorf("%w: negative value %s", ErrInvalidFeeRule, value.String())
+		}
+	}
+	if r.Percent.GreaterThan(hundred) {
+		return fmt.Errorf("%w: percent is above 100", ErrInvalidFeeRule)
+	}
+	if r.Max.IsPositive() && r.Max.LessThan(r.Min) {
+		return fmt.Errorf("%w: max is below min", ErrInvalidFeeRule)
+	}
+
+	switch r.Type {
+	case FeeFlat, FeePercentage:
+		if len(r.Tiers) > 0 {
+			return fmt.Errorf("%w: tiers are only used by %s rules", ErrInvalidFeeRule, FeeTiered)
+		}
+	case FeeTiered:
+		if len(r.Tiers) == 0 {
+			return fmt.Errorf("%w: %s rule without tiers", ErrInvalidFeeRule, FeeTiered)
+		}
+		for i, tier := range r.Tiers {
+			if tier.Percent.GreaterThan(hundred) {
+				return fmt.Errorf("%w: tier %d percent is above 100", ErrInvalidFeeRule, i)
+			}
+			if i > 0 && !tier.From.GreaterThan(r.Tiers[i-1].From) {
+				return fmt.Errorf("%w: tiers must be in ascending order of from", ErrInvalidFeeRule)
+			}
+		}
+	default:
+		return fmt.Errorf("%w: type %s", ErrInvalidFeeRule, r.Type)
+	}
+	return nil
+}
+
+// Calculate returns the fee for amount, capped by Min and Max and rounded
+// half away from zero to the scale of the currency. Amounts below the first
+// tier of a TIERED rule only pay Min.
+func (r FeeRule) Calculate(amount decimal.Decimal) (decimal.Decimal, error) {
+	scale, err := CurrencyScale(r.Currency)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+
+	fee := decimal.Zero
+	switch r.Type {
+	case FeeFlat:
+		fee = r.Flat
+	case FeePercentage:
+		fee = amount.Mul(r.Percent).Div(hundred)
+	case FeeTiered:
+		for _, tier := range r.Tiers {
+			if amount.LessThan(tier.From) {
+				break
+			}
+			fee = tier.Flat.Add(amount.Mul(tier.Percent).Div(hundred))
+		}
+	}
+
+	if fee.LessThan(r.Min) {
+		fee = r.Min
+	}
+	if r.Max.IsPositive() && fee.GreaterThan(r.Max) {
+		fee = r.Max
+	}
+	return fee.Round(scale), nil
+}
@@ -0,0 +1,39 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	HoldActive   = "ACTIVE"
+	HoldCaptured = "CAPTURED"
+	HoldVoided   = "VOIDED"
+	HoldExpired  = "EXPIRED"
+)
+
+// Hold reserves Amount on a wallet without moving it. While the hold is
+// active the amount counts against the wallet's available balance. A capture
+// withdraws up to Amount and releases the rest; a void or expiry releases
+// everything.
+type Hold struct {
+	ID             string          `json:"id"`
+	WalletId       string          `json:"wallet_id"`
+	Currency       string          `json:"currency"`
+	Amount         decimal.Decimal `json:"amount"`
+	CapturedAmount decimal.Decimal `json:"captured_amount"`
+	Status         string          `json:"status"`
+	TransactionId  string          `json:"transaction_id,omitempty"`
+	ExpiresAt      time.Time       `json:"expires_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+func NewHold() Hold {
+	return Hold{
+		ID:     uuid.NewString(),
+		Status: HoldActive,
+	}
+}
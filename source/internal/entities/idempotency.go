@@ -0,0 +1,18 @@
+package entities
+
+import "time"
+
+// IdempotencyRecord remembers the response produced for an Idempotency-Key.
+// StatusCode stays zero until the request that owns the key completes.
+type IdempotencyRecord struct {
+	Key         string
+	RequestHash string
+	StatusCode  int
+	Response    []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+func (r IdempotencyRecord) Completed() bool {
+	return r.StatusCode != 0
+}
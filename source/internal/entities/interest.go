@@ -0,0 +1,154 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Day-count conventions: how many days a year of interest is spread over.
+const (
+	DayCountActual365    = "ACT_365"
+	DayCountActual360    = "ACT_360"
+	DayCountActualActual = "ACT_ACT"
+)
+
+// Rounding modes applied when accrued interest is paid out.
+const (
+	RoundHalfUp   = "HALF_UP"
+	RoundHalfEven = "HALF_EVEN"
+	RoundDown     = "DOWN"
+	RoundUp       = "UP"
+)
+
+// accrualScale is the precision accrued interest is kept at between
+// payouts, well below any currency's minor unit.
+const accrualScale = 10
+
+// InterestPlan accrues interest on a wallet every day at AnnualRate percent
+// and pays it out every PayoutFrequency (DAILY, WEEKLY or MONTHLY).
+// Accrued is interest accrued but not paid yet, at full precision; only
+// the rounded part is paid and the rest carries over. NextAccrualDay is the
+// first day not accrued yet and NextPayoutAt the end of the current payout
+// period, both UTC midnights.
+type InterestPlan struct {
+	WalletId        string          `json:"wallet_id"`
+	Currency        string          `json:"currency"`
+	AnnualRate      decimal.Decimal `json:"annual_rate"`
+	DayCount        string          `json:"day_count"`
+	Rounding        string          `json:"rounding"`
+	PayoutFrequency string          `json:"payout_frequency"`
+	Accrued         decimal.Decimal `json:"accrued"`
+	NextAccrualDay  time.Time       `json:"next_accrual_day"`
+	NextPayoutAt    time.Time       `json:"next_payout_at"`
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+}
+
+// InterestAccrual is the interest of one wallet for one day. There is at
+// most one per wallet and day, which makes accruing a day again a no-op.
+type InterestAccrual struct {
+	WalletId  string          `json:"wallet_id"`
+	Day       time.Time       `json:"day"`
+	Balance   decimal.Decimal `json:"balance"`
+	Amount    decimal.Decimal `json:"amount"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// InterestPayout records the INTEREST transaction that paid the interest of
+// the period ending at PeriodEnd.
+type InterestPayout struct {
+	ID            string          `json:"id"`
+	WalletId      string          `json:"wallet_id"`
+	PeriodEnd     time.Time       `json:"period_end"`
+	Amount        decimal.Decimal `json:"amount"`
+	TransactionId string          `json:"transaction_id"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+func (p InterestPlan) Validate() error {
+	if p.AnnualRate.IsNegative() || p.AnnualRate.GreaterThan(hundred) {
+		return fmt.Errorf("%w: annual rate must be between 0 and 100", ErrInvalidInterestPlan)
+	}
+	switch p.DayCount {
+	case DayCountActual365, DayCountActual360, DayCountActualActual:
+	default:
+		return fmt.Errorf("%w: day count %s", ErrInvalidInterestPlan, p.DayCount)
+	}
+	switch p.Rounding {
+	case RoundHalfUp, RoundHalfEven, RoundDown, RoundUp:
+	default:
+		return fmt.Errorf("%w: rounding %s", ErrInvalidInterestPlan, p.Rounding)
+	}
+	switch p.PayoutFrequency {
+	case FrequencyDaily, FrequencyWeekly, FrequencyMonthly:
+	default:
+		return fmt.Errorf("%w: payout frequency %s", ErrInvalidInterestPlan, p.PayoutFrequency)
+	}
+	return nil
+}
+
+// DailyInterest returns the interest on the end-of-day balance of day.
+// Zero and negative balances earn nothing.
+func (p InterestPlan) DailyInterest(balance decimal.Decimal, day time.Time) decimal.Decimal {
+	if !balance.IsPositive() {
+		return decimal.Zero
+	}
+
+	days := int64(365)
+	switch p.DayCount {
+	case DayCountActual360:
+		days = 360
+	case DayCountActualActual:
+		days = int64(time.Date(day.Year(), time.December, 31, 0, 0, 0, 0, time.UTC).YearDay())
+	}
+
+	return balance.Mul(p.AnnualRate).Div(hundred).Div(decimal.NewFromInt(days)).Round(accrualScale)
+}
+
+// Payable rounds the accrued interest to the currency with the plan's
+// rounding mode.
+func (p InterestPlan) Payable() (decimal.Decimal, error) {
+	scale, err := CurrencyScale(p.Currency)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+
+	switch p.Rounding {
+	case RoundHalfEven:
+		return p.Accrued.RoundBank(scale), nil
+	case RoundDown:
+		return p.Accrued.RoundDown(scale), nil
+	case RoundUp:
+		return p.Accrued.RoundUp(scale), nil
+	default:
+		return p.Accrued.Round(scale), nil
+	}
+}
+
+// PeriodAfter returns the end of the payout period that follows the one
+// ending at end.
+func (p InterestPlan) PeriodAfter(end time.Time) time.Time {
+	switch p.PayoutFrequency {
+	case FrequencyDaily:
+		return end.AddDate(0, 0, 1)
+	case FrequencyWeekly:
+		return end.AddDate(0, 0, 7)
+	default:
+		return time.Date(end.Year(), end.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+	}
+}
+
+// FirstPeriodEnd returns the end of the payout period that contains day:
+// the next day, the next Monday or the first day of the next month.
+func (p InterestPlan) FirstPeriodEnd(day time.Time) time.Time {
+	switch p.PayoutFrequency {
+	case FrequencyDaily:
+		return day.AddDate(0, 0, 1)
+	case FrequencyWeekly:
+		return day.AddDate(0, 0, 7-(int(day.Weekday())+6)%7)
+	default:
+		return time.Date(day.Year(), day.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+	}
+}
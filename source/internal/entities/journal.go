@@ -0,0 +1,83 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	AccountWallet = "WALLET"
+	AccountSystem = "SYSTEM"
+)
+
+// System accounts are the counterparties of money entering or leaving the
+// wallets. There is one of each per currency. EQUITY holds the balances
+// wallets had before the journal existed.
+const (
+	SystemCashIn     = "CASH_IN"
+	SystemCashOut    = "CASH_OUT"
+	SystemEquity     = "EQUITY"
+	SystemFees       = "FEES"
+	SystemInterest   = "INTEREST"
+	SystemSettlement = "SETTLEMENT"
+	SystemSuspense   = "SUSPENSE"
+)
+
+// Posting moves Amount into (positive, credit) or out of (negative, debit)
+// one account.
+type Posting struct {
+	AccountType string          `json:"account_type"`
+	AccountId   string          `json:"account_id"`
+	Currency    string          `json:"currency"`
+	Amount      decimal.Decimal `json:"amount"`
+}
+
+func WalletPosting(walletID, currency string, amount decimal.Decimal) Posting {
+	return Posting{AccountType: AccountWallet, AccountId: walletID, Currency: currency, Amount: amount}
+}
+
+func SystemPosting(account, currency string, amount decimal.Decimal) Posting {
+	return Posting{AccountType: AccountSystem, AccountId: account, Currency: currency, Amount: amount}
+}
+
+// JournalEntry is one balanced business event. Its ID is shared with the
+// wallet ledger entries it produces, or with the transfer for transfers.
+type JournalEntry struct {
+	ID            string    `json:"id"`
+	OperationType string    `json:"operation_type"`
+	Postings      []Posting `json:"postings"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Validate checks that the entry has at least two non-zero postings and that
+// they sum to zero in every currency.
+func (e JournalEntry) Validate() error {
+	if len(e.Postings) < 2 {
+		return fmt.Errorf("%w: %d postings", ErrUnbalancedJournal, len(e.Postings))
+	}
+
+	sums := make(map[string]decimal.Decimal)
+	for _, posting := range e.Postings {
+		if posting.Amount.IsZero() {
+			return fmt.Errorf("%w: zero posting to %s", ErrUnbalancedJournal, posting.AccountId)
+		}
+		sums[posting.Currency] = sums[posting.Currency].Add(posting.Amount)
+	}
+
+	for currency, sum := range sums {
+		if !sum.IsZero() {
+			return fmt.Errorf("%w: %s postings sum to %s", ErrUnbalancedJournal, currency, sum.String())
+		}
+	}
+	return nil
+}
+
+// AccountBalance is the sum of all postings to one account.
+type AccountBalance struct {
+	AccountType string          `json:"account_type"`
+	AccountId   string          `json:"account_id"`
+	Currency    string          `json:"currency"`
+	Balance     decimal.Decimal `json:"balance"`
+}
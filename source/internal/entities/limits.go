@@ -0,0 +1,79 @@
+package entities
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// Names of the velocity limits, reported in LimitExceededError.
+const (
+	LimitPerOperation  = "PER_OPERATION"
+	LimitDailyAmount   = "DAILY_AMOUNT"
+	LimitDailyCount    = "DAILY_COUNT"
+	LimitMonthlyAmount = "MONTHLY_AMOUNT"
+	LimitMonthlyCount  = "MONTHLY_COUNT"
+)
+
+// VelocityLimits caps outgoing money of one wallet, in the wallet currency.
+// Daily limits cover a rolling 24h window, monthly ones the calendar month
+// in UTC. A zero value means no limit.
+type VelocityLimits struct {
+	WalletId      string          `json:"wallet_id"`
+	PerOperation  decimal.Decimal `json:"per_operation"`
+	DailyAmount   decimal.Decimal `json:"daily_amount"`
+	DailyCount    int64           `json:"daily_count"`
+	MonthlyAmount decimal.Decimal `json:"monthly_amount"`
+	MonthlyCount  int64           `json:"monthly_count"`
+}
+
+// Usage is the outgoing amount and number of operations in a window.
+type Usage struct {
+	Amount decimal.Decimal
+	Count  int64
+}
+
+// LimitExceededError names the velocity limit an operation would break.
+// errors.Is matches it against ErrLimitExceeded.
+type LimitExceededError struct {
+	Limit string
+	Max   decimal.Decimal
+	// Attempted is the amount or count the window would reach.
+	Attempted decimal.Decimal
+}
+
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("%s: %s limit is %s, operation would reach %s",
+		ErrLimitExceeded.Error(), e.Limit, e.Max.String(), e.Attempted.String())
+}
+
+func (e *LimitExceededError) Is(target error) bool {
+	return target == ErrLimitExceeded
+}
+
+// Check returns a LimitExceededError for the first limit that amount would
+// break on top of the daily and monthly usage.
+func (l VelocityLimits) Check(amount decimal.Decimal, daily, monthly Usage) error {
+	if l.PerOperation.IsPositive() && amount.GreaterThan(l.PerOperation) {
+		return &LimitExceededError{Limit: LimitPerOperation, Max: l.PerOperation, Attempted: amount}
+	}
+	if l.DailyAmount.IsPositive() && daily.Amount.Add(amount).GreaterThan(l.DailyAmount) {
+		return &LimitExceededError{Limit: LimitDailyAmount, Max: l.DailyAmount, Attempted: daily.Amount.Add(amount)}
+	}
+	if l.DailyCount > 0 && daily.Count+1 > l.DailyCount {
+		return &LimitExceededError{Limit: LimitDailyCount, Max: decimal.NewFromInt(l.DailyCount), Attempted: decimal.NewFromInt(daily.Count + 1)}
+	}
+	if l.MonthlyAmount.IsPositive() && monthly.Amount.Add(amount).GreaterThan(l.MonthlyAmount) {
+		return &LimitExceededError{Limit: LimitMonthlyAmount, Max: l.MonthlyAmount, Attempted: monthly.Amount.Add(amount)}
+	}
+	if l.MonthlyCount > 0 && monthly.Count+1 > l.MonthlyCount {
+		return &LimitExceededError{Limit: LimitMonthlyCount, Max: decimal.NewFromInt(l.MonthlyCount), Attempted: decimal.NewFromInt(monthly.Count + 1)}
+	}
+	return nil
+}
+
+// Unlimited reports whether no limit is set.
+func (l VelocityLimits) Unlimited() bool {
+	return !l.PerOperation.IsPositive() && !l.DailyAmount.IsPositive() && l.DailyCount <= 0 &&
+		!l.MonthlyAmount.IsPositive() && l.MonthlyCount <= 0
+}
@@ -0,0 +1,41 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// BalanceCheck compares the balance stored on a wallet with the balance
+// recomputed from its journal postings.
+type BalanceCheck struct {
+	WalletId      string          `json:"wallet_id"`
+	Currency      string          `json:"currency"`
+	Balance       decimal.Decimal `json:"balance"`
+	LedgerBalance decimal.Decimal `json:"ledger_balance"`
+}
+
+// Drift is how much the stored balance exceeds the ledger.
+func (c BalanceCheck) Drift() decimal.Decimal {
+	return c.Balance.Sub(c.LedgerBalance)
+}
+
+// BalanceDrift is a wallet whose stored balance differs from its ledger.
+type BalanceDrift struct {
+	BalanceCheck
+	Drift decimal.Decimal `json:"drift"`
+}
+
+// ReconciliationReport is the result of one reconciliation run.
+type ReconciliationReport struct {
+	StartedAt      time.Time                  `json:"started_at"`
+	FinishedAt     time.Time                  `json:"finished_at"`
+	WalletsChecked int                        `json:"wallets_checked"`
+	Drifts         []BalanceDrift             `json:"drifts"`
+	DriftTotals    map[string]decimal.Decimal `json:"drift_totals"`
+}
+
+// Consistent reports whether every checked wallet matched its ledger.
+func (r ReconciliationReport) Consistent() bool {
+	return len(r.Drifts) == 0
+}
@@ -0,0 +1,105 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	ScheduleActive    = "ACTIVE"
+	SchedulePaused    = "PAUSED"
+	ScheduleCancelled = "CANCELLED"
+	ScheduleCompleted = "COMPLETED"
+)
+
+const (
+	FrequencyOnce    = "ONCE"
+	FrequencyDaily   = "DAILY"
+	FrequencyWeekly  = "WEEKLY"
+	FrequencyMonthly = "MONTHLY"
+)
+
+const (
+	RunSucceeded = "SUCCEEDED"
+	RunFailed    = "FAILED"
+)
+
+// Schedule runs a DEPOSIT or WITHDRAW at StartAt and then, unless it runs
+// ONCE, every day, week or month after it until EndAt. Monthly runs keep the
+// day of month of StartAt and fall back to the last day of shorter months.
+// Occurrence is the index of the run at NextRunAt.
+type Schedule struct {
+	ID            string          `json:"id"`
+	WalletId      string          `json:"wallet_id"`
+	OperationType string          `json:"operation_type"`
+	Currency      string          `json:"currency"`
+	Amount        decimal.Decimal `json:"amount"`
+	Frequency     string          `json:"frequency"`
+	StartAt       time.Time       `json:"start_at"`
+	EndAt         *time.Time      `json:"end_at,omitempty"`
+	Occurrence    int             `json:"occurrence"`
+	NextRunAt     time.Time       `json:"next_run_at"`
+	Status        string          `json:"status"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+func NewSchedule() Schedule {
+	return Schedule{
+		ID:     uuid.NewString(),
+		Status: ScheduleActive,
+	}
+}
+
+// OccurrenceAt returns the time of the n-th run, counting from zero.
+func (s Schedule) OccurrenceAt(n int) time.Time {
+	switch s.Frequency {
+	case FrequencyDaily:
+		return s.StartAt.AddDate(0, 0, n)
+	case FrequencyWeekly:
+		return s.StartAt.AddDate(0, 0, 7*n)
+	case FrequencyMonthly:
+		year, month, day := s.StartAt.Date()
+		first := time.Date(year, month+time.Month(n), 1,
+			s.StartAt.Hour(), s.StartAt.Minute(), s.StartAt.Second(), s.StartAt.Nanosecond(), s.StartAt.Location())
+		lastDay := first.AddDate(0, 1, -1).Day()
+		return first.AddDate(0, 0, min(day, lastDay)-1)
+	}
+	return s.StartAt
+}
+
+// Advance moves NextRunAt to the first occurrence after now. Occurrences
+// missed while the schedule was paused or the workers were down are
+// skipped. A schedule without further occurrences becomes COMPLETED.
+func (s *Schedule) Advance(now time.Time) {
+	if s.Frequency == FrequencyOnce {
+		s.Status = ScheduleCompleted
+		return
+	}
+
+	for {
+		s.Occurrence++
+		s.NextRunAt = s.OccurrenceAt(s.Occurrence)
+		if s.NextRunAt.After(now) {
+			break
+		}
+	}
+
+	if s.EndAt != nil && s.NextRunAt.After(*s.EndAt) {
+		s.Status = ScheduleCompleted
+	}
+}
+
+// ScheduleRun records one execution of a schedule.
+type ScheduleRun struct {
+	ID            string    `json:"id"`
+	ScheduleId    string    `json:"schedule_id"`
+	Occurrence    int       `json:"occurrence"`
+	ScheduledAt   time.Time `json:"scheduled_at"`
+	Status        string    `json:"status"`
+	TransactionId string    `json:"transaction_id,omitempty"`
+	Error         string    `json:"error,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -0,0 +1,24 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// BalanceSnapshot is the balance of a wallet at the end of Day, a UTC
+// midnight. Snapshots are only stored for days with postings on the wallet.
+type BalanceSnapshot struct {
+	WalletId string
+	Day      time.Time
+	Balance  decimal.Decimal
+}
+
+// HistoricalBalance is the balance a wallet had at AsOf, rebuilt from the
+// journal.
+type HistoricalBalance struct {
+	WalletId string
+	Currency string
+	Balance  decimal.Decimal
+	AsOf     time.Time
+}
@@ -0,0 +1,38 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Statement covers the operations of a wallet created in [From, To).
+// ClosingBalance and Totals are only known once every line has been read.
+type Statement struct {
+	WalletId       string
+	Currency       string
+	From           time.Time
+	To             time.Time
+	OpeningBalance decimal.Decimal
+	ClosingBalance decimal.Decimal
+	Totals         map[string]StatementTotal
+}
+
+// StatementFeeTotal is the Totals key that sums the fees charged in the
+// period.
+const StatementFeeTotal = "FEE"
+
+// StatementTotal sums the operations of one type. Amount is unsigned, like
+// the amounts in the ledger.
+type StatementTotal struct {
+	Count  int64
+	Amount decimal.Decimal
+}
+
+// StatementLine is one operation with the balance right after it. Change is
+// the signed effect on the wallet balance, fee included.
+type StatementLine struct {
+	Transaction    Transaction
+	Change         decimal.Decimal
+	RunningBalance decimal.Decimal
+}
@@ -1,14 +1,52 @@
 package entities
 
-import "github.com/shopspring/decimal"
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
 
 type Transaction struct {
+	ID            string          `json:"id"`
 	WalletId      string          `json:"wallet_id"`
 	OperationType string          `json:"operation_type"`
+	Currency      string          `json:"currency,omitempty"`
 	Amount        decimal.Decimal `json:"amount"`
+	// Fee is charged on top of Amount and is already included in Balance. A
+	// reversal carries the refunded part of the original fee as a negative Fee.
+	Fee           decimal.Decimal `json:"fee,omitempty"`
+	BalanceBefore decimal.Decimal `json:"balance_before,omitempty"`
 	Balance       decimal.Decimal `json:"balance,omitempty"`
+	ReferenceId   string          `json:"reference_id,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
 }
 
 func NewTransaction() Transaction {
-	return Transaction{}
+	return Transaction{
+		ID: uuid.NewString(),
+	}
+}
+
+// TransactionCursor points at the last transaction of a history page.
+// The next page starts right after it in newest-first order.
+type TransactionCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+type TransactionFilter struct {
+	WalletId      string
+	OperationType string
+	From          *time.Time
+	To            *time.Time
+	MinAmount     *decimal.Decimal
+	MaxAmount     *decimal.Decimal
+	Cursor        *TransactionCursor
+	Limit         int
+}
+
+type TransactionPage struct {
+	Transactions []Transaction
+	NextCursor   *TransactionCursor
 }
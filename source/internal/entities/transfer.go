@@ -0,0 +1,28 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Transfer moves Amount from one wallet to another. It is recorded in the
+// ledger as a TRANSFER_OUT and a TRANSFER_IN entry that both reference the
+// transfer ID.
+type Transfer struct {
+	ID           string          `json:"id"`
+	FromWalletId string          `json:"from_wallet_id"`
+	ToWalletId   string          `json:"to_wallet_id"`
+	Currency     string          `json:"currency,omitempty"`
+	Amount       decimal.Decimal `json:"amount"`
+	FromBalance  decimal.Decimal `json:"from_balance"`
+	ToBalance    decimal.Decimal `json:"to_balance"`
+	CreatedAt    time.Time       `json:"created_at"`
+}
+
+func NewTransfer() Transfer {
+	return Transfer{
+		ID: uuid.NewString(),
+	}
+}
@@ -1,18 +1,85 @@
 package entities
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 )
 
+const (
+	WalletActive = "ACTIVE"
+	WalletFrozen = "FROZEN"
+	WalletClosed = "CLOSED"
+)
+
+// A frozen wallet either still accepts incoming money (FreezeDebit) or
+// rejects every balance change (FreezeAll).
+const (
+	FreezeDebit = "DEBIT"
+	FreezeAll   = "ALL"
+)
+
+// Wallet.Balance is the ledger balance. Reserved is the part of it held by
+// active holds, so only Available can be spent. OverdraftLimit lets the
+// balance go down to -OverdraftLimit.
 type Wallet struct {
-	ID      string          `json:"id"`
-	Balance decimal.Decimal `json:"balance"`
+	ID              string          `json:"id"`
+	OwnerId         string          `json:"owner_id,omitempty"`
+	Currency        string          `json:"currency"`
+	Balance         decimal.Decimal `json:"balance"`
+	Reserved        decimal.Decimal `json:"reserved"`
+	OverdraftLimit  decimal.Decimal `json:"overdraft_limit"`
+	Status          string          `json:"status"`
+	FreezeMode      string          `json:"freeze_mode,omitempty"`
+	StatusReason    string          `json:"status_reason,omitempty"`
+	StatusChangedAt *time.Time      `json:"status_changed_at,omitempty"`
 }
 
 func NewWallet() Wallet {
 	return Wallet{
-		ID:      uuid.NewString(),
-		Balance: decimal.Zero,
+		ID:             uuid.NewString(),
+		Currency:       DefaultCurrency,
+		Balance:        decimal.Zero,
+		Reserved:       decimal.Zero,
+		OverdraftLimit: decimal.Zero,
+		Status:         WalletActive,
+	}
+}
+
+func (w Wallet) Available() decimal.Decimal {
+	return w.Balance.Sub(w.Reserved)
+}
+
+// Spendable is Available plus the unused overdraft.
+func (w Wallet) Spendable() decimal.Decimal {
+	return w.Available().Add(w.OverdraftLimit)
+}
+
+// RemainingCredit is the part of the overdraft limit that can still be used.
+func (w Wallet) RemainingCredit() decimal.Decimal {
+	return decimal.Max(decimal.Zero, decimal.Min(w.OverdraftLimit, w.Spendable()))
+}
+
+// CheckCredit reports whether money may be added to the wallet.
+func (w Wallet) CheckCredit() error {
+	switch {
+	case w.Status == WalletClosed:
+		return ErrWalletClosed
+	case w.Status == WalletFrozen && w.FreezeMode != FreezeDebit:
+		return ErrWalletFrozen
+	}
+	return nil
+}
+
+// CheckDebit reports whether money may be taken from or reserved on the
+// wallet.
+func (w Wallet) CheckDebit() error {
+	switch w.Status {
+	case WalletClosed:
+		return ErrWalletClosed
+	case WalletFrozen:
+		return ErrWalletFrozen
 	}
+	return nil
 }
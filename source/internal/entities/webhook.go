@@ -0,0 +1,79 @@
+package entities
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DeliveryPending   = "PENDING"
+	DeliverySucceeded = "SUCCEEDED"
+	// DeliveryDead is the dead-letter state: the delivery failed too many
+	// times and is only sent again when replayed.
+	DeliveryDead = "DEAD"
+)
+
+// Webhook sends the events of one wallet, or of every wallet when WalletId
+// is empty, to URL. Secret signs the deliveries.
+type Webhook struct {
+	ID        string    `json:"id"`
+	WalletId  string    `json:"wallet_id,omitempty"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func NewWebhook() Webhook {
+	return Webhook{
+		ID:     uuid.NewString(),
+		Secret: newWebhookSecret(),
+	}
+}
+
+func newWebhookSecret() string {
+	secret := make([]byte, 32)
+	_, _ = rand.Read(secret)
+	return "whsec_" + hex.EncodeToString(secret)
+}
+
+// WebhookDelivery is one event sent to one webhook. Payload is the request
+// body, kept as sent so that replays carry the same bytes.
+type WebhookDelivery struct {
+	ID             string     `json:"id"`
+	WebhookId      string     `json:"webhook_id"`
+	EventId        string     `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	WalletId       string     `json:"wallet_id"`
+	Payload        []byte     `json:"-"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at"`
+	LastStatusCode int        `json:"last_status_code,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+type WebhookDeliveryFilter struct {
+	WebhookId string
+	Status    string
+	Limit     int
+}
+
+// SignWebhook returns the hex HMAC-SHA256 of "<timestamp>.<body>" keyed
+// with the webhook secret. Signing the timestamp lets receivers reject
+// old deliveries replayed by a third party.
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,85 @@
+package publisher
+
+import (
+	"TestProject/source/config"
+	"TestProject/source/internal/application"
+	"TestProject/source/internal/entities"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"go.uber.org/fx"
+)
+
+const moduleName = "publisher"
+
+var Module = fx.Module(
+	moduleName,
+	fx.Provide(
+		New,
+	),
+)
+
+// New returns the publisher selected by OUTBOX_PUBLISHER. Other
+// publishers, e.g. for a message broker, plug in here.
+func New(lc fx.Lifecycle, conf config.OutboxConfig) (application.EventPublisher, error) {
+	switch conf.Publisher {
+	case "none":
+		return NopPublisher{}, nil
+	case "stdout":
+		// Shares stdout with the JSON logs; only for local runs.
+		return NewWriterPublisher(os.Stdout), nil
+	case "file":
+		file, err := os.OpenFile(conf.File, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("error opening outbox file: %w", err)
+		}
+		lc.Append(fx.Hook{
+			OnStop: func(ctx context.Context) error {
+				return file.Close()
+			},
+		})
+		return NewWriterPublisher(file), nil
+	default:
+		return nil, fmt.Errorf("unknown outbox publisher %q", conf.Publisher)
+	}
+}
+
+// NopPublisher drops events. It is the default, so that events do not end
+// up in the log stream; webhooks and wallet streams work without it.
+type NopPublisher struct{}
+
+func (NopPublisher) Publish(ctx context.Context, event entities.WalletEvent) error {
+	return nil
+}
+
+// WriterPublisher writes each event as a line of JSON. It is meant for
+// local runs, where events are read from stdout or a file.
+type WriterPublisher struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+func NewWriterPublisher(out io.Writer) *WriterPublisher {
+	return &WriterPublisher{out: out}
+}
+
+func (p *WriterPublisher) Publish(ctx context.Context, event entities.WalletEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error encoding event: %w", err)
+	}
+	line = append(line, '\n')
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_, err = p.out.Write(line)
+	if err != nil {
+		return fmt.Errorf("error writing event: %w", err)
+	}
+	return nil
+}
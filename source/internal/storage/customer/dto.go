@@ -0,0 +1,54 @@
+package customer
+
+import (
+	"TestProject/source/internal/entities"
+	"TestProject/source/internal/storage/wallet"
+	"time"
+)
+
+// Customer restricts deletion while any wallet still references it.
+type Customer struct {
+	ID        string          `gorm:"primaryKey;type:uuid"`
+	Name      string          `gorm:"type:varchar(255);not null"`
+	Email     *string         `gorm:"type:varchar(255);uniqueIndex"`
+	Phone     *string         `gorm:"type:varchar(32)"`
+	Wallets   []wallet.Wallet `gorm:"foreignKey:OwnerID;constraint:OnDelete:RESTRICT"`
+	CreatedAt time.Time       `gorm:"not null"`
+	UpdatedAt time.Time       `gorm:"not null"`
+}
+
+func FromEntity(entity entities.Customer) (Customer, error) {
+	return Customer{
+		ID:        entity.ID,
+		Name:      entity.Name,
+		Email:     nullableString(entity.Email),
+		Phone:     nullableString(entity.Phone),
+		CreatedAt: entity.CreatedAt,
+		UpdatedAt: entity.UpdatedAt,
+	}, nil
+}
+
+func ToEntity(dto Customer) (entities.Customer, error) {
+	return entities.Customer{
+		ID:        dto.ID,
+		Name:      dto.Name,
+		Email:     stringValue(dto.Email),
+		Phone:     stringValue(dto.Phone),
+		CreatedAt: dto.CreatedAt,
+		UpdatedAt: dto.UpdatedAt,
+	}, nil
+}
+
+func nullableString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
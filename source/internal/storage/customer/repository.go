@@ -0,0 +1,139 @@
+package customer
+
+import (
+	"TestProject/source/internal/entities"
+	"TestProject/source/internal/storage/transactor"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+)
+
+type Repo struct {
+	db *gorm.DB
+}
+
+func NewRepo(db *gorm.DB) *Repo {
+	return &Repo{db: db}
+}
+
+func (r *Repo) Create(ctx context.Context, customer entities.Customer) (entities.Customer, error) {
+	dto, err := FromEntity(customer)
+	if err != nil {
+		return entities.Customer{}, fmt.Errorf("customer from entity error: %w", err)
+	}
+
+	if err := transactor.DB(ctx, r.db).Create(&dto).Error; err != nil {
+		return entities.Customer{}, fmt.Errorf("failed to create customer: %w", constraint(err))
+	}
+
+	entity, err := ToEntity(dto)
+	if err != nil {
+		return entities.Customer{}, fmt.Errorf("customer to entity error: %w", err)
+	}
+	return entity, nil
+}
+
+func (r *Repo) GetByID(ctx context.Context, customerID string) (entities.Customer, error) {
+	var dto Customer
+
+	err := transactor.DB(ctx, r.db).Where("id = ?", customerID).First(&dto).Error
+	if err != nil {
+		return entities.Customer{}, fmt.Errorf("db.First error: %w", notFound(err))
+	}
+
+	entity, err := ToEntity(dto)
+	if err != nil {
+		return entities.Customer{}, fmt.Errorf("customer.ToEntity error: %w", err)
+	}
+
+	return entity, nil
+}
+
+// List returns up to limit customers ordered by ID, starting after afterID.
+func (r *Repo) List(ctx context.Context, afterID string, limit int) ([]entities.Customer, error) {
+	var dtos []Customer
+
+	query := transactor.DB(ctx, r.db).Order("id").Limit(limit)
+	if afterID != "" {
+		query = query.Where("id > ?", afterID)
+	}
+	err := query.Find(&dtos).Error
+	if err != nil {
+		return nil, fmt.Errorf("db.Find error: %w", err)
+	}
+
+	result := make([]entities.Customer, 0, len(dtos))
+	for _, dto := range dtos {
+		entity, err := ToEntity(dto)
+		if err != nil {
+			return nil, fmt.Errorf("customer.ToEntity error: %w", err)
+		}
+		result = append(result, entity)
+	}
+
+	return result, nil
+}
+
+func (r *Repo) Update(ctx context.Context, customer entities.Customer) (entities.Customer, error) {
+	dto, err := FromEntity(customer)
+	if err != nil {
+		return entities.Customer{}, fmt.Errorf("customer from entity error: %w", err)
+	}
+
+	result := transactor.DB(ctx, r.db).
+		Model(&Customer{}).
+		Where("id = ?", dto.ID).
+		Select("name", "email", "phone", "updated_at").
+		Updates(&dto)
+	if result.Error != nil {
+		return entities.Customer{}, fmt.Errorf("db.Updates error: %w", constraint(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return entities.Customer{}, entities.ErrCustomerNotFound
+	}
+
+	return r.GetByID(ctx, customer.ID)
+}
+
+// Delete removes a customer that owns no wallets.
+func (r *Repo) Delete(ctx context.Context, customerID string) error {
+	result := transactor.DB(ctx, r.db).Where("id = ?", customerID).Delete(&Customer{})
+	if result.Error != nil {
+		return fmt.Errorf("db.Delete error: %w", constraint(result.Error))
+	}
+	if result.RowsAffected == 0 {
+		return entities.ErrCustomerNotFound
+	}
+	return nil
+}
+
+func notFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return entities.ErrCustomerNotFound
+	}
+	return err
+}
+
+// constraint maps a duplicate email to ErrCustomerExists and a wallet still
+// referencing the customer to ErrCustomerHasWallets.
+func constraint(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+	switch pgErr.Code {
+	case uniqueViolation:
+		return fmt.Errorf("%w: %s", entities.ErrCustomerExists, pgErr.Message)
+	case foreignKeyViolation:
+		return fmt.Errorf("%w: %s", entities.ErrCustomerHasWallets, pgErr.Message)
+	}
+	return err
+}
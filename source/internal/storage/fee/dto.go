@@ -0,0 +1,84 @@
+package fee
+
+import (
+	"TestProject/source/internal/entities"
+	"encoding/json"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// defaultScope is the scope of rules that apply to every wallet.
+const defaultScope = "DEFAULT"
+
+// FeeRule is keyed by scope, the wallet ID or DEFAULT, so that wallet rules
+// and default rules share one primary key.
+type FeeRule struct {
+	Scope         string          `gorm:"primaryKey;type:varchar(64)"`
+	OperationType string          `gorm:"primaryKey;type:varchar(32)"`
+	Currency      string          `gorm:"primaryKey;type:char(3)"`
+	Type          string          `gorm:"type:varchar(16);not null"`
+	Flat          decimal.Decimal `gorm:"type:decimal(16,3);default:0;not null"`
+	Percent       decimal.Decimal `gorm:"type:decimal(7,4);default:0;not null"`
+	Tiers         string          `gorm:"type:jsonb;default:'[]';not null"`
+	MinFee        decimal.Decimal `gorm:"type:decimal(16,3);default:0;not null"`
+	MaxFee        decimal.Decimal `gorm:"type:decimal(16,3);default:0;not null"`
+	UpdatedAt     time.Time       `gorm:"not null"`
+}
+
+func scope(walletID string) string {
+	if walletID == "" {
+		return defaultScope
+	}
+	return walletID
+}
+
+func FromEntity(entity entities.FeeRule) (FeeRule, error) {
+	tiers := entity.Tiers
+	if tiers == nil {
+		tiers = []entities.FeeTier{}
+	}
+	data, err := json.Marshal(tiers)
+	if err != nil {
+		return FeeRule{}, err
+	}
+
+	return FeeRule{
+		Scope:         scope(entity.WalletId),
+		OperationType: entity.OperationType,
+		Currency:      entity.Currency,
+		Type:          entity.Type,
+		Flat:          entity.Flat,
+		Percent:       entity.Percent,
+		Tiers:         string(data),
+		MinFee:        entity.Min,
+		MaxFee:        entity.Max,
+		UpdatedAt:     time.Now().UTC(),
+	}, nil
+}
+
+func ToEntity(dto FeeRule) (entities.FeeRule, error) {
+	var tiers []entities.FeeTier
+	err := json.Unmarshal([]byte(dto.Tiers), &tiers)
+	if err != nil {
+		return entities.FeeRule{}, err
+	}
+
+	walletID := dto.Scope
+	if walletID == defaultScope {
+		walletID = ""
+	}
+
+	return entities.FeeRule{
+		WalletId:      walletID,
+		OperationType: dto.OperationType,
+		Currency:      dto.Currency,
+		Type:          dto.Type,
+		Flat:          dto.Flat,
+		Percent:       dto.Percent,
+		Tiers:         tiers,
+		Min:           dto.MinFee,
+		Max:           dto.MaxFee,
+		UpdatedAt:     dto.UpdatedAt,
+	}, nil
+}
@@ -0,0 +1,111 @@
+package fee
+
+import (
+	"TestProject/source/internal/entities"
+	"TestProject/source/internal/storage/transactor"
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repo struct {
+	db *gorm.DB
+}
+
+func NewRepo(db *gorm.DB) *Repo {
+	return &Repo{db: db}
+}
+
+// Effective returns the wallet's own rule for the operation, or the default
+// rule for the currency, or ErrFeeRuleNotFound when neither exists.
+func (r *Repo) Effective(ctx context.Context, walletID, operationType, currency string) (entities.FeeRule, error) {
+	var dtos []FeeRule
+
+	err := transactor.DB(ctx, r.db).
+		Where("scope IN ? AND operation_type = ? AND currency = ?", []string{walletID, defaultScope}, operationType, currency).
+		Find(&dtos).Error
+	if err != nil {
+		return entities.FeeRule{}, fmt.Errorf("db.Find error: %w", err)
+	}
+	if len(dtos) == 0 {
+		return entities.FeeRule{}, entities.ErrFeeRuleNotFound
+	}
+
+	dto := dtos[0]
+	for _, candidate := range dtos {
+		if candidate.Scope == walletID {
+			dto = candidate
+		}
+	}
+
+	entity, err := ToEntity(dto)
+	if err != nil {
+		return entities.FeeRule{}, fmt.Errorf("fee.ToEntity error: %w", err)
+	}
+
+	return entity, nil
+}
+
+// List returns the rules of one wallet, or the default rules when walletID
+// is empty.
+func (r *Repo) List(ctx context.Context, walletID string) ([]entities.FeeRule, error) {
+	var dtos []FeeRule
+
+	err := transactor.DB(ctx, r.db).
+		Where("scope = ?", scope(walletID)).
+		Order("operation_type, currency").
+		Find(&dtos).Error
+	if err != nil {
+		return nil, fmt.Errorf("db.Find error: %w", err)
+	}
+
+	result := make([]entities.FeeRule, 0, len(dtos))
+	for _, dto := range dtos {
+		entity, err := ToEntity(dto)
+		if err != nil {
+			return nil, fmt.Errorf("fee.ToEntity error: %w", err)
+		}
+		result = append(result, entity)
+	}
+
+	return result, nil
+}
+
+// Save creates or replaces the rule.
+func (r *Repo) Save(ctx context.Context, rule entities.FeeRule) (entities.FeeRule, error) {
+	dto, err := FromEntity(rule)
+	if err != nil {
+		return entities.FeeRule{}, fmt.Errorf("fee rule from entity error: %w", err)
+	}
+
+	err = transactor.DB(ctx, r.db).
+		Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(&dto).Error
+	if err != nil {
+		return entities.FeeRule{}, fmt.Errorf("db.Create error: %w", err)
+	}
+
+	entity, err := ToEntity(dto)
+	if err != nil {
+		return entities.FeeRule{}, fmt.Errorf("fee.ToEntity error: %w", err)
+	}
+
+	return entity, nil
+}
+
+// Delete removes the rule. A wallet without its own rule falls back to the
+// default one.
+func (r *Repo) Delete(ctx context.Context, walletID, operationType, currency string) error {
+	result := transactor.DB(ctx, r.db).
+		Where("scope = ? AND operation_type = ? AND currency = ?", scope(walletID), operationType, currency).
+		Delete(&FeeRule{})
+	if result.Error != nil {
+		return fmt.Errorf("db.Delete error: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return entities.ErrFeeRuleNotFound
+	}
+	return nil
+}
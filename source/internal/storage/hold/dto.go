@@ -0,0 +1,61 @@
+package hold
+
+import (
+	"TestProject/source/internal/entities"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type Hold struct {
+	ID             string          `gorm:"primaryKey;type:uuid"`
+	WalletID       string          `gorm:"type:uuid;not null;index"`
+	Currency       string          `gorm:"type:char(3);not null"`
+	Amount         decimal.Decimal `gorm:"type:decimal(16,3);not null"`
+	CapturedAmount decimal.Decimal `gorm:"type:decimal(16,3);default:0;not null"`
+	Status         string          `gorm:"type:varchar(16);not null;index:idx_holds_status_expires,priority:1"`
+	TransactionID  *string         `gorm:"type:uuid"`
+	ExpiresAt      time.Time       `gorm:"not null;index:idx_holds_status_expires,priority:2"`
+	CreatedAt      time.Time       `gorm:"not null"`
+	UpdatedAt      time.Time       `gorm:"not null"`
+}
+
+func FromEntity(entity entities.Hold) (Hold, error) {
+	var transactionID *string
+	if entity.TransactionId != "" {
+		transactionID = &entity.TransactionId
+	}
+
+	return Hold{
+		ID:             entity.ID,
+		WalletID:       entity.WalletId,
+		Currency:       entity.Currency,
+		Amount:         entity.Amount,
+		CapturedAmount: entity.CapturedAmount,
+		Status:         entity.Status,
+		TransactionID:  transactionID,
+		ExpiresAt:      entity.ExpiresAt,
+		CreatedAt:      entity.CreatedAt,
+		UpdatedAt:      entity.UpdatedAt,
+	}, nil
+}
+
+func ToEntity(dto Hold) (entities.Hold, error) {
+	var transactionID string
+	if dto.TransactionID != nil {
+		transactionID = *dto.TransactionID
+	}
+
+	return entities.Hold{
+		ID:             dto.ID,
+		WalletId:       dto.WalletID,
+		Currency:       dto.Currency,
+		Amount:         dto.Amount,
+		CapturedAmount: dto.CapturedAmount,
+		Status:         dto.Status,
+		TransactionId:  transactionID,
+		ExpiresAt:      dto.ExpiresAt,
+		CreatedAt:      dto.CreatedAt,
+		UpdatedAt:      dto.UpdatedAt,
+	}, nil
+}
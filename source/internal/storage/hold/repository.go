@@ -0,0 +1,121 @@
+package hold
+
+import (
+	"TestProject/source/internal/entities"
+	"TestProject/source/internal/storage/transactor"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Repo struct {
+	db *gorm.DB
+}
+
+func NewRepo(db *gorm.DB) *Repo {
+	return &Repo{db: db}
+}
+
+func (r *Repo) Create(ctx context.Context, hold entities.Hold) (entities.Hold, error) {
+	dto, err := FromEntity(hold)
+	if err != nil {
+		return entities.Hold{}, fmt.Errorf("hold from entity error: %w", err)
+	}
+
+	if err := transactor.DB(ctx, r.db).Create(&dto).Error; err != nil {
+		return entities.Hold{}, fmt.Errorf("failed to create hold: %w", err)
+	}
+
+	entity, err := ToEntity(dto)
+	if err != nil {
+		return entities.Hold{}, fmt.Errorf("hold to entity error: %w", err)
+	}
+	return entity, nil
+}
+
+func (r *Repo) GetByID(ctx context.Context, holdID string) (entities.Hold, error) {
+	var dto Hold
+
+	err := transactor.DB(ctx, r.db).Where("id = ?", holdID).First(&dto).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = entities.ErrHoldNotFound
+		}
+		return entities.Hold{}, fmt.Errorf("db.First error: %w", err)
+	}
+
+	entity, err := ToEntity(dto)
+	if err != nil {
+		return entities.Hold{}, fmt.Errorf("hold.ToEntity error: %w", err)
+	}
+
+	return entity, nil
+}
+
+func (r *Repo) Update(ctx context.Context, hold entities.Hold) (entities.Hold, error) {
+	dto, err := FromEntity(hold)
+	if err != nil {
+		return entities.Hold{}, fmt.Errorf("hold from entity error: %w", err)
+	}
+
+	err = transactor.DB(ctx, r.db).Save(&dto).Error
+	if err != nil {
+		return entities.Hold{}, fmt.Errorf("db.Save error: %w", err)
+	}
+
+	entity, err := ToEntity(dto)
+	if err != nil {
+		return entities.Hold{}, fmt.Errorf("hold to entity error: %w", err)
+	}
+
+	return entity, nil
+}
+
+// Usage sums and counts the wallet's active holds created at or after since,
+// other than exceptID.
+func (r *Repo) Usage(ctx context.Context, walletID string, since time.Time, exceptID string) (entities.Usage, error) {
+	var usage entities.Usage
+
+	query := transactor.DB(ctx, r.db).
+		Model(&Hold{}).
+		Select("COALESCE(SUM(amount), 0), COUNT(*)").
+		Where("wallet_id = ? AND status = ? AND created_at >= ?", walletID, entities.HoldActive, since)
+	if exceptID != "" {
+		query = query.Where("id <> ?", exceptID)
+	}
+
+	err := query.Row().Scan(&usage.Amount, &usage.Count)
+	if err != nil {
+		return entities.Usage{}, fmt.Errorf("row.Scan error: %w", err)
+	}
+
+	return usage, nil
+}
+
+// ListExpired returns up to limit active holds whose TTL ran out before now.
+func (r *Repo) ListExpired(ctx context.Context, now time.Time, limit int) ([]entities.Hold, error) {
+	var dtos []Hold
+
+	err := transactor.DB(ctx, r.db).
+		Where("status = ? AND expires_at <= ?", entities.HoldActive, now).
+		Order("expires_at").
+		Limit(limit).
+		Find(&dtos).Error
+	if err != nil {
+		return nil, fmt.Errorf("db.Find error: %w", err)
+	}
+
+	result := make([]entities.Hold, 0, len(dtos))
+	for _, dto := range dtos {
+		entity, err := ToEntity(dto)
+		if err != nil {
+			return nil, fmt.Errorf("hold.ToEntity error: %w", err)
+		}
+		result = append(result, entity)
+	}
+
+	return result, nil
+}
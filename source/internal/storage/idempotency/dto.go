@@ -0,0 +1,37 @@
+package idempotency
+
+import (
+	"TestProject/source/internal/entities"
+	"time"
+)
+
+type IdempotencyKey struct {
+	Key         string    `gorm:"primaryKey;type:varchar(255)"`
+	RequestHash string    `gorm:"type:char(64);not null"`
+	StatusCode  int       `gorm:"not null;default:0"`
+	Response    []byte    `gorm:"type:bytea"`
+	CreatedAt   time.Time `gorm:"not null"`
+	ExpiresAt   time.Time `gorm:"not null;index"`
+}
+
+func FromEntity(entity entities.IdempotencyRecord) (IdempotencyKey, error) {
+	return IdempotencyKey{
+		Key:         entity.Key,
+		RequestHash: entity.RequestHash,
+		StatusCode:  entity.StatusCode,
+		Response:    entity.Response,
+		CreatedAt:   entity.CreatedAt,
+		ExpiresAt:   entity.ExpiresAt,
+	}, nil
+}
+
+func ToEntity(dto IdempotencyKey) (entities.IdempotencyRecord, error) {
+	return entities.IdempotencyRecord{
+		Key:         dto.Key,
+		RequestHash: dto.RequestHash,
+		StatusCode:  dto.StatusCode,
+		Response:    dto.Response,
+		CreatedAt:   dto.CreatedAt,
+		ExpiresAt:   dto.ExpiresAt,
+	}, nil
+}
@@ -0,0 +1,92 @@
+package idempotency
+
+import (
+	"TestProject/source/internal/entities"
+	"TestProject/source/internal/storage/transactor"
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repo struct {
+	db *gorm.DB
+}
+
+func NewRepo(db *gorm.DB) *Repo {
+	return &Repo{db: db}
+}
+
+// Acquire inserts the key unless a live record already holds it, then
+// returns the stored record locked until the end of the transaction.
+// An expired record is overwritten as if the key had never been used.
+// A concurrent request with the same key blocks on the insert until the
+// first one commits or rolls back.
+func (r *Repo) Acquire(ctx context.Context, record entities.IdempotencyRecord) (entities.IdempotencyRecord, error) {
+	dto, err := FromEntity(record)
+	if err != nil {
+		return entities.IdempotencyRecord{}, fmt.Errorf("idempotency key from entity error: %w", err)
+	}
+
+	db := transactor.DB(ctx, r.db)
+
+	err = db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "key"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"request_hash": dto.RequestHash,
+			"status_code":  0,
+			"response":     nil,
+			"created_at":   dto.CreatedAt,
+			"expires_at":   dto.ExpiresAt,
+		}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "idempotency_keys.expires_at <= ?", Vars: []interface{}{dto.CreatedAt}},
+		}},
+	}).Create(&dto).Error
+	if err != nil {
+		return entities.IdempotencyRecord{}, fmt.Errorf("db.Create error: %w", err)
+	}
+
+	var stored IdempotencyKey
+	err = db.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("key = ?", record.Key).
+		First(&stored).Error
+	if err != nil {
+		return entities.IdempotencyRecord{}, fmt.Errorf("db.First error: %w", err)
+	}
+
+	entity, err := ToEntity(stored)
+	if err != nil {
+		return entities.IdempotencyRecord{}, fmt.Errorf("idempotency.ToEntity error: %w", err)
+	}
+
+	return entity, nil
+}
+
+// Complete stores the response produced for an acquired key.
+func (r *Repo) Complete(ctx context.Context, record entities.IdempotencyRecord) error {
+	err := transactor.DB(ctx, r.db).
+		Model(&IdempotencyKey{}).
+		Where("key = ?", record.Key).
+		Updates(map[string]interface{}{
+			"status_code": record.StatusCode,
+			"response":    record.Response,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("db.Updates error: %w", err)
+	}
+	return nil
+}
+
+func (r *Repo) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result := transactor.DB(ctx, r.db).
+		Where("expires_at <= ?", now).
+		Delete(&IdempotencyKey{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("db.Delete error: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,117 @@
+package interest
+
+import (
+	"TestProject/source/internal/entities"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type InterestPlan struct {
+	WalletID        string          `gorm:"primaryKey;type:uuid"`
+	Currency        string          `gorm:"type:char(3);not null"`
+	AnnualRate      decimal.Decimal `gorm:"type:decimal(7,4);not null"`
+	DayCount        string          `gorm:"type:varchar(16);not null"`
+	Rounding        string          `gorm:"type:varchar(16);not null"`
+	PayoutFrequency string          `gorm:"type:varchar(16);not null"`
+	Accrued         decimal.Decimal `gorm:"type:decimal(26,10);default:0;not null"`
+	NextAccrualDay  time.Time       `gorm:"not null;index"`
+	NextPayoutAt    time.Time       `gorm:"not null"`
+	CreatedAt       time.Time       `gorm:"not null"`
+	UpdatedAt       time.Time       `gorm:"not null"`
+}
+
+// InterestAccrual is keyed by wallet and day, so a day is accrued at most
+// once.
+type InterestAccrual struct {
+	WalletID  string          `gorm:"primaryKey;type:uuid"`
+	Day       time.Time       `gorm:"primaryKey"`
+	Balance   decimal.Decimal `gorm:"type:decimal(16,3);not null"`
+	Amount    decimal.Decimal `gorm:"type:decimal(26,10);not null"`
+	CreatedAt time.Time       `gorm:"not null"`
+}
+
+// InterestPayout is unique per wallet and period, so a period is paid at
+// most once.
+type InterestPayout struct {
+	ID            string          `gorm:"primaryKey;type:uuid"`
+	WalletID      string          `gorm:"type:uuid;not null;uniqueIndex:idx_interest_payouts_period,priority:1"`
+	PeriodEnd     time.Time       `gorm:"not null;uniqueIndex:idx_interest_payouts_period,priority:2"`
+	Amount        decimal.Decimal `gorm:"type:decimal(16,3);not null"`
+	TransactionID string          `gorm:"type:uuid;not null"`
+	CreatedAt     time.Time       `gorm:"not null"`
+}
+
+func FromEntity(entity entities.InterestPlan) (InterestPlan, error) {
+	return InterestPlan{
+		WalletID:        entity.WalletId,
+		Currency:        entity.Currency,
+		AnnualRate:      entity.AnnualRate,
+		DayCount:        entity.DayCount,
+		Rounding:        entity.Rounding,
+		PayoutFrequency: entity.PayoutFrequency,
+		Accrued:         entity.Accrued,
+		NextAccrualDay:  entity.NextAccrualDay,
+		NextPayoutAt:    entity.NextPayoutAt,
+		CreatedAt:       entity.CreatedAt,
+		UpdatedAt:       entity.UpdatedAt,
+	}, nil
+}
+
+func ToEntity(dto InterestPlan) (entities.InterestPlan, error) {
+	return entities.InterestPlan{
+		WalletId:        dto.WalletID,
+		Currency:        dto.Currency,
+		AnnualRate:      dto.AnnualRate,
+		DayCount:        dto.DayCount,
+		Rounding:        dto.Rounding,
+		PayoutFrequency: dto.PayoutFrequency,
+		Accrued:         dto.Accrued,
+		NextAccrualDay:  dto.NextAccrualDay.UTC(),
+		NextPayoutAt:    dto.NextPayoutAt.UTC(),
+		CreatedAt:       dto.CreatedAt,
+		UpdatedAt:       dto.UpdatedAt,
+	}, nil
+}
+
+func AccrualFromEntity(entity entities.InterestAccrual) (InterestAccrual, error) {
+	return InterestAccrual{
+		WalletID:  entity.WalletId,
+		Day:       entity.Day,
+		Balance:   entity.Balance,
+		Amount:    entity.Amount,
+		CreatedAt: entity.CreatedAt,
+	}, nil
+}
+
+func AccrualToEntity(dto InterestAccrual) (entities.InterestAccrual, error) {
+	return entities.InterestAccrual{
+		WalletId:  dto.WalletID,
+		Day:       dto.Day.UTC(),
+		Balance:   dto.Balance,
+		Amount:    dto.Amount,
+		CreatedAt: dto.CreatedAt,
+	}, nil
+}
+
+func PayoutFromEntity(entity entities.InterestPayout) (InterestPayout, error) {
+	return InterestPayout{
+		ID:            entity.ID,
+		WalletID:      entity.WalletId,
+		PeriodEnd:     entity.PeriodEnd,
+		Amount:        entity.Amount,
+		TransactionID: entity.TransactionId,
+		CreatedAt:     entity.CreatedAt,
+	}, nil
+}
+
+func PayoutToEntity(dto InterestPayout) (entities.InterestPayout, error) {
+	return entities.InterestPayout{
+		ID:            dto.ID,
+		WalletId:      dto.WalletID,
+		PeriodEnd:     dto.PeriodEnd.UTC(),
+		Amount:        dto.Amount,
+		TransactionId: dto.TransactionID,
+		CreatedAt:     dto.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,164 @@
+package interest
+
+import (
+	"TestProject/source/internal/entities"
+	"TestProject/source/internal/storage/transactor"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repo struct {
+	db *gorm.DB
+}
+
+func NewRepo(db *gorm.DB) *Repo {
+	return &Repo{db: db}
+}
+
+func (r *Repo) GetByWalletID(ctx context.Context, walletID string) (entities.InterestPlan, error) {
+	return r.get(transactor.DB(ctx, r.db), walletID)
+}
+
+// GetByWalletIDForUpdate locks the plan until the end of the transaction. It
+// waits for a worker that is accruing the plan.
+func (r *Repo) GetByWalletIDForUpdate(ctx context.Context, walletID string) (entities.InterestPlan, error) {
+	return r.get(transactor.DB(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}), walletID)
+}
+
+func (r *Repo) get(db *gorm.DB, walletID string) (entities.InterestPlan, error) {
+	var dto InterestPlan
+
+	err := db.Where("wallet_id = ?", walletID).First(&dto).Error
+	if err != nil {
+		return entities.InterestPlan{}, fmt.Errorf("db.First error: %w", notFound(err))
+	}
+
+	entity, err := ToEntity(dto)
+	if err != nil {
+		return entities.InterestPlan{}, fmt.Errorf("interest.ToEntity error: %w", err)
+	}
+
+	return entity, nil
+}
+
+// ClaimDue locks a plan with a day before before that is not accrued yet.
+// Plans locked by other workers are skipped. It returns
+// ErrInterestPlanNotFound when nothing is due.
+func (r *Repo) ClaimDue(ctx context.Context, before time.Time) (entities.InterestPlan, error) {
+	var dto InterestPlan
+
+	err := transactor.DB(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("next_accrual_day < ?", before).
+		Order("next_accrual_day").
+		First(&dto).Error
+	if err != nil {
+		return entities.InterestPlan{}, fmt.Errorf("db.First error: %w", notFound(err))
+	}
+
+	entity, err := ToEntity(dto)
+	if err != nil {
+		return entities.InterestPlan{}, fmt.Errorf("interest.ToEntity error: %w", err)
+	}
+
+	return entity, nil
+}
+
+// Save creates or replaces the plan.
+func (r *Repo) Save(ctx context.Context, plan entities.InterestPlan) (entities.InterestPlan, error) {
+	dto, err := FromEntity(plan)
+	if err != nil {
+		return entities.InterestPlan{}, fmt.Errorf("interest plan from entity error: %w", err)
+	}
+
+	err = transactor.DB(ctx, r.db).Save(&dto).Error
+	if err != nil {
+		return entities.InterestPlan{}, fmt.Errorf("db.Save error: %w", err)
+	}
+
+	entity, err := ToEntity(dto)
+	if err != nil {
+		return entities.InterestPlan{}, fmt.Errorf("interest.ToEntity error: %w", err)
+	}
+	return entity, nil
+}
+
+func (r *Repo) Delete(ctx context.Context, walletID string) error {
+	result := transactor.DB(ctx, r.db).Where("wallet_id = ?", walletID).Delete(&InterestPlan{})
+	if result.Error != nil {
+		return fmt.Errorf("db.Delete error: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return entities.ErrInterestPlanNotFound
+	}
+	return nil
+}
+
+// CreateAccrual stores the accrual and reports whether it is new. An
+// accrual for a day that already has one is dropped.
+func (r *Repo) CreateAccrual(ctx context.Context, accrual entities.InterestAccrual) (bool, error) {
+	dto, err := AccrualFromEntity(accrual)
+	if err != nil {
+		return false, fmt.Errorf("interest accrual from entity error: %w", err)
+	}
+
+	result := transactor.DB(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(&dto)
+	if result.Error != nil {
+		return false, fmt.Errorf("db.Create error: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *Repo) CreatePayout(ctx context.Context, payout entities.InterestPayout) (entities.InterestPayout, error) {
+	dto, err := PayoutFromEntity(payout)
+	if err != nil {
+		return entities.InterestPayout{}, fmt.Errorf("interest payout from entity error: %w", err)
+	}
+
+	if err := transactor.DB(ctx, r.db).Create(&dto).Error; err != nil {
+		return entities.InterestPayout{}, fmt.Errorf("failed to create interest payout: %w", err)
+	}
+
+	entity, err := PayoutToEntity(dto)
+	if err != nil {
+		return entities.InterestPayout{}, fmt.Errorf("interest payout to entity error: %w", err)
+	}
+	return entity, nil
+}
+
+// ListPayouts returns the wallet's payouts, newest first.
+func (r *Repo) ListPayouts(ctx context.Context, walletID string, limit int) ([]entities.InterestPayout, error) {
+	var dtos []InterestPayout
+
+	err := transactor.DB(ctx, r.db).
+		Where("wallet_id = ?", walletID).
+		Order("period_end DESC").
+		Limit(limit).
+		Find(&dtos).Error
+	if err != nil {
+		return nil, fmt.Errorf("db.Find error: %w", err)
+	}
+
+	result := make([]entities.InterestPayout, 0, len(dtos))
+	for _, dto := range dtos {
+		entity, err := PayoutToEntity(dto)
+		if err != nil {
+			return nil, fmt.Errorf("interest payout to entity error: %w", err)
+		}
+		result = append(result, entity)
+	}
+
+	return result, nil
+}
+
+func notFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return entities.ErrInterestPlanNotFound
+	}
+	return err
+}
@@ -0,0 +1,67 @@
+package journal
+
+import (
+	"TestProject/source/internal/entities"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type JournalEntry struct {
+	ID            string    `gorm:"primaryKey;type:uuid"`
+	OperationType string    `gorm:"type:varchar(32);not null"`
+	Postings      []Posting `gorm:"foreignKey:JournalEntryID;constraint:OnDelete:RESTRICT"`
+	CreatedAt     time.Time `gorm:"not null"`
+}
+
+type Posting struct {
+	ID             string          `gorm:"primaryKey;type:uuid"`
+	JournalEntryID string          `gorm:"type:uuid;not null;index"`
+	AccountType    string          `gorm:"type:varchar(16);not null;index:idx_postings_account,priority:1;index:idx_postings_account_created,priority:1"`
+	AccountID      string          `gorm:"type:varchar(64);not null;index:idx_postings_account,priority:2;index:idx_postings_account_created,priority:2"`
+	Currency       string          `gorm:"type:char(3);not null;index:idx_postings_account,priority:3"`
+	Amount         decimal.Decimal `gorm:"type:decimal(16,3);not null"`
+	CreatedAt      time.Time       `gorm:"not null;index:idx_postings_account_created,priority:3"`
+}
+
+func FromEntity(entity entities.JournalEntry) (JournalEntry, error) {
+	postings := make([]Posting, 0, len(entity.Postings))
+	for _, posting := range entity.Postings {
+		postings = append(postings, Posting{
+			ID:             uuid.NewString(),
+			JournalEntryID: entity.ID,
+			AccountType:    posting.AccountType,
+			AccountID:      posting.AccountId,
+			Currency:       posting.Currency,
+			Amount:         posting.Amount,
+			CreatedAt:      entity.CreatedAt,
+		})
+	}
+
+	return JournalEntry{
+		ID:            entity.ID,
+		OperationType: entity.OperationType,
+		Postings:      postings,
+		CreatedAt:     entity.CreatedAt,
+	}, nil
+}
+
+func ToEntity(dto JournalEntry) (entities.JournalEntry, error) {
+	postings := make([]entities.Posting, 0, len(dto.Postings))
+	for _, posting := range dto.Postings {
+		postings = append(postings, entities.Posting{
+			AccountType: posting.AccountType,
+			AccountId:   posting.AccountID,
+			Currency:    posting.Currency,
+			Amount:      posting.Amount,
+		})
+	}
+
+	return entities.JournalEntry{
+		ID:            dto.ID,
+		OperationType: dto.OperationType,
+		Postings:      postings,
+		CreatedAt:     dto.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,130 @@
+package journal
+
+import (
+	"TestProject/source/internal/entities"
+	"TestProject/source/internal/storage/transactor"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// Repo stores journal entries. Like the transactions ledger it is
+// append-only.
+type Repo struct {
+	db *gorm.DB
+}
+
+func NewRepo(db *gorm.DB) *Repo {
+	return &Repo{db: db}
+}
+
+// Create stores the entry together with its postings.
+func (r *Repo) Create(ctx context.Context, entry entities.JournalEntry) error {
+	dto, err := FromEntity(entry)
+	if err != nil {
+		return fmt.Errorf("journal entry from entity error: %w", err)
+	}
+
+	if err := transactor.DB(ctx, r.db).Create(&dto).Error; err != nil {
+		return fmt.Errorf("failed to create journal entry: %w", err)
+	}
+	return nil
+}
+
+// Balances sums postings per account of the given type.
+func (r *Repo) Balances(ctx context.Context, accountType string) ([]entities.AccountBalance, error) {
+	var rows []struct {
+		AccountType string
+		AccountID   string
+		Currency    string
+		Balance     decimal.Decimal
+	}
+
+	err := transactor.DB(ctx, r.db).
+		Model(&Posting{}).
+		Select("account_type, account_id, currency, SUM(amount) AS balance").
+		Where("account_type = ?", accountType).
+		Group("account_type, account_id, currency").
+		Order("account_id, currency").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("db.Scan error: %w", err)
+	}
+
+	result := make([]entities.AccountBalance, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, entities.AccountBalance{
+			AccountType: row.AccountType,
+			AccountId:   row.AccountID,
+			Currency:    row.Currency,
+			Balance:     row.Balance,
+		})
+	}
+
+	return result, nil
+}
+
+// WalletBalanceChecks returns up to limit wallets with IDs greater than
+// afterID, in ID order, together with the sum of their postings. The stored
+// and recomputed balances come from the same statement snapshot.
+func (r *Repo) WalletBalanceChecks(ctx context.Context, afterID string, limit int) ([]entities.BalanceCheck, error) {
+	var rows []struct {
+		ID            string
+		Currency      string
+		Balance       decimal.Decimal
+		LedgerBalance decimal.Decimal
+	}
+
+	query := transactor.DB(ctx, r.db).
+		Table("wallets AS w").
+		Select("w.id, w.currency, w.balance, COALESCE(p.balance, 0) AS ledger_balance").
+		Joins(`LEFT JOIN LATERAL (
+			SELECT SUM(amount) AS balance
+			FROM postings
+			WHERE account_type = ? AND account_id = w.id::text
+		) AS p ON true`, entities.AccountWallet).
+		Order("w.id").
+		Limit(limit)
+	if afterID != "" {
+		query = query.Where("w.id > ?", afterID)
+	}
+
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("db.Scan error: %w", err)
+	}
+
+	result := make([]entities.BalanceCheck, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, entities.BalanceCheck{
+			WalletId:      row.ID,
+			Currency:      row.Currency,
+			Balance:       row.Balance,
+			LedgerBalance: row.LedgerBalance,
+		})
+	}
+
+	return result, nil
+}
+
+// WalletPostingsSum sums the postings of a wallet created in [from, to].
+// A zero from sums from the beginning of the journal.
+func (r *Repo) WalletPostingsSum(ctx context.Context, walletID string, from, to time.Time) (decimal.Decimal, error) {
+	var sum decimal.Decimal
+
+	query := transactor.DB(ctx, r.db).
+		Model(&Posting{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("account_type = ? AND account_id = ? AND created_at <= ?", entities.AccountWallet, walletID, to)
+	if !from.IsZero() {
+		query = query.Where("created_at >= ?", from)
+	}
+
+	if err := query.Scan(&sum).Error; err != nil {
+		return decimal.Decimal{}, fmt.Errorf("db.Scan error: %w", err)
+	}
+
+	return sum, nil
+}
@@ -0,0 +1,42 @@
+package limits
+
+import (
+	"TestProject/source/internal/entities"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// WalletLimits overrides the default velocity limits for one wallet.
+type WalletLimits struct {
+	WalletID      string          `gorm:"primaryKey;type:uuid"`
+	PerOperation  decimal.Decimal `gorm:"type:decimal(16,3);default:0;not null"`
+	DailyAmount   decimal.Decimal `gorm:"type:decimal(16,3);default:0;not null"`
+	DailyCount    int64           `gorm:"default:0;not null"`
+	MonthlyAmount decimal.Decimal `gorm:"type:decimal(16,3);default:0;not null"`
+	MonthlyCount  int64           `gorm:"default:0;not null"`
+	UpdatedAt     time.Time       `gorm:"not null"`
+}
+
+func FromEntity(entity entities.VelocityLimits) (WalletLimits, error) {
+	return WalletLimits{
+		WalletID:      entity.WalletId,
+		PerOperation:  entity.PerOperation,
+		DailyAmount:   entity.DailyAmount,
+		DailyCount:    entity.DailyCount,
+		MonthlyAmount: entity.MonthlyAmount,
+		MonthlyCount:  entity.MonthlyCount,
+		UpdatedAt:     time.Now().UTC(),
+	}, nil
+}
+
+func ToEntity(dto WalletLimits) (entities.VelocityLimits, error) {
+	return entities.VelocityLimits{
+		WalletId:      dto.WalletID,
+		PerOperation:  dto.PerOperation,
+		DailyAmount:   dto.DailyAmount,
+		DailyCount:    dto.DailyCount,
+		MonthlyAmount: dto.MonthlyAmount,
+		MonthlyCount:  dto.MonthlyCount,
+	}, nil
+}
@@ -0,0 +1,72 @@
+package limits
+
+import (
+	"TestProject/source/internal/entities"
+	"TestProject/source/internal/storage/transactor"
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repo struct {
+	db *gorm.DB
+}
+
+func NewRepo(db *gorm.DB) *Repo {
+	return &Repo{db: db}
+}
+
+// GetByWalletID returns the wallet's own limits or ErrLimitsNotFound when it
+// uses the defaults.
+func (r *Repo) GetByWalletID(ctx context.Context, walletID string) (entities.VelocityLimits, error) {
+	var dto WalletLimits
+
+	err := transactor.DB(ctx, r.db).Where("wallet_id = ?", walletID).First(&dto).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = entities.ErrLimitsNotFound
+		}
+		return entities.VelocityLimits{}, fmt.Errorf("db.First error: %w", err)
+	}
+
+	entity, err := ToEntity(dto)
+	if err != nil {
+		return entities.VelocityLimits{}, fmt.Errorf("limits.ToEntity error: %w", err)
+	}
+
+	return entity, nil
+}
+
+// Save creates or replaces the wallet's limits.
+func (r *Repo) Save(ctx context.Context, limits entities.VelocityLimits) (entities.VelocityLimits, error) {
+	dto, err := FromEntity(limits)
+	if err != nil {
+		return entities.VelocityLimits{}, fmt.Errorf("limits from entity error: %w", err)
+	}
+
+	err = transactor.DB(ctx, r.db).
+		Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(&dto).Error
+	if err != nil {
+		return entities.VelocityLimits{}, fmt.Errorf("db.Create error: %w", err)
+	}
+
+	entity, err := ToEntity(dto)
+	if err != nil {
+		return entities.VelocityLimits{}, fmt.Errorf("limits.ToEntity error: %w", err)
+	}
+
+	return entity, nil
+}
+
+// Delete removes the wallet's limits so it falls back to the defaults.
+func (r *Repo) Delete(ctx context.Context, walletID string) error {
+	err := transactor.DB(ctx, r.db).Where("wallet_id = ?", walletID).Delete(&WalletLimits{}).Error
+	if err != nil {
+		return fmt.Errorf("db.Delete error: %w", err)
+	}
+	return nil
+}
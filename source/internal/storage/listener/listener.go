@@ -0,0 +1,177 @@
+package listener
+
+import (
+	"TestProject/source/internal/entities"
+	"TestProject/source/internal/storage/outbox"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	// subscriptionBuffer is how many events a subscriber may fall behind
+	// before it is dropped.
+	subscriptionBuffer = 64
+	reconnectDelay     = time.Second
+)
+
+// EventLoader reads the announced events from the outbox.
+type EventLoader interface {
+	ListBySequences(ctx context.Context, sequences []int64) ([]entities.WalletEvent, error)
+}
+
+// Listener receives the wallet events committed by every replica through
+// Postgres LISTEN on its own connection and fans them out to subscribers.
+// Notifications carry only event sequences; the events are loaded from the
+// outbox when someone is subscribed.
+type Listener struct {
+	log    *slog.Logger
+	dsn    string
+	events EventLoader
+
+	mu          sync.Mutex
+	subscribers map[string]map[chan entities.WalletEvent]struct{}
+}
+
+func New(log *slog.Logger, dsn string, events EventLoader) *Listener {
+	return &Listener{
+		log:         log,
+		dsn:         dsn,
+		events:      events,
+		subscribers: make(map[string]map[chan entities.WalletEvent]struct{}),
+	}
+}
+
+// Subscribe returns the events of the wallet committed from now on. The
+// channel is closed when events may have been missed: when the subscriber
+// falls behind or the listener reconnects.
+func (l *Listener) Subscribe(walletID string) (<-chan entities.WalletEvent, func()) {
+	events := make(chan entities.WalletEvent, subscriptionBuffer)
+
+	l.mu.Lock()
+	if l.subscribers[walletID] == nil {
+		l.subscribers[walletID] = make(map[chan entities.WalletEvent]struct{})
+	}
+	l.subscribers[walletID][events] = struct{}{}
+	l.mu.Unlock()
+
+	return events, func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.remove(walletID, events)
+	}
+}
+
+// Run listens until ctx is done, reconnecting after connection errors.
+func (l *Listener) Run(ctx context.Context) {
+	for {
+		err := l.listen(ctx)
+		// Notifications sent while not listening are lost, so every
+		// subscription has to start over.
+		l.dropAll()
+		if ctx.Err() != nil {
+			return
+		}
+		l.log.WarnContext(ctx, "wallet event listener disconnected", slog.String("error", err.Error()))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+func (l *Listener) listen(ctx context.Context) error {
+	conn, err := pgx.Connect(ctx, l.dsn)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.WithoutCancel(ctx))
+
+	_, err = conn.Exec(ctx, "LISTEN "+outbox.Channel)
+	if err != nil {
+		return err
+	}
+	// Subscriptions made before LISTEN took effect may have missed events.
+	l.dropAll()
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		var sequences []int64
+		err = json.Unmarshal([]byte(notification.Payload), &sequences)
+		if err != nil {
+			l.log.ErrorContext(ctx, "error decoding wallet event notification", slog.String("error", err.Error()))
+			continue
+		}
+		if !l.hasSubscribers() {
+			continue
+		}
+
+		events, err := l.events.ListBySequences(ctx, sequences)
+		if err != nil {
+			if ctx.Err() != nil {
+				return err
+			}
+			// The announced events are lost to the subscribers.
+			l.log.ErrorContext(ctx, "error loading wallet events", slog.String("error", err.Error()))
+			l.dropAll()
+			continue
+		}
+		for _, event := range events {
+			l.dispatch(event)
+		}
+	}
+}
+
+func (l *Listener) hasSubscribers() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return len(l.subscribers) > 0
+}
+
+func (l *Listener) dispatch(event entities.WalletEvent) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for events := range l.subscribers[event.WalletId] {
+		select {
+		case events <- event:
+		default:
+			l.remove(event.WalletId, events)
+		}
+	}
+}
+
+func (l *Listener) dropAll() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for walletID, subscribers := range l.subscribers {
+		for events := range subscribers {
+			l.remove(walletID, events)
+		}
+	}
+}
+
+// remove closes the subscription once; l.mu must be held.
+func (l *Listener) remove(walletID string, events chan entities.WalletEvent) {
+	subscribers := l.subscribers[walletID]
+	if _, ok := subscribers[events]; !ok {
+		return
+	}
+	delete(subscribers, events)
+	close(events)
+	if len(subscribers) == 0 {
+		delete(l.subscribers, walletID)
+	}
+}
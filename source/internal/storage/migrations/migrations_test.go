@@ -0,0 +1,178 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// testDB returns a connection to an empty schema of the database in
+// TEST_DATABASE_DSN and drops the schema when the test ends. Tests are
+// skipped when the variable is not set.
+func testDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+	config, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		t.Fatalf("invalid TEST_DATABASE_DSN: %v", err)
+	}
+
+	admin := stdlib.OpenDB(*config.Copy())
+	t.Cleanup(func() { admin.Close() })
+
+	schema := "migrations_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	_, err = admin.Exec("CREATE SCHEMA " + schema)
+	if err != nil {
+		t.Fatalf("error creating schema: %v", err)
+	}
+	t.Cleanup(func() {
+		_, err := admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+		if err != nil {
+			t.Errorf("error dropping schema: %v", err)
+		}
+	})
+
+	config.RuntimeParams["search_path"] = schema
+	db := stdlib.OpenDB(*config)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func migrateUp(t *testing.T, db *sql.DB) {
+	t.Helper()
+
+	migrator, err := New(db)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = migrator.Up(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pending, err := migrator.Pending(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Fatalf("expected no pending migrations, got %d", len(pending))
+	}
+}
+
+func columnScale(t *testing.T, db *sql.DB, table, column string) int {
+	t.Helper()
+
+	var scale int
+	err := db.QueryRow(`SELECT numeric_scale FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = $1 AND column_name = $2`, table, column).Scan(&scale)
+	if err != nil {
+		t.Fatalf("error reading %s.%s: %v", table, column, err)
+	}
+	return scale
+}
+
+func TestMigrator_Up_FreshDatabase(t *testing.T) {
+	db := testDB(t)
+
+	migrateUp(t, db)
+	// A second run finds nothing to apply.
+	migrateUp(t, db)
+
+	if scale := columnScale(t, db, "wallets", "balance"); scale != 3 {
+		t.Errorf("expected wallets.balance scale 3, got %d", scale)
+	}
+}
+
+func TestMigrator_Up_AdoptsAutoMigrateDatabase(t *testing.T) {
+	db := testDB(t)
+
+	// The schema AutoMigrate created before wallets had owners, currencies
+	// and statuses, with the first version of the transactions ledger.
+	walletID := uuid.NewString()
+	for _, statement := range []string{
+		`CREATE TABLE wallets (
+			id      uuid PRIMARY KEY,
+			balance decimal(15,2) NOT NULL DEFAULT 0
+		)`,
+		`CREATE TABLE transactions (
+			id             uuid PRIMARY KEY,
+			wallet_id      uuid NOT NULL,
+			operation_type varchar(32) NOT NULL,
+			amount         decimal(15,2) NOT NULL,
+			balance_before decimal(15,2) NOT NULL,
+			balance_after  decimal(15,2) NOT NULL,
+			created_at     timestamptz NOT NULL
+		)`,
+		`CREATE INDEX idx_transactions_wallet_created ON transactions (wallet_id, created_at)`,
+		fmt.Sprintf(`INSERT INTO wallets (id, balance) VALUES ('%s', 100.50)`, walletID),
+		fmt.Sprintf(`INSERT INTO transactions (id, wallet_id, operation_type, amount, balance_before, balance_after, created_at)
+			VALUES ('%s', '%s', 'DEPOSIT', 100.50, 0, 100.50, now())`, uuid.NewString(), walletID),
+	} {
+		_, err := db.Exec(statement)
+		if err != nil {
+			t.Fatalf("error creating AutoMigrate schema: %v", err)
+		}
+	}
+
+	migrateUp(t, db)
+
+	var currency, status, reserved, overdraft string
+	err := db.QueryRow("SELECT currency, status, reserved, overdraft_limit FROM wallets WHERE id = $1", walletID).
+		Scan(&currency, &status, &reserved, &overdraft)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if currency != "RUB" || status != "ACTIVE" || reserved != "0.000" || overdraft != "0.000" {
+		t.Errorf("unexpected defaults: currency %s, status %s, reserved %s, overdraft %s", currency, status, reserved, overdraft)
+	}
+
+	var fee string
+	err = db.QueryRow("SELECT currency, fee FROM transactions WHERE wallet_id = $1", walletID).Scan(&currency, &fee)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if currency != "RUB" || fee != "0.000" {
+		t.Errorf("unexpected defaults: currency %s, fee %s", currency, fee)
+	}
+
+	for _, column := range []struct{ table, name string }{
+		{"wallets", "balance"},
+		{"transactions", "amount"},
+		{"transactions", "balance_before"},
+		{"transactions", "balance_after"},
+	} {
+		if scale := columnScale(t, db, column.table, column.name); scale != 3 {
+			t.Errorf("expected %s.%s scale 3, got %d", column.table, column.name, scale)
+		}
+	}
+
+	var opening string
+	err = db.QueryRow("SELECT amount FROM postings WHERE account_type = 'WALLET' AND account_id = $1", walletID).Scan(&opening)
+	if err != nil {
+		t.Fatalf("expected an opening balance posting: %v", err)
+	}
+	if opening != "100.500" {
+		t.Errorf("expected opening balance 100.500, got %s", opening)
+	}
+
+	var constraints int
+	err = db.QueryRow(`SELECT count(*) FROM pg_constraint
+		WHERE conrelid = 'wallets'::regclass AND conname IN ('chk_wallets_balance_floor', 'chk_wallets_overdraft_limit', 'fk_customers_wallets')`).
+		Scan(&constraints)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if constraints != 3 {
+		t.Errorf("expected 3 wallet constraints, got %d", constraints)
+	}
+}
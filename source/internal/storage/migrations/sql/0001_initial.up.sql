@@ -0,0 +1,214 @@
+-- Baseline schema, as previously created by GORM AutoMigrate. Tables and
+-- indexes use IF NOT EXISTS, so tables AutoMigrate already created are kept.
+--
+-- Older AutoMigrate databases have wallets and transactions without the
+-- columns added later, and amounts in decimal(15,2). The ALTER TABLE
+-- statements after those two tables add the missing columns with the same
+-- defaults and bring the amounts to decimal(16,3); on a fresh database they
+-- change nothing. Constraints missing on such tables are added by 0006.
+
+CREATE TABLE IF NOT EXISTS customers (
+    id         uuid PRIMARY KEY,
+    name       varchar(255) NOT NULL,
+    email      varchar(255),
+    phone      varchar(32),
+    created_at timestamptz NOT NULL,
+    updated_at timestamptz NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_customers_email ON customers (email);
+
+CREATE TABLE IF NOT EXISTS wallets (
+    id                uuid PRIMARY KEY,
+    owner_id          uuid,
+    currency          char(3) NOT NULL DEFAULT 'RUB',
+    balance           decimal(16,3) NOT NULL DEFAULT 0,
+    reserved          decimal(16,3) NOT NULL DEFAULT 0,
+    overdraft_limit   decimal(16,3) NOT NULL DEFAULT 0,
+    status            varchar(16) NOT NULL DEFAULT 'ACTIVE',
+    freeze_mode       varchar(8),
+    status_reason     varchar(64),
+    status_changed_at timestamptz,
+    CONSTRAINT fk_customers_wallets FOREIGN KEY (owner_id) REFERENCES customers (id) ON DELETE RESTRICT,
+    CONSTRAINT chk_wallets_balance_floor CHECK (balance >= -overdraft_limit),
+    CONSTRAINT chk_wallets_overdraft_limit CHECK (overdraft_limit >= 0)
+);
+ALTER TABLE wallets
+    ADD COLUMN IF NOT EXISTS owner_id          uuid,
+    ADD COLUMN IF NOT EXISTS currency          char(3) NOT NULL DEFAULT 'RUB',
+    ADD COLUMN IF NOT EXISTS reserved          decimal(16,3) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS overdraft_limit   decimal(16,3) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS status            varchar(16) NOT NULL DEFAULT 'ACTIVE',
+    ADD COLUMN IF NOT EXISTS freeze_mode       varchar(8),
+    ADD COLUMN IF NOT EXISTS status_reason     varchar(64),
+    ADD COLUMN IF NOT EXISTS status_changed_at timestamptz,
+    ALTER COLUMN balance TYPE decimal(16,3);
+CREATE INDEX IF NOT EXISTS idx_wallets_owner_id ON wallets (owner_id);
+
+CREATE TABLE IF NOT EXISTS transactions (
+    id             uuid PRIMARY KEY,
+    wallet_id      uuid NOT NULL,
+    operation_type varchar(32) NOT NULL,
+    currency       char(3) NOT NULL DEFAULT 'RUB',
+    amount         decimal(16,3) NOT NULL,
+    fee            decimal(16,3) NOT NULL DEFAULT 0,
+    balance_before decimal(16,3) NOT NULL,
+    balance_after  decimal(16,3) NOT NULL,
+    reference_id   uuid,
+    created_at     timestamptz NOT NULL
+);
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS currency     char(3) NOT NULL DEFAULT 'RUB',
+    ADD COLUMN IF NOT EXISTS fee          decimal(16,3) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS reference_id uuid,
+    ALTER COLUMN amount TYPE decimal(16,3),
+    ALTER COLUMN balance_before TYPE decimal(16,3),
+    ALTER COLUMN balance_after TYPE decimal(16,3);
+CREATE INDEX IF NOT EXISTS idx_transactions_wallet_created ON transactions (wallet_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_transactions_reference_id ON transactions (reference_id);
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    key          varchar(255) PRIMARY KEY,
+    request_hash char(64) NOT NULL,
+    status_code  bigint NOT NULL DEFAULT 0,
+    response     bytea,
+    created_at   timestamptz NOT NULL,
+    expires_at   timestamptz NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
+
+CREATE TABLE IF NOT EXISTS holds (
+    id              uuid PRIMARY KEY,
+    wallet_id       uuid NOT NULL,
+    currency        char(3) NOT NULL,
+    amount          decimal(16,3) NOT NULL,
+    captured_amount decimal(16,3) NOT NULL DEFAULT 0,
+    status          varchar(16) NOT NULL,
+    transaction_id  uuid,
+    expires_at      timestamptz NOT NULL,
+    created_at      timestamptz NOT NULL,
+    updated_at      timestamptz NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_holds_wallet_id ON holds (wallet_id);
+CREATE INDEX IF NOT EXISTS idx_holds_status_expires ON holds (status, expires_at);
+
+CREATE TABLE IF NOT EXISTS journal_entries (
+    id             uuid PRIMARY KEY,
+    operation_type varchar(32) NOT NULL,
+    created_at     timestamptz NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS postings (
+    id               uuid PRIMARY KEY,
+    journal_entry_id uuid NOT NULL,
+    account_type     varchar(16) NOT NULL,
+    account_id       varchar(64) NOT NULL,
+    currency         char(3) NOT NULL,
+    amount           decimal(16,3) NOT NULL,
+    created_at       timestamptz NOT NULL,
+    CONSTRAINT fk_journal_entries_postings FOREIGN KEY (journal_entry_id) REFERENCES journal_entries (id) ON DELETE RESTRICT
+);
+CREATE INDEX IF NOT EXISTS idx_postings_journal_entry_id ON postings (journal_entry_id);
+CREATE INDEX IF NOT EXISTS idx_postings_account ON postings (account_type, account_id, currency);
+CREATE INDEX IF NOT EXISTS idx_postings_account_created ON postings (account_type, account_id, created_at);
+
+CREATE TABLE IF NOT EXISTS wallet_limits (
+    wallet_id      uuid PRIMARY KEY,
+    per_operation  decimal(16,3) NOT NULL DEFAULT 0,
+    daily_amount   decimal(16,3) NOT NULL DEFAULT 0,
+    daily_count    bigint NOT NULL DEFAULT 0,
+    monthly_amount decimal(16,3) NOT NULL DEFAULT 0,
+    monthly_count  bigint NOT NULL DEFAULT 0,
+    updated_at     timestamptz NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS schedules (
+    id             uuid PRIMARY KEY,
+    wallet_id      uuid NOT NULL,
+    operation_type varchar(32) NOT NULL,
+    currency       char(3) NOT NULL,
+    amount         decimal(16,3) NOT NULL,
+    frequency      varchar(16) NOT NULL,
+    start_at       timestamptz NOT NULL,
+    end_at         timestamptz,
+    occurrence     bigint NOT NULL DEFAULT 0,
+    next_run_at    timestamptz NOT NULL,
+    status         varchar(16) NOT NULL,
+    created_at     timestamptz NOT NULL,
+    updated_at     timestamptz NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_schedules_wallet_id ON schedules (wallet_id);
+CREATE INDEX IF NOT EXISTS idx_schedules_status_next_run ON schedules (status, next_run_at);
+
+CREATE TABLE IF NOT EXISTS schedule_runs (
+    id             uuid PRIMARY KEY,
+    schedule_id    uuid NOT NULL,
+    occurrence     bigint NOT NULL,
+    scheduled_at   timestamptz NOT NULL,
+    status         varchar(16) NOT NULL,
+    transaction_id uuid,
+    error          text,
+    created_at     timestamptz NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_schedule_runs_occurrence ON schedule_runs (schedule_id, occurrence);
+
+CREATE TABLE IF NOT EXISTS balance_snapshots (
+    wallet_id  uuid,
+    day        timestamptz,
+    balance    decimal(16,3) NOT NULL,
+    created_at timestamptz NOT NULL,
+    PRIMARY KEY (wallet_id, day)
+);
+
+CREATE TABLE IF NOT EXISTS snapshot_days (
+    day        timestamptz PRIMARY KEY,
+    wallets    bigint NOT NULL,
+    created_at timestamptz NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS fee_rules (
+    scope          varchar(64),
+    operation_type varchar(32),
+    currency       char(3),
+    type           varchar(16) NOT NULL,
+    flat           decimal(16,3) NOT NULL DEFAULT 0,
+    percent        decimal(7,4) NOT NULL DEFAULT 0,
+    tiers          jsonb NOT NULL DEFAULT '[]',
+    min_fee        decimal(16,3) NOT NULL DEFAULT 0,
+    max_fee        decimal(16,3) NOT NULL DEFAULT 0,
+    updated_at     timestamptz NOT NULL,
+    PRIMARY KEY (scope, operation_type, currency)
+);
+
+CREATE TABLE IF NOT EXISTS interest_plans (
+    wallet_id        uuid PRIMARY KEY,
+    currency         char(3) NOT NULL,
+    annual_rate      decimal(7,4) NOT NULL,
+    day_count        varchar(16) NOT NULL,
+    rounding         varchar(16) NOT NULL,
+    payout_frequency varchar(16) NOT NULL,
+    accrued          decimal(26,10) NOT NULL DEFAULT 0,
+    next_accrual_day timestamptz NOT NULL,
+    next_payout_at   timestamptz NOT NULL,
+    created_at       timestamptz NOT NULL,
+    updated_at       timestamptz NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_interest_plans_next_accrual_day ON interest_plans (next_accrual_day);
+
+CREATE TABLE IF NOT EXISTS interest_accruals (
+    wallet_id  uuid,
+    day        timestamptz,
+    balance    decimal(16,3) NOT NULL,
+    amount     decimal(26,10) NOT NULL,
+    created_at timestamptz NOT NULL,
+    PRIMARY KEY (wallet_id, day)
+);
+
+CREATE TABLE IF NOT EXISTS interest_payouts (
+    id             uuid PRIMARY KEY,
+    wallet_id      uuid NOT NULL,
+    period_end     timestamptz NOT NULL,
+    amount         decimal(16,3) NOT NULL,
+    transaction_id uuid NOT NULL,
+    created_at     timestamptz NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_interest_payouts_period ON interest_payouts (wallet_id, period_end);
@@ -0,0 +1,20 @@
+CREATE TABLE outbox_events (
+    sequence       bigserial PRIMARY KEY,
+    id             uuid NOT NULL,
+    type           varchar(32) NOT NULL,
+    wallet_id      uuid NOT NULL,
+    transaction_id uuid NOT NULL,
+    operation_type varchar(32) NOT NULL,
+    currency       char(3) NOT NULL,
+    amount         decimal(16,3) NOT NULL,
+    fee            decimal(16,3) NOT NULL DEFAULT 0,
+    balance        decimal(16,3) NOT NULL,
+    occurred_at    timestamptz NOT NULL,
+    published_at   timestamptz,
+    attempts       bigint NOT NULL DEFAULT 0,
+    last_error     text
+);
+CREATE UNIQUE INDEX idx_outbox_events_id ON outbox_events (id);
+CREATE INDEX idx_outbox_events_published_at ON outbox_events (published_at);
+-- The relay scans unpublished events in sequence order.
+CREATE INDEX idx_outbox_events_unpublished ON outbox_events (sequence) WHERE published_at IS NULL;
@@ -0,0 +1,29 @@
+CREATE TABLE webhooks (
+    id         uuid PRIMARY KEY,
+    wallet_id  uuid,
+    url        text NOT NULL,
+    secret     varchar(128) NOT NULL,
+    created_at timestamptz NOT NULL
+);
+CREATE INDEX idx_webhooks_wallet_id ON webhooks (wallet_id);
+
+CREATE TABLE webhook_deliveries (
+    id               uuid PRIMARY KEY,
+    webhook_id       uuid NOT NULL,
+    event_id         uuid NOT NULL,
+    event_type       varchar(32) NOT NULL,
+    wallet_id        uuid NOT NULL,
+    payload          bytea NOT NULL,
+    status           varchar(16) NOT NULL,
+    attempts         bigint NOT NULL DEFAULT 0,
+    next_attempt_at  timestamptz NOT NULL,
+    last_status_code bigint,
+    last_error       text,
+    delivered_at     timestamptz,
+    created_at       timestamptz NOT NULL,
+    updated_at       timestamptz NOT NULL,
+    CONSTRAINT fk_webhooks_deliveries FOREIGN KEY (webhook_id) REFERENCES webhooks (id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX idx_webhook_deliveries_event ON webhook_deliveries (webhook_id, event_id);
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries (status, next_attempt_at);
+CREATE INDEX idx_webhook_deliveries_created_at ON webhook_deliveries (created_at);
@@ -0,0 +1,2 @@
+-- Wallet streams replay the events of one wallet after a given sequence.
+CREATE INDEX idx_outbox_events_wallet ON outbox_events (wallet_id, sequence);
@@ -0,0 +1,33 @@
+-- Wallets that had a balance before the journal existed and have not been
+-- used since have no postings for it, and reconciliation reports them as
+-- drift. Post their balance as an OPENING_BALANCE entry against the system
+-- EQUITY account, dated at the migration.
+--
+-- Wallets with postings are left alone: a difference there is real drift,
+-- and reconciliation has to report it. Balance snapshots only cover wallets
+-- with postings, so none of them change.
+
+CREATE TEMPORARY TABLE opening_balances ON COMMIT DROP AS
+SELECT gen_random_uuid() AS entry_id,
+       w.id::text AS wallet_id,
+       w.currency,
+       w.balance AS amount,
+       now() AS created_at
+FROM wallets AS w
+WHERE w.balance <> 0
+  AND NOT EXISTS (
+      SELECT 1
+      FROM postings AS p
+      WHERE p.account_type = 'WALLET' AND p.account_id = w.id::text
+  );
+
+INSERT INTO journal_entries (id, operation_type, created_at)
+SELECT entry_id, 'OPENING_BALANCE', created_at
+FROM opening_balances;
+
+INSERT INTO postings (id, journal_entry_id, account_type, account_id, currency, amount, created_at)
+SELECT gen_random_uuid(), entry_id, 'WALLET', wallet_id, currency, amount, created_at
+FROM opening_balances
+UNION ALL
+SELECT gen_random_uuid(), entry_id, 'SYSTEM', 'EQUITY', currency, -amount, created_at
+FROM opening_balances;
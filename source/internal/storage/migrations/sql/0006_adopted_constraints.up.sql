@@ -0,0 +1,29 @@
+-- 0001 adopts databases created by AutoMigrate through CREATE TABLE IF NOT
+-- EXISTS, which leaves existing tables as they are. Add the constraints of
+-- 0001 that such tables may miss. Each one is added only when no constraint
+-- of that name exists, so databases created by 0001 are left unchanged.
+--
+-- chk_wallets_balance_floor fails on wallets already below their overdraft
+-- floor; set their overdraft limit before migrating.
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'chk_wallets_overdraft_limit' AND conrelid = 'wallets'::regclass) THEN
+        ALTER TABLE wallets ADD CONSTRAINT chk_wallets_overdraft_limit CHECK (overdraft_limit >= 0);
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'chk_wallets_balance_floor' AND conrelid = 'wallets'::regclass) THEN
+        ALTER TABLE wallets ADD CONSTRAINT chk_wallets_balance_floor CHECK (balance >= -overdraft_limit);
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_customers_wallets' AND conrelid = 'wallets'::regclass) THEN
+        ALTER TABLE wallets ADD CONSTRAINT fk_customers_wallets
+            FOREIGN KEY (owner_id) REFERENCES customers (id) ON DELETE RESTRICT;
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_journal_entries_postings' AND conrelid = 'postings'::regclass) THEN
+        ALTER TABLE postings ADD CONSTRAINT fk_journal_entries_postings
+            FOREIGN KEY (journal_entry_id) REFERENCES journal_entries (id) ON DELETE RESTRICT;
+    END IF;
+END
+$$;
@@ -3,8 +3,24 @@ package storage
 import (
 	"TestProject/source/config"
 	"TestProject/source/internal/application"
+	"TestProject/source/internal/storage/customer"
+	"TestProject/source/internal/storage/fee"
+	"TestProject/source/internal/storage/hold"
+	"TestProject/source/internal/storage/idempotency"
+	"TestProject/source/internal/storage/interest"
+	"TestProject/source/internal/storage/journal"
+	"TestProject/source/internal/storage/limits"
+	"TestProject/source/internal/storage/listener"
+	"TestProject/source/internal/storage/migrations"
+	"TestProject/source/internal/storage/outbox"
+	"TestProject/source/internal/storage/schedule"
+	"TestProject/source/internal/storage/snapshot"
+	"TestProject/source/internal/storage/transaction"
+	"TestProject/source/internal/storage/transactor"
 	"TestProject/source/internal/storage/wallet"
+	"TestProject/source/internal/storage/webhook"
 	"context"
+	"database/sql"
 	"fmt"
 	"log/slog"
 	"time"
@@ -21,10 +37,66 @@ var Module = fx.Module(
 	moduleName,
 	fx.Provide(
 		NewDatabaseConnection,
+		transactor.New,
 		wallet.NewRepo,
+		transaction.NewRepo,
+		idempotency.NewRepo,
+		hold.NewRepo,
+		journal.NewRepo,
+		limits.NewRepo,
+		schedule.NewRepo,
+		snapshot.NewRepo,
+		fee.NewRepo,
+		interest.NewRepo,
+		customer.NewRepo,
+		outbox.NewRepo,
+		webhook.NewRepo,
+		NewListener,
+		func(t *transactor.Transactor) application.Transactor {
+			return t
+		},
 		func(repo *wallet.Repo) application.WalletRepo {
 			return repo
 		},
+		func(repo *transaction.Repo) application.TransactionRepo {
+			return repo
+		},
+		func(repo *idempotency.Repo) application.IdempotencyRepo {
+			return repo
+		},
+		func(repo *hold.Repo) application.HoldRepo {
+			return repo
+		},
+		func(repo *journal.Repo) application.JournalRepo {
+			return repo
+		},
+		func(repo *limits.Repo) application.LimitRepo {
+			return repo
+		},
+		func(repo *schedule.Repo) application.ScheduleRepo {
+			return repo
+		},
+		func(repo *snapshot.Repo) application.SnapshotRepo {
+			return repo
+		},
+		func(repo *fee.Repo) application.FeeRepo {
+			return repo
+		},
+		func(repo *interest.Repo) application.InterestRepo {
+			return repo
+		},
+		func(repo *customer.Repo) application.CustomerRepo {
+			return repo
+		},
+		func(repo *outbox.Repo) application.OutboxRepo {
+			return repo
+		},
+		func(repo *webhook.Repo) application.WebhookRepo {
+			return repo
+		},
+		func(l *listener.Listener) application.EventSubscriber {
+			return l
+		},
 	),
 	fx.Decorate(
 		func(log *slog.Logger) *slog.Logger {
@@ -39,7 +111,59 @@ const (
 )
 
 func NewDatabaseConnection(lc fx.Lifecycle, conf config.DBConfig, logger *slog.Logger) (*gorm.DB, error) {
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=Europe/Moscow",
+	db, err := OpenDatabase(conf, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	err = prepareSchema(context.Background(), sqlDB, conf, logger)
+	if err != nil {
+		_ = sqlDB.Close()
+		return nil, err
+	}
+
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			return sqlDB.Close()
+		},
+	})
+
+	return db, nil
+}
+
+// NewListener starts listening for committed wallet events on a connection
+// of its own, since LISTEN does not work through the pool.
+func NewListener(lc fx.Lifecycle, conf config.DBConfig, logger *slog.Logger, repo *outbox.Repo) *listener.Listener {
+	l := listener.New(logger, dataSourceName(conf), repo)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				l.Run(ctx)
+			}()
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+			<-done
+			return nil
+		},
+	})
+
+	return l
+}
+
+func dataSourceName(conf config.DBConfig) string {
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=Europe/Moscow",
 		conf.Host,
 		conf.User,
 		conf.Password,
@@ -47,6 +171,11 @@ func NewDatabaseConnection(lc fx.Lifecycle, conf config.DBConfig, logger *slog.L
 		conf.Port,
 		conf.SSLMode,
 	)
+}
+
+// OpenDatabase connects to the database without touching the schema.
+func OpenDatabase(conf config.DBConfig, logger *slog.Logger) (*gorm.DB, error) {
+	dsn := dataSourceName(conf)
 
 	gormLogger := slogGorm.New(
 		slogGorm.WithHandler(logger.Handler()),
@@ -72,21 +201,39 @@ func NewDatabaseConnection(lc fx.Lifecycle, conf config.DBConfig, logger *slog.L
 	sqlDB.SetMaxOpenConns(200)
 	sqlDB.SetConnMaxLifetime(time.Minute * 15)
 
-	err = db.AutoMigrate(&wallet.Wallet{})
+	return db, nil
+}
+
+// prepareSchema applies pending migrations when DB_MIGRATE is set and
+// otherwise refuses to start on a schema older than the binary. A newer
+// schema is accepted, so that the previous release keeps running while a
+// new one is rolled out.
+func prepareSchema(ctx context.Context, db *sql.DB, conf config.DBConfig, logger *slog.Logger) error {
+	migrator, err := migrations.New(db)
 	if err != nil {
-		logger.Error("cannot auto migrate", slog.Any("error", err))
-		return nil, err
+		return err
 	}
 
-	lc.Append(fx.Hook{
-		OnStop: func(ctx context.Context) error {
-			sqlDB, err := db.DB()
-			if err != nil {
-				return err
-			}
-			return sqlDB.Close()
-		},
-	})
+	if conf.Migrate {
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			logger.Error("cannot migrate", slog.Any("error", err))
+			return err
+		}
+		for _, migration := range applied {
+			logger.Info("migration applied",
+				slog.Int64("version", migration.Version), slog.String("name", migration.Name))
+		}
+		return nil
+	}
 
-	return db, nil
+	pending, err := migrator.Pending(ctx)
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%w: %d migrations pending, the first is %d_%s; run migrate up or set DB_MIGRATE=true",
+			migrations.ErrSchemaBehind, len(pending), pending[0].Version, pending[0].Name)
+	}
+	return nil
 }
@@ -0,0 +1,59 @@
+package outbox
+
+import (
+	"TestProject/source/internal/entities"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// OutboxEvent is a wallet event waiting to be published. PublishedAt is set
+// once the relay has handed it to the publisher.
+type OutboxEvent struct {
+	Sequence      int64           `gorm:"primaryKey;autoIncrement;index:idx_outbox_events_wallet,priority:2"`
+	ID            string          `gorm:"type:uuid;not null;uniqueIndex"`
+	Type          string          `gorm:"type:varchar(32);not null"`
+	WalletID      string          `gorm:"type:uuid;not null;index:idx_outbox_events_wallet,priority:1"`
+	TransactionID string          `gorm:"type:uuid;not null"`
+	OperationType string          `gorm:"type:varchar(32);not null"`
+	Currency      string          `gorm:"type:char(3);not null"`
+	Amount        decimal.Decimal `gorm:"type:decimal(16,3);not null"`
+	Fee           decimal.Decimal `gorm:"type:decimal(16,3);default:0;not null"`
+	Balance       decimal.Decimal `gorm:"type:decimal(16,3);not null"`
+	OccurredAt    time.Time       `gorm:"not null"`
+	PublishedAt   *time.Time      `gorm:"index"`
+	Attempts      int             `gorm:"not null;default:0"`
+	LastError     *string         `gorm:"type:text"`
+}
+
+func FromEntity(entity entities.WalletEvent) (OutboxEvent, error) {
+	return OutboxEvent{
+		Sequence:      entity.Sequence,
+		ID:            entity.ID,
+		Type:          entity.Type,
+		WalletID:      entity.WalletId,
+		TransactionID: entity.TransactionId,
+		OperationType: entity.OperationType,
+		Currency:      entity.Currency,
+		Amount:        entity.Amount,
+		Fee:           entity.Fee,
+		Balance:       entity.Balance,
+		OccurredAt:    entity.OccurredAt,
+	}, nil
+}
+
+func ToEntity(dto OutboxEvent) (entities.WalletEvent, error) {
+	return entities.WalletEvent{
+		ID:            dto.ID,
+		Sequence:      dto.Sequence,
+		Type:          dto.Type,
+		WalletId:      dto.WalletID,
+		TransactionId: dto.TransactionID,
+		OperationType: dto.OperationType,
+		Currency:      dto.Currency,
+		Amount:        dto.Amount,
+		Fee:           dto.Fee,
+		Balance:       dto.Balance,
+		OccurredAt:    dto.OccurredAt,
+	}, nil
+}
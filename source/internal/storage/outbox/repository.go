@@ -0,0 +1,222 @@
+package outbox
+
+import (
+	"TestProject/source/internal/entities"
+	"TestProject/source/internal/storage/transactor"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Channel is the Postgres notification channel the relay announces
+// committed events on, as a JSON array of their sequences.
+const Channel = "wallet_events"
+
+type Repo struct {
+	db *gorm.DB
+}
+
+func NewRepo(db *gorm.DB) *Repo {
+	return &Repo{db: db}
+}
+
+// Create adds the event to the outbox and returns it with its sequence.
+func (r *Repo) Create(ctx context.Context, event entities.WalletEvent) (entities.WalletEvent, error) {
+	dto, err := FromEntity(event)
+	if err != nil {
+		return entities.WalletEvent{}, fmt.Errorf("outbox event from entity error: %w", err)
+	}
+
+	err = transactor.DB(ctx, r.db).Create(&dto).Error
+	if err != nil {
+		return entities.WalletEvent{}, fmt.Errorf("db.Create error: %w", err)
+	}
+
+	entity, err := ToEntity(dto)
+	if err != nil {
+		return entities.WalletEvent{}, fmt.Errorf("outbox.ToEntity error: %w", err)
+	}
+
+	return entity, nil
+}
+
+// Notify announces the events with the given sequences on Channel. Postgres
+// delivers a notification only when its transaction commits, and every
+// notifying transaction commits under one global lock, so the relay sends
+// one per batch rather than each write one per event.
+func (r *Repo) Notify(ctx context.Context, sequences []int64) error {
+	payload, err := json.Marshal(sequences)
+	if err != nil {
+		return fmt.Errorf("error encoding event sequences: %w", err)
+	}
+	err = transactor.DB(ctx, r.db).Exec("SELECT pg_notify(?, ?)", Channel, string(payload)).Error
+	if err != nil {
+		return fmt.Errorf("db.Exec pg_notify error: %w", err)
+	}
+	return nil
+}
+
+// ListBySequences returns the events with the given sequences that are
+// still in the outbox, in sequence order.
+func (r *Repo) ListBySequences(ctx context.Context, sequences []int64) ([]entities.WalletEvent, error) {
+	var dtos []OutboxEvent
+
+	err := transactor.DB(ctx, r.db).
+		Where("sequence IN ?", sequences).
+		Order("sequence").
+		Find(&dtos).Error
+	if err != nil {
+		return nil, fmt.Errorf("db.Find error: %w", err)
+	}
+
+	result := make([]entities.WalletEvent, 0, len(dtos))
+	for _, dto := range dtos {
+		entity, err := ToEntity(dto)
+		if err != nil {
+			return nil, fmt.Errorf("outbox.ToEntity error: %w", err)
+		}
+		result = append(result, entity)
+	}
+
+	return result, nil
+}
+
+// ListByWallet returns up to limit events of the wallet after the given
+// sequence, in sequence order, published or not.
+func (r *Repo) ListByWallet(ctx context.Context, walletID string, afterSequence int64, limit int) ([]entities.WalletEvent, error) {
+	var dtos []OutboxEvent
+
+	err := transactor.DB(ctx, r.db).
+		Where("wallet_id = ? AND sequence > ?", walletID, afterSequence).
+		Order("sequence").
+		Limit(limit).
+		Find(&dtos).Error
+	if err != nil {
+		return nil, fmt.Errorf("db.Find error: %w", err)
+	}
+
+	result := make([]entities.WalletEvent, 0, len(dtos))
+	for _, dto := range dtos {
+		entity, err := ToEntity(dto)
+		if err != nil {
+			return nil, fmt.Errorf("outbox.ToEntity error: %w", err)
+		}
+		result = append(result, entity)
+	}
+
+	return result, nil
+}
+
+// HasEvent reports whether the wallet event with the given sequence is
+// still in the outbox, i.e. was not purged after publishing.
+func (r *Repo) HasEvent(ctx context.Context, walletID string, sequence int64) (bool, error) {
+	var dto OutboxEvent
+
+	err := transactor.DB(ctx, r.db).
+		Select("sequence").
+		Where("wallet_id = ? AND sequence = ?", walletID, sequence).
+		Take(&dto).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("db.Take error: %w", err)
+	}
+	return true, nil
+}
+
+// LastSequence returns the sequence of the wallet's latest event, or 0 when
+// it has none.
+func (r *Repo) LastSequence(ctx context.Context, walletID string) (int64, error) {
+	var sequence int64
+
+	err := transactor.DB(ctx, r.db).
+		Model(&OutboxEvent{}).
+		Select("COALESCE(MAX(sequence), 0)").
+		Where("wallet_id = ?", walletID).
+		Scan(&sequence).Error
+	if err != nil {
+		return 0, fmt.Errorf("db.Scan error: %w", err)
+	}
+	return sequence, nil
+}
+
+// ClaimUnpublished locks up to limit unpublished events of wallets other
+// than skipWallets in sequence order until the end of the transaction.
+// Locked rows are waited for rather than skipped, so that two relays never
+// publish the events of one wallet out of order.
+func (r *Repo) ClaimUnpublished(ctx context.Context, limit int, skipWallets []string) ([]entities.WalletEvent, error) {
+	var dtos []OutboxEvent
+
+	query := transactor.DB(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("published_at IS NULL")
+	if len(skipWallets) > 0 {
+		query = query.Where("wallet_id NOT IN ?", skipWallets)
+	}
+	err := query.
+		Order("sequence").
+		Limit(limit).
+		Find(&dtos).Error
+	if err != nil {
+		return nil, fmt.Errorf("db.Find error: %w", err)
+	}
+
+	result := make([]entities.WalletEvent, 0, len(dtos))
+	for _, dto := range dtos {
+		entity, err := ToEntity(dto)
+		if err != nil {
+			return nil, fmt.Errorf("outbox.ToEntity error: %w", err)
+		}
+		result = append(result, entity)
+	}
+
+	return result, nil
+}
+
+func (r *Repo) MarkPublished(ctx context.Context, sequences []int64, now time.Time) error {
+	err := transactor.DB(ctx, r.db).
+		Model(&OutboxEvent{}).
+		Where("sequence IN ?", sequences).
+		Updates(map[string]any{
+			"published_at": now,
+			"attempts":     gorm.Expr("attempts + 1"),
+			"last_error":   nil,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("db.Updates error: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed records a failed publishing attempt; the event stays
+// unpublished.
+func (r *Repo) MarkFailed(ctx context.Context, sequence int64, reason string) error {
+	err := transactor.DB(ctx, r.db).
+		Model(&OutboxEvent{}).
+		Where("sequence = ?", sequence).
+		Updates(map[string]any{
+			"attempts":   gorm.Expr("attempts + 1"),
+			"last_error": reason,
+		}).Error
+	if err != nil {
+		return fmt.Errorf("db.Updates error: %w", err)
+	}
+	return nil
+}
+
+// DeletePublished removes events published before the given time.
+func (r *Repo) DeletePublished(ctx context.Context, before time.Time) (int64, error) {
+	result := transactor.DB(ctx, r.db).
+		Where("published_at < ?", before).
+		Delete(&OutboxEvent{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("db.Delete error: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,113 @@
+package schedule
+
+import (
+	"TestProject/source/internal/entities"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type Schedule struct {
+	ID            string          `gorm:"primaryKey;type:uuid"`
+	WalletID      string          `gorm:"type:uuid;not null;index"`
+	OperationType string          `gorm:"type:varchar(32);not null"`
+	Currency      string          `gorm:"type:char(3);not null"`
+	Amount        decimal.Decimal `gorm:"type:decimal(16,3);not null"`
+	Frequency     string          `gorm:"type:varchar(16);not null"`
+	StartAt       time.Time       `gorm:"not null"`
+	EndAt         *time.Time
+	Occurrence    int       `gorm:"not null;default:0"`
+	NextRunAt     time.Time `gorm:"not null;index:idx_schedules_status_next_run,priority:2"`
+	Status        string    `gorm:"type:varchar(16);not null;index:idx_schedules_status_next_run,priority:1"`
+	CreatedAt     time.Time `gorm:"not null"`
+	UpdatedAt     time.Time `gorm:"not null"`
+}
+
+// ScheduleRun is unique per occurrence, so an occurrence can never be
+// recorded twice.
+type ScheduleRun struct {
+	ID            string    `gorm:"primaryKey;type:uuid"`
+	ScheduleID    string    `gorm:"type:uuid;not null;uniqueIndex:idx_schedule_runs_occurrence,priority:1"`
+	Occurrence    int       `gorm:"not null;uniqueIndex:idx_schedule_runs_occurrence,priority:2"`
+	ScheduledAt   time.Time `gorm:"not null"`
+	Status        string    `gorm:"type:varchar(16);not null"`
+	TransactionID *string   `gorm:"type:uuid"`
+	Error         *string   `gorm:"type:text"`
+	CreatedAt     time.Time `gorm:"not null"`
+}
+
+func FromEntity(entity entities.Schedule) (Schedule, error) {
+	return Schedule{
+		ID:            entity.ID,
+		WalletID:      entity.WalletId,
+		OperationType: entity.OperationType,
+		Currency:      entity.Currency,
+		Amount:        entity.Amount,
+		Frequency:     entity.Frequency,
+		StartAt:       entity.StartAt,
+		EndAt:         entity.EndAt,
+		Occurrence:    entity.Occurrence,
+		NextRunAt:     entity.NextRunAt,
+		Status:        entity.Status,
+		CreatedAt:     entity.CreatedAt,
+		UpdatedAt:     entity.UpdatedAt,
+	}, nil
+}
+
+func ToEntity(dto Schedule) (entities.Schedule, error) {
+	return entities.Schedule{
+		ID:            dto.ID,
+		WalletId:      dto.WalletID,
+		OperationType: dto.OperationType,
+		Currency:      dto.Currency,
+		Amount:        dto.Amount,
+		Frequency:     dto.Frequency,
+		StartAt:       dto.StartAt.UTC(),
+		EndAt:         dto.EndAt,
+		Occurrence:    dto.Occurrence,
+		NextRunAt:     dto.NextRunAt.UTC(),
+		Status:        dto.Status,
+		CreatedAt:     dto.CreatedAt,
+		UpdatedAt:     dto.UpdatedAt,
+	}, nil
+}
+
+func RunFromEntity(entity entities.ScheduleRun) (ScheduleRun, error) {
+	return ScheduleRun{
+		ID:            entity.ID,
+		ScheduleID:    entity.ScheduleId,
+		Occurrence:    entity.Occurrence,
+		ScheduledAt:   entity.ScheduledAt,
+		Status:        entity.Status,
+		TransactionID: nullableString(entity.TransactionId),
+		Error:         nullableString(entity.Error),
+		CreatedAt:     entity.CreatedAt,
+	}, nil
+}
+
+func RunToEntity(dto ScheduleRun) (entities.ScheduleRun, error) {
+	return entities.ScheduleRun{
+		ID:            dto.ID,
+		ScheduleId:    dto.ScheduleID,
+		Occurrence:    dto.Occurrence,
+		ScheduledAt:   dto.ScheduledAt,
+		Status:        dto.Status,
+		TransactionId: stringValue(dto.TransactionID),
+		Error:         stringValue(dto.Error),
+		CreatedAt:     dto.CreatedAt,
+	}, nil
+}
+
+func nullableString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
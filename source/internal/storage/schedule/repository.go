@@ -0,0 +1,153 @@
+package schedule
+
+import (
+	"TestProject/source/internal/entities"
+	"TestProject/source/internal/storage/transactor"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repo struct {
+	db *gorm.DB
+}
+
+func NewRepo(db *gorm.DB) *Repo {
+	return &Repo{db: db}
+}
+
+func (r *Repo) Create(ctx context.Context, schedule entities.Schedule) (entities.Schedule, error) {
+	dto, err := FromEntity(schedule)
+	if err != nil {
+		return entities.Schedule{}, fmt.Errorf("schedule from entity error: %w", err)
+	}
+
+	if err := transactor.DB(ctx, r.db).Create(&dto).Error; err != nil {
+		return entities.Schedule{}, fmt.Errorf("failed to create schedule: %w", err)
+	}
+
+	entity, err := ToEntity(dto)
+	if err != nil {
+		return entities.Schedule{}, fmt.Errorf("schedule to entity error: %w", err)
+	}
+	return entity, nil
+}
+
+func (r *Repo) GetByID(ctx context.Context, scheduleID string) (entities.Schedule, error) {
+	return r.get(transactor.DB(ctx, r.db), scheduleID)
+}
+
+// GetByIDForUpdate locks the schedule until the end of the transaction. It
+// waits for a worker that is running the schedule.
+func (r *Repo) GetByIDForUpdate(ctx context.Context, scheduleID string) (entities.Schedule, error) {
+	return r.get(transactor.DB(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}), scheduleID)
+}
+
+func (r *Repo) get(db *gorm.DB, scheduleID string) (entities.Schedule, error) {
+	var dto Schedule
+
+	err := db.Where("id = ?", scheduleID).First(&dto).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = entities.ErrScheduleNotFound
+		}
+		return entities.Schedule{}, fmt.Errorf("db.First error: %w", err)
+	}
+
+	entity, err := ToEntity(dto)
+	if err != nil {
+		return entities.Schedule{}, fmt.Errorf("schedule.ToEntity error: %w", err)
+	}
+
+	return entity, nil
+}
+
+// ClaimDue locks the active schedule that has been due the longest.
+// Schedules locked by other workers are skipped, so replicas never run the
+// same schedule at once. It returns ErrScheduleNotFound when nothing is due.
+func (r *Repo) ClaimDue(ctx context.Context, now time.Time) (entities.Schedule, error) {
+	var dto Schedule
+
+	err := transactor.DB(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ? AND next_run_at <= ?", entities.ScheduleActive, now).
+		Order("next_run_at").
+		First(&dto).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = entities.ErrScheduleNotFound
+		}
+		return entities.Schedule{}, fmt.Errorf("db.First error: %w", err)
+	}
+
+	entity, err := ToEntity(dto)
+	if err != nil {
+		return entities.Schedule{}, fmt.Errorf("schedule.ToEntity error: %w", err)
+	}
+
+	return entity, nil
+}
+
+func (r *Repo) Update(ctx context.Context, schedule entities.Schedule) (entities.Schedule, error) {
+	dto, err := FromEntity(schedule)
+	if err != nil {
+		return entities.Schedule{}, fmt.Errorf("schedule from entity error: %w", err)
+	}
+
+	err = transactor.DB(ctx, r.db).Save(&dto).Error
+	if err != nil {
+		return entities.Schedule{}, fmt.Errorf("db.Save error: %w", err)
+	}
+
+	entity, err := ToEntity(dto)
+	if err != nil {
+		return entities.Schedule{}, fmt.Errorf("schedule to entity error: %w", err)
+	}
+	return entity, nil
+}
+
+func (r *Repo) CreateRun(ctx context.Context, run entities.ScheduleRun) (entities.ScheduleRun, error) {
+	dto, err := RunFromEntity(run)
+	if err != nil {
+		return entities.ScheduleRun{}, fmt.Errorf("schedule run from entity error: %w", err)
+	}
+
+	if err := transactor.DB(ctx, r.db).Create(&dto).Error; err != nil {
+		return entities.ScheduleRun{}, fmt.Errorf("failed to create schedule run: %w", err)
+	}
+
+	entity, err := RunToEntity(dto)
+	if err != nil {
+		return entities.ScheduleRun{}, fmt.Errorf("schedule run to entity error: %w", err)
+	}
+	return entity, nil
+}
+
+// ListRuns returns the schedule's runs, newest first.
+func (r *Repo) ListRuns(ctx context.Context, scheduleID string, limit int) ([]entities.ScheduleRun, error) {
+	var dtos []ScheduleRun
+
+	err := transactor.DB(ctx, r.db).
+		Where("schedule_id = ?", scheduleID).
+		Order("occurrence DESC").
+		Limit(limit).
+		Find(&dtos).Error
+	if err != nil {
+		return nil, fmt.Errorf("db.Find error: %w", err)
+	}
+
+	result := make([]entities.ScheduleRun, 0, len(dtos))
+	for _, dto := range dtos {
+		entity, err := RunToEntity(dto)
+		if err != nil {
+			return nil, fmt.Errorf("schedule run to entity error: %w", err)
+		}
+		result = append(result, entity)
+	}
+
+	return result, nil
+}
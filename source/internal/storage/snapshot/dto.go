@@ -0,0 +1,32 @@
+package snapshot
+
+import (
+	"TestProject/source/internal/entities"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// BalanceSnapshot is the wallet balance at the end of Day.
+type BalanceSnapshot struct {
+	WalletID  string          `gorm:"primaryKey;type:uuid"`
+	Day       time.Time       `gorm:"primaryKey"`
+	Balance   decimal.Decimal `gorm:"type:decimal(16,3);not null"`
+	CreatedAt time.Time       `gorm:"not null"`
+}
+
+// SnapshotDay marks a day whose snapshots have been taken, including days
+// without any postings.
+type SnapshotDay struct {
+	Day       time.Time `gorm:"primaryKey"`
+	Wallets   int64     `gorm:"not null"`
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+func ToEntity(dto BalanceSnapshot) (entities.BalanceSnapshot, error) {
+	return entities.BalanceSnapshot{
+		WalletId: dto.WalletID,
+		Day:      dto.Day.UTC(),
+		Balance:  dto.Balance,
+	}, nil
+}
@@ -0,0 +1,121 @@
+package snapshot
+
+import (
+	"TestProject/source/internal/entities"
+	"TestProject/source/internal/storage/transactor"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repo struct {
+	db *gorm.DB
+}
+
+func NewRepo(db *gorm.DB) *Repo {
+	return &Repo{db: db}
+}
+
+// takeDaySQL stores the end-of-day balance of every wallet with postings
+// created in [@from, @to). The balance is the previous snapshot of the
+// wallet plus those postings; the first day ever taken passes no @from and
+// sums the whole journal instead.
+const takeDaySQL = `
+INSERT INTO balance_snapshots (wallet_id, day, balance, created_at)
+SELECT d.account_id::uuid, @day, COALESCE(prev.balance, 0) + d.amount, @now
+FROM (
+	SELECT account_id, SUM(amount) AS amount
+	FROM postings
+	WHERE account_type = @account_type AND created_at < @to %s
+	GROUP BY account_id
+) AS d
+LEFT JOIN LATERAL (
+	SELECT s.balance
+	FROM balance_snapshots AS s
+	WHERE s.wallet_id = d.account_id::uuid AND s.day < @day
+	ORDER BY s.day DESC
+	LIMIT 1
+) AS prev ON true
+ON CONFLICT (wallet_id, day) DO NOTHING`
+
+// TakeDay stores the snapshots of day and marks it as taken, in one
+// transaction. Taking a day again is a no-op, so replicas may race on it.
+func (r *Repo) TakeDay(ctx context.Context, day, from time.Time) (int64, error) {
+	var taken int64
+
+	err := transactor.DB(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		params := map[string]any{
+			"day":          day,
+			"to":           day.AddDate(0, 0, 1),
+			"now":          time.Now().UTC(),
+			"account_type": entities.AccountWallet,
+		}
+		lowerBound := ""
+		if !from.IsZero() {
+			params["from"] = from
+			lowerBound = "AND created_at >= @from"
+		}
+
+		result := tx.Exec(fmt.Sprintf(takeDaySQL, lowerBound), params)
+		if result.Error != nil {
+			return fmt.Errorf("db.Exec error: %w", result.Error)
+		}
+		taken = result.RowsAffected
+
+		marker := SnapshotDay{Day: day, Wallets: taken, CreatedAt: time.Now().UTC()}
+		err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&marker).Error
+		if err != nil {
+			return fmt.Errorf("db.Create error: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return taken, nil
+}
+
+// LastDay returns the latest day whose snapshots have been taken.
+func (r *Repo) LastDay(ctx context.Context) (time.Time, error) {
+	var marker SnapshotDay
+
+	err := transactor.DB(ctx, r.db).Order("day DESC").First(&marker).Error
+	if err != nil {
+		return time.Time{}, fmt.Errorf("db.First error: %w", notFound(err))
+	}
+
+	return marker.Day.UTC(), nil
+}
+
+// Latest returns the newest snapshot of the wallet that ends no later than
+// before.
+func (r *Repo) Latest(ctx context.Context, walletID string, before time.Time) (entities.BalanceSnapshot, error) {
+	var dto BalanceSnapshot
+
+	err := transactor.DB(ctx, r.db).
+		Where("wallet_id = ? AND day <= ?", walletID, before.AddDate(0, 0, -1)).
+		Order("day DESC").
+		First(&dto).Error
+	if err != nil {
+		return entities.BalanceSnapshot{}, fmt.Errorf("db.First error: %w", notFound(err))
+	}
+
+	entity, err := ToEntity(dto)
+	if err != nil {
+		return entities.BalanceSnapshot{}, fmt.Errorf("snapshot.ToEntity error: %w", err)
+	}
+
+	return entity, nil
+}
+
+func notFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return entities.ErrSnapshotNotFound
+	}
+	return err
+}
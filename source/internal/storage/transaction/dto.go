@@ -0,0 +1,66 @@
+package transaction
+
+import (
+	"TestProject/source/internal/entities"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Transaction is a row of the append-only transactions ledger.
+type Transaction struct {
+	ID            string          `gorm:"primaryKey;type:uuid"`
+	WalletID      string          `gorm:"type:uuid;not null;index:idx_transactions_wallet_created,priority:1"`
+	OperationType string          `gorm:"type:varchar(32);not null"`
+	Currency      string          `gorm:"type:char(3);default:'RUB';not null"`
+	Amount        decimal.Decimal `gorm:"type:decimal(16,3);not null"`
+	Fee           decimal.Decimal `gorm:"type:decimal(16,3);default:0;not null"`
+	BalanceBefore decimal.Decimal `gorm:"type:decimal(16,3);not null"`
+	BalanceAfter  decimal.Decimal `gorm:"type:decimal(16,3);not null"`
+	ReferenceID   *string         `gorm:"type:uuid;index"`
+	CreatedAt     time.Time       `gorm:"not null;index:idx_transactions_wallet_created,priority:2,sort:desc"`
+}
+
+func FromEntity(entity entities.Transaction) (Transaction, error) {
+	return Transaction{
+		ID:            entity.ID,
+		WalletID:      entity.WalletId,
+		OperationType: entity.OperationType,
+		Currency:      entity.Currency,
+		Amount:        entity.Amount,
+		Fee:           entity.Fee,
+		BalanceBefore: entity.BalanceBefore,
+		BalanceAfter:  entity.Balance,
+		ReferenceID:   nullableString(entity.ReferenceId),
+		CreatedAt:     entity.CreatedAt,
+	}, nil
+}
+
+func ToEntity(dto Transaction) (entities.Transaction, error) {
+	return entities.Transaction{
+		ID:            dto.ID,
+		WalletId:      dto.WalletID,
+		OperationType: dto.OperationType,
+		Currency:      dto.Currency,
+		Amount:        dto.Amount,
+		Fee:           dto.Fee,
+		BalanceBefore: dto.BalanceBefore,
+		Balance:       dto.BalanceAfter,
+		ReferenceId:   stringValue(dto.ReferenceID),
+		CreatedAt:     dto.CreatedAt,
+	}, nil
+}
+
+func nullableString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
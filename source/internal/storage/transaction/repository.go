@@ -0,0 +1,179 @@
+package transaction
+
+import (
+	"TestProject/source/internal/entities"
+	"TestProject/source/internal/storage/transactor"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// Repo gives access to the transactions ledger. Rows are only ever inserted:
+// there is deliberately no update or delete.
+type Repo struct {
+	db *gorm.DB
+}
+
+func NewRepo(db *gorm.DB) *Repo {
+	return &Repo{db: db}
+}
+
+func (r *Repo) Create(ctx context.Context, transaction entities.Transaction) (entities.Transaction, error) {
+	dto, err := FromEntity(transaction)
+	if err != nil {
+		return entities.Transaction{}, fmt.Errorf("transaction from entity error: %w", err)
+	}
+
+	if err := transactor.DB(ctx, r.db).Create(&dto).Error; err != nil {
+		return entities.Transaction{}, fmt.Errorf("failed to create transaction: %w", err)
+	}
+
+	entity, err := ToEntity(dto)
+	if err != nil {
+		return entities.Transaction{}, fmt.Errorf("transaction to entity error: %w", err)
+	}
+	return entity, nil
+}
+
+func (r *Repo) GetByID(ctx context.Context, transactionID string) (entities.Transaction, error) {
+	var dto Transaction
+
+	err := transactor.DB(ctx, r.db).Where("id = ?", transactionID).First(&dto).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = entities.ErrTransactionNotFound
+		}
+		return entities.Transaction{}, fmt.Errorf("db.First error: %w", err)
+	}
+
+	entity, err := ToEntity(dto)
+	if err != nil {
+		return entities.Transaction{}, fmt.Errorf("transaction.ToEntity error: %w", err)
+	}
+
+	return entity, nil
+}
+
+// SumByReference adds up the amounts of entries of the given operation type
+// that reference referenceID.
+func (r *Repo) SumByReference(ctx context.Context, referenceID, operationType string) (decimal.Decimal, error) {
+	var sum decimal.Decimal
+
+	err := transactor.DB(ctx, r.db).
+		Model(&Transaction{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("reference_id = ? AND operation_type = ?", referenceID, operationType).
+		Row().
+		Scan(&sum)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("row.Scan error: %w", err)
+	}
+
+	return sum, nil
+}
+
+// Usage sums and counts the wallet's transactions of the given types created
+// at or after since.
+func (r *Repo) Usage(ctx context.Context, walletID string, operationTypes []string, since time.Time) (entities.Usage, error) {
+	var usage entities.Usage
+
+	err := transactor.DB(ctx, r.db).
+		Model(&Transaction{}).
+		Select("COALESCE(SUM(amount), 0), COUNT(*)").
+		Where("wallet_id = ? AND operation_type IN ? AND created_at >= ?", walletID, operationTypes, since).
+		Row().
+		Scan(&usage.Amount, &usage.Count)
+	if err != nil {
+		return entities.Usage{}, fmt.Errorf("row.Scan error: %w", err)
+	}
+
+	return usage, nil
+}
+
+// List returns the wallet's transactions that match the filter, newest first.
+func (r *Repo) List(ctx context.Context, filter entities.TransactionFilter) ([]entities.Transaction, error) {
+	query := transactor.DB(ctx, r.db).Where("wallet_id = ?", filter.WalletId)
+
+	if filter.OperationType != "" {
+		query = query.Where("operation_type = ?", filter.OperationType)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+	if filter.MinAmount != nil {
+		query = query.Where("amount >= ?", *filter.MinAmount)
+	}
+	if filter.MaxAmount != nil {
+		query = query.Where("amount <= ?", *filter.MaxAmount)
+	}
+	if filter.Cursor != nil {
+		query = query.Where("(created_at, id) < (?, ?)", filter.Cursor.CreatedAt, filter.Cursor.ID)
+	}
+
+	var dtos []Transaction
+	err := query.
+		Order("created_at DESC, id DESC").
+		Limit(filter.Limit).
+		Find(&dtos).Error
+	if err != nil {
+		return nil, fmt.Errorf("db.Find error: %w", err)
+	}
+
+	result := make([]entities.Transaction, 0, len(dtos))
+	for _, dto := range dtos {
+		entity, err := ToEntity(dto)
+		if err != nil {
+			return nil, fmt.Errorf("transaction.ToEntity error: %w", err)
+		}
+		result = append(result, entity)
+	}
+
+	return result, nil
+}
+
+// Stream calls fn for each transaction of the wallet created in [from, to),
+// oldest first. Rows are read from the database cursor one at a time, so
+// the result is never loaded into memory as a whole.
+func (r *Repo) Stream(ctx context.Context, walletID string, from, to time.Time, fn func(entities.Transaction) error) error {
+	db := transactor.DB(ctx, r.db)
+
+	rows, err := db.
+		Model(&Transaction{}).
+		Where("wallet_id = ? AND created_at >= ? AND created_at < ?", walletID, from, to).
+		Order("created_at, id").
+		Rows()
+	if err != nil {
+		return fmt.Errorf("db.Rows error: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var dto Transaction
+		err = db.ScanRows(rows, &dto)
+		if err != nil {
+			return fmt.Errorf("db.ScanRows error: %w", err)
+		}
+
+		entity, err := ToEntity(dto)
+		if err != nil {
+			return fmt.Errorf("transaction.ToEntity error: %w", err)
+		}
+
+		err = fn(entity)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("rows.Err error: %w", err)
+	}
+	return nil
+}
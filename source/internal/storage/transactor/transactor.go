@@ -0,0 +1,59 @@
+package transactor
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type txKey struct{}
+
+// Transactor runs several repository calls inside one database transaction.
+// Repositories pick the transaction up from the context through DB.
+type Transactor struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) *Transactor {
+	return &Transactor{db: db}
+}
+
+// WithinTransaction calls fn with a context bound to a database transaction.
+// If ctx is already bound to one, fn joins it instead of opening a new one.
+func (t *Transactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+
+	return t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// WithinSavepoint calls fn in a savepoint of the transaction bound to ctx,
+// so that a failed fn is rolled back alone and the transaction goes on.
+// Without a bound transaction it is WithinTransaction.
+func (t *Transactor) WithinSavepoint(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, ok := ctx.Value(txKey{}).(*gorm.DB)
+	if !ok {
+		return t.WithinTransaction(ctx, fn)
+	}
+
+	return tx.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// InTransaction reports whether ctx is bound to a database transaction.
+func (t *Transactor) InTransaction(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(*gorm.DB)
+	return ok
+}
+
+// DB returns the transaction bound to ctx, or db when there is none.
+func DB(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
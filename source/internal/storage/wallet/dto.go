@@ -2,25 +2,69 @@ package wallet
 
 import (
 	"TestProject/source/internal/entities"
+	"time"
 
 	"github.com/shopspring/decimal"
 )
 
 type Wallet struct {
-	ID      string          `gorm:"primaryKey;type:uuid"`
-	Balance decimal.Decimal `gorm:"type:decimal(15,2);default:0;not null"`
+	ID              string          `gorm:"primaryKey;type:uuid"`
+	OwnerID         *string         `gorm:"type:uuid;index"`
+	Currency        string          `gorm:"type:char(3);default:'RUB';not null"`
+	Balance         decimal.Decimal `gorm:"type:decimal(16,3);default:0;not null;check:chk_wallets_balance_floor,balance >= -overdraft_limit"`
+	Reserved        decimal.Decimal `gorm:"type:decimal(16,3);default:0;not null"`
+	OverdraftLimit  decimal.Decimal `gorm:"type:decimal(16,3);default:0;not null;check:chk_wallets_overdraft_limit,overdraft_limit >= 0"`
+	Status          string          `gorm:"type:varchar(16);default:'ACTIVE';not null"`
+	FreezeMode      *string         `gorm:"type:varchar(8)"`
+	StatusReason    *string         `gorm:"type:varchar(64)"`
+	StatusChangedAt *time.Time
 }
 
 func FromEntity(entity entities.Wallet) (Wallet, error) {
+	status := entity.Status
+	if status == "" {
+		status = entities.WalletActive
+	}
+
 	return Wallet{
-		ID:      entity.ID,
-		Balance: entity.Balance,
+		ID:              entity.ID,
+		OwnerID:         nullableString(entity.OwnerId),
+		Currency:        entity.Currency,
+		Balance:         entity.Balance,
+		Reserved:        entity.Reserved,
+		OverdraftLimit:  entity.OverdraftLimit,
+		Status:          status,
+		FreezeMode:      nullableString(entity.FreezeMode),
+		StatusReason:    nullableString(entity.StatusReason),
+		StatusChangedAt: entity.StatusChangedAt,
 	}, nil
 }
 
 func ToEntity(dto Wallet) (entities.Wallet, error) {
 	return entities.Wallet{
-		ID:      dto.ID,
-		Balance: dto.Balance,
+		ID:              dto.ID,
+		OwnerId:         stringValue(dto.OwnerID),
+		Currency:        dto.Currency,
+		Balance:         dto.Balance,
+		Reserved:        dto.Reserved,
+		OverdraftLimit:  dto.OverdraftLimit,
+		Status:          dto.Status,
+		FreezeMode:      stringValue(dto.FreezeMode),
+		StatusReason:    stringValue(dto.StatusReason),
+		StatusChangedAt: dto.StatusChangedAt,
 	}, nil
 }
+
+func nullableString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -2,9 +2,13 @@ package wallet
 
 import (
 	"TestProject/source/internal/entities"
+	"TestProject/source/internal/storage/transactor"
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
@@ -24,7 +28,7 @@ func (r *Repo) Create(ctx context.Context, wallet entities.Wallet) (entities.Wal
 
 	}
 
-	if err := r.db.WithContext(ctx).Create(&dto).Error; err != nil {
+	if err := transactor.DB(ctx, r.db).Create(&dto).Error; err != nil {
 		return entities.Wallet{}, fmt.Errorf("failed to create wallet: %w", err)
 	}
 
@@ -39,9 +43,9 @@ func (r *Repo) Create(ctx context.Context, wallet entities.Wallet) (entities.Wal
 func (r *Repo) GetByID(ctx context.Context, walletID string) (entities.Wallet, error) {
 	var dto Wallet
 
-	err := r.db.WithContext(ctx).Where("id = ?", walletID).First(&dto).Error
+	err := transactor.DB(ctx, r.db).Where("id = ?", walletID).First(&dto).Error
 	if err != nil {
-		return entities.Wallet{}, fmt.Errorf("db.First error: %w", err)
+		return entities.Wallet{}, fmt.Errorf("db.First error: %w", notFound(err))
 	}
 
 	entity, err := ToEntity(dto)
@@ -56,12 +60,33 @@ func (r *Repo) GetByID(ctx context.Context, walletID string) (entities.Wallet, e
 func (r *Repo) GetByIDForUpdate(ctx context.Context, walletID string) (entities.Wallet, error) {
 	var dto Wallet
 
-	err := r.db.WithContext(ctx).
+	err := transactor.DB(ctx, r.db).
 		Clauses(clause.Locking{Strength: "UPDATE"}).
 		Where("id = ?", walletID).
 		First(&dto).Error
 	if err != nil {
-		return entities.Wallet{}, fmt.Errorf("db.First error: %w", err)
+		return entities.Wallet{}, fmt.Errorf("db.First error: %w", notFound(err))
+	}
+
+	entity, err := ToEntity(dto)
+	if err != nil {
+		return entities.Wallet{}, fmt.Errorf("wallet.ToEntity error: %w", err)
+	}
+
+	return entity, nil
+}
+
+// GetByIDForShare reads the wallet under a shared row lock: writers wait
+// until the transaction ends, other readers do not.
+func (r *Repo) GetByIDForShare(ctx context.Context, walletID string) (entities.Wallet, error) {
+	var dto Wallet
+
+	err := transactor.DB(ctx, r.db).
+		Clauses(clause.Locking{Strength: "SHARE"}).
+		Where("id = ?", walletID).
+		First(&dto).Error
+	if err != nil {
+		return entities.Wallet{}, fmt.Errorf("db.First error: %w", notFound(err))
 	}
 
 	entity, err := ToEntity(dto)
@@ -75,14 +100,14 @@ func (r *Repo) GetByIDForUpdate(ctx context.Context, walletID string) (entities.
 func (r *Repo) UpdateWithLock(ctx context.Context, walletID string, updateFn func(*entities.Wallet) error) (entities.Wallet, error) {
 	var result entities.Wallet
 
-	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	err := transactor.DB(ctx, r.db).Transaction(func(tx *gorm.DB) error {
 		var dto Wallet
 		err := tx.
 			Clauses(clause.Locking{Strength: "UPDATE"}).
 			Where("id = ?", walletID).
 			First(&dto).Error
 		if err != nil {
-			return fmt.Errorf("db.First error: %w", err)
+			return fmt.Errorf("db.First error: %w", notFound(err))
 		}
 
 		entity, err := ToEntity(dto)
@@ -102,7 +127,7 @@ func (r *Repo) UpdateWithLock(ctx context.Context, walletID string, updateFn fun
 
 		err = tx.Save(&dto).Error
 		if err != nil {
-			return fmt.Errorf("db.Save error: %w", err)
+			return fmt.Errorf("db.Save error: %w", balanceFloor(err))
 		}
 
 		result = entity
@@ -122,9 +147,9 @@ func (r *Repo) Update(ctx context.Context, wallet entities.Wallet) (entities.Wal
 		return entities.Wallet{}, fmt.Errorf("wallet from entity error: %w", err)
 	}
 
-	err = r.db.WithContext(ctx).Save(&dto).Error
+	err = transactor.DB(ctx, r.db).Save(&dto).Error
 	if err != nil {
-		return entities.Wallet{}, fmt.Errorf("db.Save error: %w", err)
+		return entities.Wallet{}, fmt.Errorf("db.Save error: %w", balanceFloor(err))
 	}
 
 	entity, err := ToEntity(dto)
@@ -134,3 +159,122 @@ func (r *Repo) Update(ctx context.Context, wallet entities.Wallet) (entities.Wal
 
 	return entity, nil
 }
+
+func notFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return entities.ErrWalletNotFound
+	}
+	return err
+}
+
+// balanceFloor maps a violation of the overdraft floor constraint to
+// ErrInsufficientFunds. The application checks the floor first, so this only
+// fires for writes that bypass it.
+func balanceFloor(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.ConstraintName == "chk_wallets_balance_floor" {
+		return fmt.Errorf("%w: %s", entities.ErrInsufficientFunds, pgErr.Message)
+	}
+	return err
+}
+
+// UpdateManyWithLock locks all given wallets in one transaction and passes
+// them to updateFn keyed by ID. Row locks are always taken in ascending ID
+// order, so concurrent calls over overlapping wallets cannot deadlock.
+func (r *Repo) UpdateManyWithLock(ctx context.Context, walletIDs []string, updateFn func(map[string]*entities.Wallet) error) (map[string]entities.Wallet, error) {
+	ids := slices.Clone(walletIDs)
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+
+	result := make(map[string]entities.Wallet, len(ids))
+
+	err := transactor.DB(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		locked := make(map[string]*entities.Wallet, len(ids))
+
+		for _, id := range ids {
+			var dto Wallet
+			err := tx.
+				Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("id = ?", id).
+				First(&dto).Error
+			if err != nil {
+				return fmt.Errorf("db.First error: %w", notFound(err))
+			}
+
+			entity, err := ToEntity(dto)
+			if err != nil {
+				return fmt.Errorf("wallet.ToEntity error: %w", err)
+			}
+			locked[id] = &entity
+		}
+
+		err := updateFn(locked)
+		if err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			dto, err := FromEntity(*locked[id])
+			if err != nil {
+				return fmt.Errorf("wallet from entity error: %w", err)
+			}
+
+			err = tx.Save(&dto).Error
+			if err != nil {
+				return fmt.Errorf("db.Save error: %w", balanceFloor(err))
+			}
+
+			result[id] = *locked[id]
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// LockMany takes row locks on the given wallets in ascending ID order and
+// keeps them until the surrounding transaction ends, so later
+// UpdateWithLock calls on these wallets cannot deadlock with each other.
+// It is only useful inside a transaction. Missing wallets are skipped and
+// fail later, in the operation that uses them.
+func (r *Repo) LockMany(ctx context.Context, walletIDs []string) error {
+	ids := slices.Clone(walletIDs)
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+
+	err := transactor.DB(ctx, r.db).
+		Exec("SELECT id FROM wallets WHERE id IN ? ORDER BY id FOR UPDATE", ids).Error
+	if err != nil {
+		return fmt.Errorf("db.Exec error: %w", err)
+	}
+
+	return nil
+}
+
+// ListByOwner returns the customer's wallets ordered by ID.
+func (r *Repo) ListByOwner(ctx context.Context, ownerID string) ([]entities.Wallet, error) {
+	var dtos []Wallet
+
+	err := transactor.DB(ctx, r.db).
+		Where("owner_id = ?", ownerID).
+		Order("id").
+		Find(&dtos).Error
+	if err != nil {
+		return nil, fmt.Errorf("db.Find error: %w", err)
+	}
+
+	result := make([]entities.Wallet, 0, len(dtos))
+	for _, dto := range dtos {
+		entity, err := ToEntity(dto)
+		if err != nil {
+			return nil, fmt.Errorf("wallet.ToEntity error: %w", err)
+		}
+		result = append(result, entity)
+	}
+
+	return result, nil
+}
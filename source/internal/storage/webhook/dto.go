@@ -0,0 +1,113 @@
+package webhook
+
+import (
+	"TestProject/source/internal/entities"
+	"time"
+)
+
+// Webhook.WalletID is NULL for webhooks that receive the events of every
+// wallet.
+type Webhook struct {
+	ID         string            `gorm:"primaryKey;type:uuid"`
+	WalletID   *string           `gorm:"type:uuid;index"`
+	URL        string            `gorm:"type:text;not null"`
+	Secret     string            `gorm:"type:varchar(128);not null"`
+	Deliveries []WebhookDelivery `gorm:"foreignKey:WebhookID;constraint:OnDelete:CASCADE"`
+	CreatedAt  time.Time         `gorm:"not null"`
+}
+
+type WebhookDelivery struct {
+	ID             string    `gorm:"primaryKey;type:uuid"`
+	WebhookID      string    `gorm:"type:uuid;not null;uniqueIndex:idx_webhook_deliveries_event,priority:1"`
+	EventID        string    `gorm:"type:uuid;not null;uniqueIndex:idx_webhook_deliveries_event,priority:2"`
+	EventType      string    `gorm:"type:varchar(32);not null"`
+	WalletID       string    `gorm:"type:uuid;not null"`
+	Payload        []byte    `gorm:"type:bytea;not null"`
+	Status         string    `gorm:"type:varchar(16);not null;index:idx_webhook_deliveries_due,priority:1"`
+	Attempts       int       `gorm:"not null;default:0"`
+	NextAttemptAt  time.Time `gorm:"not null;index:idx_webhook_deliveries_due,priority:2"`
+	LastStatusCode *int
+	LastError      *string `gorm:"type:text"`
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time `gorm:"not null;index:idx_webhook_deliveries_created_at"`
+	UpdatedAt      time.Time `gorm:"not null"`
+}
+
+func FromEntity(entity entities.Webhook) (Webhook, error) {
+	return Webhook{
+		ID:        entity.ID,
+		WalletID:  nullableString(entity.WalletId),
+		URL:       entity.URL,
+		Secret:    entity.Secret,
+		CreatedAt: entity.CreatedAt,
+	}, nil
+}
+
+func ToEntity(dto Webhook) (entities.Webhook, error) {
+	return entities.Webhook{
+		ID:        dto.ID,
+		WalletId:  stringValue(dto.WalletID),
+		URL:       dto.URL,
+		Secret:    dto.Secret,
+		CreatedAt: dto.CreatedAt,
+	}, nil
+}
+
+func DeliveryFromEntity(entity entities.WebhookDelivery) (WebhookDelivery, error) {
+	dto := WebhookDelivery{
+		ID:            entity.ID,
+		WebhookID:     entity.WebhookId,
+		EventID:       entity.EventId,
+		EventType:     entity.EventType,
+		WalletID:      entity.WalletId,
+		Payload:       entity.Payload,
+		Status:        entity.Status,
+		Attempts:      entity.Attempts,
+		NextAttemptAt: entity.NextAttemptAt,
+		LastError:     nullableString(entity.LastError),
+		DeliveredAt:   entity.DeliveredAt,
+		CreatedAt:     entity.CreatedAt,
+		UpdatedAt:     entity.UpdatedAt,
+	}
+	if entity.LastStatusCode != 0 {
+		code := entity.LastStatusCode
+		dto.LastStatusCode = &code
+	}
+	return dto, nil
+}
+
+func DeliveryToEntity(dto WebhookDelivery) (entities.WebhookDelivery, error) {
+	entity := entities.WebhookDelivery{
+		ID:            dto.ID,
+		WebhookId:     dto.WebhookID,
+		EventId:       dto.EventID,
+		EventType:     dto.EventType,
+		WalletId:      dto.WalletID,
+		Payload:       dto.Payload,
+		Status:        dto.Status,
+		Attempts:      dto.Attempts,
+		NextAttemptAt: dto.NextAttemptAt,
+		LastError:     stringValue(dto.LastError),
+		DeliveredAt:   dto.DeliveredAt,
+		CreatedAt:     dto.CreatedAt,
+		UpdatedAt:     dto.UpdatedAt,
+	}
+	if dto.LastStatusCode != nil {
+		entity.LastStatusCode = *dto.LastStatusCode
+	}
+	return entity, nil
+}
+
+func nullableString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
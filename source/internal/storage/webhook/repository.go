@@ -0,0 +1,226 @@
+package webhook
+
+import (
+	"TestProject/source/internal/entities"
+	"TestProject/source/internal/storage/transactor"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repo struct {
+	db *gorm.DB
+}
+
+func NewRepo(db *gorm.DB) *Repo {
+	return &Repo{db: db}
+}
+
+func (r *Repo) Create(ctx context.Context, webhook entities.Webhook) (entities.Webhook, error) {
+	dto, err := FromEntity(webhook)
+	if err != nil {
+		return entities.Webhook{}, fmt.Errorf("webhook from entity error: %w", err)
+	}
+
+	if err := transactor.DB(ctx, r.db).Create(&dto).Error; err != nil {
+		return entities.Webhook{}, fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	entity, err := ToEntity(dto)
+	if err != nil {
+		return entities.Webhook{}, fmt.Errorf("webhook to entity error: %w", err)
+	}
+	return entity, nil
+}
+
+func (r *Repo) GetByID(ctx context.Context, webhookID string) (entities.Webhook, error) {
+	var dto Webhook
+
+	err := transactor.DB(ctx, r.db).Where("id = ?", webhookID).First(&dto).Error
+	if err != nil {
+		return entities.Webhook{}, fmt.Errorf("db.First error: %w", notFound(err, entities.ErrWebhookNotFound))
+	}
+
+	entity, err := ToEntity(dto)
+	if err != nil {
+		return entities.Webhook{}, fmt.Errorf("webhook.ToEntity error: %w", err)
+	}
+	return entity, nil
+}
+
+// List returns the wallet's own webhooks, or the webhooks for every wallet
+// when walletID is empty, oldest first.
+func (r *Repo) List(ctx context.Context, walletID string) ([]entities.Webhook, error) {
+	query := transactor.DB(ctx, r.db).Order("created_at, id")
+	if walletID != "" {
+		query = query.Where("wallet_id = ?", walletID)
+	}
+	return r.find(query)
+}
+
+// ListForWallet returns the webhooks that receive the wallet's events: its
+// own and those for every wallet.
+func (r *Repo) ListForWallet(ctx context.Context, walletID string) ([]entities.Webhook, error) {
+	query := transactor.DB(ctx, r.db).
+		Where("wallet_id = ? OR wallet_id IS NULL", walletID).
+		Order("created_at, id")
+	return r.find(query)
+}
+
+func (r *Repo) find(query *gorm.DB) ([]entities.Webhook, error) {
+	var dtos []Webhook
+
+	err := query.Find(&dtos).Error
+	if err != nil {
+		return nil, fmt.Errorf("db.Find error: %w", err)
+	}
+
+	result := make([]entities.Webhook, 0, len(dtos))
+	for _, dto := range dtos {
+		entity, err := ToEntity(dto)
+		if err != nil {
+			return nil, fmt.Errorf("webhook.ToEntity error: %w", err)
+		}
+		result = append(result, entity)
+	}
+	return result, nil
+}
+
+// Delete removes the webhook together with its deliveries.
+func (r *Repo) Delete(ctx context.Context, webhookID string) error {
+	result := transactor.DB(ctx, r.db).Where("id = ?", webhookID).Delete(&Webhook{})
+	if result.Error != nil {
+		return fmt.Errorf("db.Delete error: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return entities.ErrWebhookNotFound
+	}
+	return nil
+}
+
+// CreateDeliveries skips deliveries of an event already queued for the
+// same webhook.
+func (r *Repo) CreateDeliveries(ctx context.Context, deliveries []entities.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	dtos := make([]WebhookDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		dto, err := DeliveryFromEntity(delivery)
+		if err != nil {
+			return fmt.Errorf("webhook delivery from entity error: %w", err)
+		}
+		dtos = append(dtos, dto)
+	}
+
+	err := transactor.DB(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(&dtos).Error
+	if err != nil {
+		return fmt.Errorf("db.Create error: %w", err)
+	}
+	return nil
+}
+
+// ClaimDueDelivery locks the pending delivery of a webhook other than
+// skipWebhooks that has waited longest past its next attempt. Deliveries
+// locked by another worker are skipped.
+func (r *Repo) ClaimDueDelivery(ctx context.Context, now time.Time, skipWebhooks []string) (entities.WebhookDelivery, error) {
+	var dto WebhookDelivery
+
+	query := transactor.DB(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ? AND next_attempt_at <= ?", entities.DeliveryPending, now)
+	if len(skipWebhooks) > 0 {
+		query = query.Where("webhook_id NOT IN ?", skipWebhooks)
+	}
+	err := query.
+		Order("next_attempt_at").
+		First(&dto).Error
+	if err != nil {
+		return entities.WebhookDelivery{}, fmt.Errorf("db.First error: %w", notFound(err, entities.ErrDeliveryNotFound))
+	}
+
+	entity, err := DeliveryToEntity(dto)
+	if err != nil {
+		return entities.WebhookDelivery{}, fmt.Errorf("webhook delivery to entity error: %w", err)
+	}
+	return entity, nil
+}
+
+// GetDeliveryForUpdate locks the delivery until the end of the transaction.
+func (r *Repo) GetDeliveryForUpdate(ctx context.Context, deliveryID string) (entities.WebhookDelivery, error) {
+	var dto WebhookDelivery
+
+	err := transactor.DB(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", deliveryID).
+		First(&dto).Error
+	if err != nil {
+		return entities.WebhookDelivery{}, fmt.Errorf("db.First error: %w", notFound(err, entities.ErrDeliveryNotFound))
+	}
+
+	entity, err := DeliveryToEntity(dto)
+	if err != nil {
+		return entities.WebhookDelivery{}, fmt.Errorf("webhook delivery to entity error: %w", err)
+	}
+	return entity, nil
+}
+
+func (r *Repo) UpdateDelivery(ctx context.Context, delivery entities.WebhookDelivery) (entities.WebhookDelivery, error) {
+	dto, err := DeliveryFromEntity(delivery)
+	if err != nil {
+		return entities.WebhookDelivery{}, fmt.Errorf("webhook delivery from entity error: %w", err)
+	}
+
+	result := transactor.DB(ctx, r.db).
+		Model(&WebhookDelivery{}).
+		Where("id = ?", dto.ID).
+		Select("status", "attempts", "next_attempt_at", "last_status_code", "last_error", "delivered_at", "updated_at").
+		Updates(&dto)
+	if result.Error != nil {
+		return entities.WebhookDelivery{}, fmt.Errorf("db.Updates error: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return entities.WebhookDelivery{}, entities.ErrDeliveryNotFound
+	}
+	return delivery, nil
+}
+
+// ListDeliveries returns the latest deliveries matching the filter, newest
+// first.
+func (r *Repo) ListDeliveries(ctx context.Context, filter entities.WebhookDeliveryFilter) ([]entities.WebhookDelivery, error) {
+	var dtos []WebhookDelivery
+
+	query := transactor.DB(ctx, r.db).Order("created_at DESC, id DESC").Limit(filter.Limit)
+	if filter.WebhookId != "" {
+		query = query.Where("webhook_id = ?", filter.WebhookId)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	err := query.Find(&dtos).Error
+	if err != nil {
+		return nil, fmt.Errorf("db.Find error: %w", err)
+	}
+
+	result := make([]entities.WebhookDelivery, 0, len(dtos))
+	for _, dto := range dtos {
+		entity, err := DeliveryToEntity(dto)
+		if err != nil {
+			return nil, fmt.Errorf("webhook delivery to entity error: %w", err)
+		}
+		result = append(result, entity)
+	}
+	return result, nil
+}
+
+func notFound(err error, notFoundErr error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return notFoundErr
+	}
+	return err
+}
@@ -0,0 +1,38 @@
+package grpcapi
+
+import (
+	walletv1 "TestProject/source/api/wallet/v1"
+	"TestProject/source/internal/entities"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Amounts are sent as decimal strings at the currency scale, the same way
+// the v2 REST API sends them.
+
+func WalletToProto(wallet entities.Wallet) *walletv1.Wallet {
+	return &walletv1.Wallet{
+		WalletId:         wallet.ID,
+		OwnerId:          wallet.OwnerId,
+		Currency:         wallet.Currency,
+		Balance:          entities.FormatAmount(wallet.Balance, wallet.Currency),
+		AvailableBalance: entities.FormatAmount(wallet.Available(), wallet.Currency),
+		OverdraftLimit:   entities.FormatAmount(wallet.OverdraftLimit, wallet.Currency),
+		Status:           wallet.Status,
+		FreezeMode:       wallet.FreezeMode,
+	}
+}
+
+func TransactionToProto(transaction entities.Transaction) *walletv1.Transaction {
+	return &walletv1.Transaction{
+		TransactionId: transaction.ID,
+		WalletId:      transaction.WalletId,
+		OperationType: transaction.OperationType,
+		Currency:      transaction.Currency,
+		Amount:        entities.FormatAmount(transaction.Amount, transaction.Currency),
+		Fee:           entities.FormatAmount(transaction.Fee, transaction.Currency),
+		Balance:       entities.FormatAmount(transaction.Balance, transaction.Currency),
+		ReferenceId:   transaction.ReferenceId,
+		CreatedAt:     timestamppb.New(transaction.CreatedAt),
+	}
+}
@@ -0,0 +1,64 @@
+package grpcapi
+
+import (
+	"TestProject/source/internal/entities"
+	"context"
+	"errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// domainError maps application errors to gRPC statuses, following the
+// HTTP statuses the REST API uses for them.
+func domainError(err error) error {
+	switch {
+	case errors.Is(err, context.Canceled),
+		errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	case errors.Is(err, entities.ErrInsufficientFunds),
+		errors.Is(err, entities.ErrWalletFrozen),
+		errors.Is(err, entities.ErrWalletClosed):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, entities.ErrLimitExceeded):
+		return limitError(err)
+	case errors.Is(err, entities.ErrCurrencyMismatch),
+		errors.Is(err, entities.ErrInvalidAmountPrecision),
+		errors.Is(err, entities.ErrUnsupportedCurrency),
+		errors.Is(err, entities.ErrInvalidAmount),
+		errors.Is(err, entities.ErrAmountOverflow),
+		errors.Is(err, entities.ErrInvalidOperation):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, entities.ErrWalletNotFound):
+		return status.Error(codes.NotFound, "wallet not found")
+	case errors.Is(err, entities.ErrCustomerNotFound):
+		return status.Error(codes.NotFound, "owner not found")
+	case errors.Is(err, entities.ErrIdempotencyKeyReused):
+		return status.Error(codes.AlreadyExists, "idempotency key reused with a different request")
+	}
+	return status.Error(codes.Internal, "internal error")
+}
+
+// limitError carries the name of the broken limit in an ErrorInfo detail.
+func limitError(err error) error {
+	st := status.New(codes.ResourceExhausted, err.Error())
+
+	var limitErr *entities.LimitExceededError
+	if !errors.As(err, &limitErr) {
+		return st.Err()
+	}
+	detailed, detailErr := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   "LIMIT_EXCEEDED",
+		Domain:   "wallet.v1",
+		Metadata: map[string]string{"limit": limitErr.Limit},
+	})
+	if detailErr != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+func invalidArgument(message string) error {
+	return status.Error(codes.InvalidArgument, message)
+}
@@ -0,0 +1,116 @@
+package grpcapi
+
+import (
+	walletv1 "TestProject/source/api/wallet/v1"
+	"TestProject/source/internal/entities"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDomainError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code codes.Code
+	}{
+		{"insufficient funds", entities.ErrInsufficientFunds, codes.FailedPrecondition},
+		{"frozen wallet", entities.ErrWalletFrozen, codes.FailedPrecondition},
+		{"closed wallet", entities.ErrWalletClosed, codes.FailedPrecondition},
+		{"currency mismatch", entities.ErrCurrencyMismatch, codes.InvalidArgument},
+		{"precision", entities.ErrInvalidAmountPrecision, codes.InvalidArgument},
+		{"unsupported currency", entities.ErrUnsupportedCurrency, codes.InvalidArgument},
+		{"overflow", entities.ErrAmountOverflow, codes.InvalidArgument},
+		{"wallet not found", entities.ErrWalletNotFound, codes.NotFound},
+		{"owner not found", entities.ErrCustomerNotFound, codes.NotFound},
+		{"key reused", entities.ErrIdempotencyKeyReused, codes.AlreadyExists},
+		{"deadline", context.DeadlineExceeded, codes.DeadlineExceeded},
+		{"unknown", errors.New("connection refused"), codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := domainError(fmt.Errorf("error processing transaction: %w", tt.err))
+			assert.Equal(t, tt.code, status.Code(err))
+		})
+	}
+}
+
+func TestDomainError_HidesInternalErrors(t *testing.T) {
+	err := domainError(errors.New("db.First error: password authentication failed"))
+
+	assert.Equal(t, "internal error", status.Convert(err).Message())
+}
+
+func TestDomainError_LimitDetails(t *testing.T) {
+	err := domainError(fmt.Errorf("error processing transaction: %w", &entities.LimitExceededError{
+		Limit:     entities.LimitDailyAmount,
+		Max:       decimal.NewFromInt(1000),
+		Attempted: decimal.NewFromInt(1200),
+	}))
+
+	st := status.Convert(err)
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+	require.Len(t, st.Details(), 1)
+	info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+	require.True(t, ok)
+	assert.Equal(t, entities.LimitDailyAmount, info.GetMetadata()["limit"])
+}
+
+func TestWalletServer_ValidatesRequests(t *testing.T) {
+	// Invalid requests are rejected before the application is called.
+	server := NewWalletServer(slog.Default(), nil)
+	ctx := context.Background()
+	walletID := "5b0a3b4e-8a5f-4b47-9a0e-5b0e0d3e6c1a"
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"create with negative balance", func() error {
+			_, err := server.CreateWallet(ctx, &walletv1.CreateWalletRequest{Balance: "-1"})
+			return err
+		}},
+		{"create with invalid owner", func() error {
+			_, err := server.CreateWallet(ctx, &walletv1.CreateWalletRequest{OwnerId: "owner"})
+			return err
+		}},
+		{"balance without wallet id", func() error {
+			_, err := server.GetBalance(ctx, &walletv1.GetBalanceRequest{})
+			return err
+		}},
+		{"transaction with unknown operation", func() error {
+			_, err := server.ProcessTransaction(ctx, &walletv1.ProcessTransactionRequest{
+				WalletId: walletID, OperationType: "INTEREST", Amount: "10",
+			})
+			return err
+		}},
+		{"transaction with zero amount", func() error {
+			_, err := server.ProcessTransaction(ctx, &walletv1.ProcessTransactionRequest{
+				WalletId: walletID, OperationType: "DEPOSIT", Amount: "0",
+			})
+			return err
+		}},
+		{"transaction with float amount", func() error {
+			_, err := server.ProcessTransaction(ctx, &walletv1.ProcessTransactionRequest{
+				WalletId: walletID, OperationType: "DEPOSIT", Amount: "1e3",
+			})
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, codes.InvalidArgument, status.Code(tt.call()))
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package grpcapi
+
+import (
+	"TestProject/source/internal/application"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	MetadataIdempotencyKey     = "idempotency-key"
+	MetadataIdempotentReplayed = "idempotent-replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// idempotent runs fn once per idempotency-key metadata value, as the REST
+// Idempotency middleware does for the Idempotency-Key header. The response
+// is stored in the transaction fn runs in and replayed to retries with the
+// idempotent-replayed header set. Without the key fn just runs.
+func idempotent[T proto.Message](
+	ctx context.Context,
+	app *application.Application,
+	request proto.Message,
+	fn func(ctx context.Context) (T, error),
+) (T, error) {
+	var zero T
+
+	key := idempotencyKey(ctx)
+	if key == "" {
+		return fn(ctx)
+	}
+	if len(key) > maxIdempotencyKeyLength {
+		return zero, invalidArgument("idempotency key is too long")
+	}
+
+	requestHash, err := hashRequest(ctx, request)
+	if err != nil {
+		return zero, domainError(err)
+	}
+
+	var response T
+	var handlerErr error
+
+	record, replayed, err := app.ExecuteIdempotent(ctx, key, requestHash, func(ctx context.Context) (int, []byte, error) {
+		response, handlerErr = fn(ctx)
+		if handlerErr != nil {
+			return 0, nil, handlerErr
+		}
+		body, err := proto.Marshal(response)
+		if err != nil {
+			return 0, nil, fmt.Errorf("error marshaling response: %w", err)
+		}
+		// The record counts as completed by a non-zero status code.
+		return http.StatusOK, body, nil
+	})
+	if err != nil {
+		if handlerErr != nil {
+			// Already a status.
+			return zero, handlerErr
+		}
+		return zero, domainError(err)
+	}
+	if !replayed {
+		return response, nil
+	}
+
+	response = zero.ProtoReflect().Type().New().Interface().(T)
+	err = proto.Unmarshal(record.Response, response)
+	if err != nil {
+		return zero, domainError(fmt.Errorf("error unmarshaling stored response: %w", err))
+	}
+	err = grpc.SetHeader(ctx, metadata.Pairs(MetadataIdempotentReplayed, "true"))
+	if err != nil {
+		return zero, domainError(fmt.Errorf("error setting replay header: %w", err))
+	}
+	return response, nil
+}
+
+func idempotencyKey(ctx context.Context) string {
+	values := metadata.ValueFromIncomingContext(ctx, MetadataIdempotencyKey)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// hashRequest identifies the call by its full method name and the
+// deterministic encoding of the request message.
+func hashRequest(ctx context.Context, request proto.Message) (string, error) {
+	body, err := proto.MarshalOptions{Deterministic: true}.Marshal(request)
+	if err != nil {
+		return "", fmt.Errorf("error marshaling request: %w", err)
+	}
+	method, _ := grpc.Method(ctx)
+
+	sum := sha256.New()
+	sum.Write([]byte(method + "\n"))
+	sum.Write(body)
+	return hex.EncodeToString(sum.Sum(nil)), nil
+}
@@ -0,0 +1,75 @@
+package grpcapi
+
+import (
+	walletv1 "TestProject/source/api/wallet/v1"
+	"TestProject/source/config"
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+
+	"go.uber.org/fx"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+const moduleName = "grpc_api"
+
+var Module = fx.Module(
+	moduleName,
+	fx.Provide(
+		NewWalletServer,
+		NewGrpcServer,
+	),
+	fx.Decorate(
+		func(log *slog.Logger) *slog.Logger {
+			return log.With("module", moduleName)
+		},
+	),
+)
+
+// NewGrpcServer serves the gRPC API on GRPC_PORT. Reflection is enabled so
+// that tools like grpcurl work without the .proto file.
+func NewGrpcServer(
+	lc fx.Lifecycle,
+	walletServer *WalletServer,
+	cfg config.GrpcConfig,
+	log *slog.Logger,
+) *grpc.Server {
+	server := grpc.NewServer()
+	walletv1.RegisterWalletServiceServer(server, walletServer)
+	reflection.Register(server)
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			listener, err := net.Listen("tcp", fmt.Sprintf(":%v", cfg.Port))
+			if err != nil {
+				return fmt.Errorf("error listening on grpc port: %w", err)
+			}
+			go func() {
+				err := server.Serve(listener)
+				if err != nil {
+					log.Error("serving grpc", slog.Any("error", err))
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			// GracefulStop waits for open history streams; cut them off
+			// when the shutdown deadline comes first.
+			stopped := make(chan struct{})
+			go func() {
+				server.GracefulStop()
+				close(stopped)
+			}()
+			select {
+			case <-stopped:
+			case <-ctx.Done():
+				server.Stop()
+			}
+			return nil
+		},
+	})
+
+	return server
+}
@@ -0,0 +1,180 @@
+package grpcapi
+
+import (
+	walletv1 "TestProject/source/api/wallet/v1"
+	"TestProject/source/internal/application"
+	"TestProject/source/internal/entities"
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// historyPageSize is how many transactions ListTransactions reads per
+// query while streaming.
+const historyPageSize = 100
+
+// WalletServer implements walletv1.WalletServiceServer on top of the same
+// application methods the REST handlers use.
+type WalletServer struct {
+	walletv1.UnimplementedWalletServiceServer
+
+	log *slog.Logger
+	app *application.Application
+}
+
+func NewWalletServer(log *slog.Logger, app *application.Application) *WalletServer {
+	return &WalletServer{log: log, app: app}
+}
+
+func (s *WalletServer) CreateWallet(ctx context.Context, request *walletv1.CreateWalletRequest) (*walletv1.Wallet, error) {
+	wallet := entities.NewWallet()
+
+	if request.GetBalance() != "" {
+		balance, err := entities.ParseAmount(request.GetBalance())
+		if err != nil {
+			return nil, invalidArgument(err.Error())
+		}
+		if balance.IsNegative() {
+			return nil, invalidArgument("balance cannot be negative")
+		}
+		wallet.Balance = balance
+	}
+	if request.GetCurrency() != "" {
+		wallet.Currency = request.GetCurrency()
+	}
+	if request.GetOwnerId() != "" {
+		err := uuid.Validate(request.GetOwnerId())
+		if err != nil {
+			return nil, invalidArgument("owner_id must be a UUID")
+		}
+		wallet.OwnerId = request.GetOwnerId()
+	}
+
+	return idempotent(ctx, s.app, request, func(ctx context.Context) (*walletv1.Wallet, error) {
+		wallet, err := s.app.CreateWallet(ctx, wallet)
+		if err != nil {
+			s.log.ErrorContext(ctx, "error creating wallet", slog.String("error", err.Error()))
+			return nil, domainError(err)
+		}
+		return WalletToProto(wallet), nil
+	})
+}
+
+func (s *WalletServer) GetBalance(ctx context.Context, request *walletv1.GetBalanceRequest) (*walletv1.Wallet, error) {
+	err := validateWalletID(request.GetWalletId())
+	if err != nil {
+		return nil, err
+	}
+
+	wallet, err := s.app.WalletRepo.GetByID(ctx, request.GetWalletId())
+	if err != nil {
+		s.log.ErrorContext(ctx, "wallet not found", slog.String("error", err.Error()))
+		return nil, domainError(err)
+	}
+
+	return WalletToProto(wallet), nil
+}
+
+func (s *WalletServer) ProcessTransaction(ctx context.Context, request *walletv1.ProcessTransactionRequest) (*walletv1.Transaction, error) {
+	err := validateWalletID(request.GetWalletId())
+	if err != nil {
+		return nil, err
+	}
+	operationType := request.GetOperationType()
+	if operationType != "DEPOSIT" && operationType != "WITHDRAW" {
+		return nil, invalidArgument("operation_type must be DEPOSIT or WITHDRAW")
+	}
+	amount, err := parsePositiveAmount(request.GetAmount())
+	if err != nil {
+		return nil, invalidArgument(err.Error())
+	}
+
+	transaction := entities.NewTransaction()
+	transaction.WalletId = request.GetWalletId()
+	transaction.OperationType = operationType
+	transaction.Currency = request.GetCurrency()
+	transaction.Amount = amount
+
+	return idempotent(ctx, s.app, request, func(ctx context.Context) (*walletv1.Transaction, error) {
+		transaction, err := s.app.ProcessTransaction(ctx, transaction)
+		if err != nil {
+			s.log.ErrorContext(ctx, "error processing transaction", slog.String("error", err.Error()))
+			return nil, domainError(err)
+		}
+		return TransactionToProto(transaction), nil
+	})
+}
+
+// ListTransactions streams the history newest first, reading it page by
+// page so that long histories are never loaded at once.
+func (s *WalletServer) ListTransactions(request *walletv1.ListTransactionsRequest, stream grpc.ServerStreamingServer[walletv1.Transaction]) error {
+	ctx := stream.Context()
+
+	err := validateWalletID(request.GetWalletId())
+	if err != nil {
+		return err
+	}
+
+	filter := entities.TransactionFilter{
+		WalletId:      request.GetWalletId(),
+		OperationType: request.GetOperationType(),
+		From:          optionalTime(request.GetFrom()),
+		To:            optionalTime(request.GetTo()),
+		Limit:         historyPageSize,
+	}
+
+	for {
+		page, err := s.app.ListTransactions(ctx, filter)
+		if err != nil {
+			s.log.ErrorContext(ctx, "error listing transactions", slog.String("error", err.Error()))
+			return domainError(err)
+		}
+
+		for _, transaction := range page.Transactions {
+			err = stream.Send(TransactionToProto(transaction))
+			if err != nil {
+				return err
+			}
+		}
+
+		if page.NextCursor == nil {
+			return nil
+		}
+		filter.Cursor = page.NextCursor
+	}
+}
+
+func validateWalletID(walletID string) error {
+	if walletID == "" {
+		return invalidArgument("wallet_id is required")
+	}
+	if uuid.Validate(walletID) != nil {
+		return invalidArgument("wallet_id must be a UUID")
+	}
+	return nil
+}
+
+func parsePositiveAmount(value string) (decimal.Decimal, error) {
+	amount, err := entities.ParseAmount(value)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+	if !amount.IsPositive() {
+		return decimal.Decimal{}, fmt.Errorf("%w: amount must be greater than zero", entities.ErrInvalidAmount)
+	}
+	return amount, nil
+}
+
+func optionalTime(timestamp *timestamppb.Timestamp) *time.Time {
+	if timestamp == nil {
+		return nil
+	}
+	t := timestamp.AsTime()
+	return &t
+}
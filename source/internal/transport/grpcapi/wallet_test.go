@@ -0,0 +1,587 @@
+package grpcapi
+
+import (
+	walletv1 "TestProject/source/api/wallet/v1"
+	"TestProject/source/internal/application"
+	"TestProject/source/internal/entities"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// The mocks below keep just enough state for the application methods the
+// server calls. They embed the repo interfaces, so any other call panics.
+
+type mockTransactor struct{}
+
+func (mockTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (mockTransactor) WithinSavepoint(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (mockTransactor) InTransaction(ctx context.Context) bool {
+	return false
+}
+
+type mockWalletRepo struct {
+	application.WalletRepo
+
+	mu      sync.Mutex
+	wallets map[string]entities.Wallet
+	errors  map[string]error
+}
+
+func (m *mockWalletRepo) Create(ctx context.Context, wallet entities.Wallet) (entities.Wallet, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.wallets[wallet.ID] = wallet
+	return wallet, nil
+}
+
+func (m *mockWalletRepo) GetByID(ctx context.Context, walletID string) (entities.Wallet, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err, ok := m.errors["get"]; ok {
+		return entities.Wallet{}, err
+	}
+	wallet, ok := m.wallets[walletID]
+	if !ok {
+		return entities.Wallet{}, entities.ErrWalletNotFound
+	}
+	return wallet, nil
+}
+
+func (m *mockWalletRepo) UpdateWithLock(ctx context.Context, walletID string, updateFn func(*entities.Wallet) error) (entities.Wallet, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	wallet, ok := m.wallets[walletID]
+	if !ok {
+		return entities.Wallet{}, entities.ErrWalletNotFound
+	}
+	err := updateFn(&wallet)
+	if err != nil {
+		return entities.Wallet{}, err
+	}
+	m.wallets[walletID] = wallet
+	return wallet, nil
+}
+
+type mockTransactionRepo struct {
+	application.TransactionRepo
+
+	mu           sync.Mutex
+	transactions []entities.Transaction
+}
+
+func (m *mockTransactionRepo) Create(ctx context.Context, transaction entities.Transaction) (entities.Transaction, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.transactions = append(m.transactions, transaction)
+	return transaction, nil
+}
+
+// List returns the newest transactions first, as the storage does.
+func (m *mockTransactionRepo) List(ctx context.Context, filter entities.TransactionFilter) ([]entities.Transaction, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var result []entities.Transaction
+	for i := len(m.transactions) - 1; i >= 0 && len(result) < filter.Limit; i-- {
+		transaction := m.transactions[i]
+		if transaction.WalletId != filter.WalletId {
+			continue
+		}
+		if filter.Cursor != nil && !transaction.CreatedAt.Before(filter.Cursor.CreatedAt) {
+			continue
+		}
+		result = append(result, transaction)
+	}
+	return result, nil
+}
+
+func (m *mockTransactionRepo) Usage(ctx context.Context, walletID string, operationTypes []string, since time.Time) (entities.Usage, error) {
+	return entities.Usage{}, nil
+}
+
+type mockHoldRepo struct {
+	application.HoldRepo
+}
+
+func (mockHoldRepo) Usage(ctx context.Context, walletID string, since time.Time, exceptID string) (entities.Usage, error) {
+	return entities.Usage{}, nil
+}
+
+type mockJournalRepo struct {
+	application.JournalRepo
+}
+
+func (mockJournalRepo) Create(ctx context.Context, entry entities.JournalEntry) error {
+	return nil
+}
+
+type mockLimitRepo struct {
+	application.LimitRepo
+
+	limits map[string]entities.VelocityLimits
+}
+
+func (m *mockLimitRepo) GetByWalletID(ctx context.Context, walletID string) (entities.VelocityLimits, error) {
+	limits, ok := m.limits[walletID]
+	if !ok {
+		return entities.VelocityLimits{}, entities.ErrLimitsNotFound
+	}
+	return limits, nil
+}
+
+type mockFeeRepo struct {
+	application.FeeRepo
+}
+
+func (mockFeeRepo) Effective(ctx context.Context, walletID, operationType, currency string) (entities.FeeRule, error) {
+	return entities.FeeRule{}, entities.ErrFeeRuleNotFound
+}
+
+type mockOutboxRepo struct {
+	application.OutboxRepo
+}
+
+func (mockOutboxRepo) Create(ctx context.Context, event entities.WalletEvent) (entities.WalletEvent, error) {
+	return event, nil
+}
+
+// mockIdempotencyRepo never expires keys: the application built here has
+// no idempotency TTL.
+type mockIdempotencyRepo struct {
+	application.IdempotencyRepo
+
+	mu      sync.Mutex
+	records map[string]entities.IdempotencyRecord
+}
+
+func (m *mockIdempotencyRepo) Acquire(ctx context.Context, record entities.IdempotencyRecord) (entities.IdempotencyRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, ok := m.records[record.Key]
+	if ok {
+		return stored, nil
+	}
+	m.records[record.Key] = record
+	return record, nil
+}
+
+func (m *mockIdempotencyRepo) Complete(ctx context.Context, record entities.IdempotencyRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.records[record.Key] = record
+	return nil
+}
+
+type testBackend struct {
+	wallets      *mockWalletRepo
+	transactions *mockTransactionRepo
+	limits       *mockLimitRepo
+}
+
+func (b *testBackend) addWallet(currency, balance string) entities.Wallet {
+	wallet := entities.NewWallet()
+	wallet.Currency = currency
+	wallet.Balance = decimal.RequireFromString(balance)
+	b.wallets.wallets[wallet.ID] = wallet
+	return wallet
+}
+
+// newTestClient serves a WalletServer over an in-memory connection, so
+// that metadata, headers and statuses go through a real gRPC stack.
+func newTestClient(t *testing.T) (walletv1.WalletServiceClient, *testBackend) {
+	t.Helper()
+
+	backend := &testBackend{
+		wallets: &mockWalletRepo{
+			wallets: make(map[string]entities.Wallet),
+			errors:  make(map[string]error),
+		},
+		transactions: &mockTransactionRepo{},
+		limits:       &mockLimitRepo{limits: make(map[string]entities.VelocityLimits)},
+	}
+	app := &application.Application{
+		Transactor:      mockTransactor{},
+		WalletRepo:      backend.wallets,
+		TransactionRepo: backend.transactions,
+		IdempotencyRepo: &mockIdempotencyRepo{records: make(map[string]entities.IdempotencyRecord)},
+		HoldRepo:        mockHoldRepo{},
+		JournalRepo:     mockJournalRepo{},
+		LimitRepo:       backend.limits,
+		FeeRepo:         mockFeeRepo{},
+		OutboxRepo:      mockOutboxRepo{},
+	}
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	walletv1.RegisterWalletServiceServer(server, NewWalletServer(slog.New(slog.NewTextHandler(io.Discard, nil)), app))
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return walletv1.NewWalletServiceClient(conn), backend
+}
+
+func TestWalletServer_Amounts(t *testing.T) {
+	client, backend := newTestClient(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		currency string
+		balance  string
+		request  *walletv1.ProcessTransactionRequest
+		amount   string
+		after    string
+		code     codes.Code
+	}{
+		{
+			name: "deposit is formatted at the currency scale", currency: "RUB", balance: "10",
+			request: &walletv1.ProcessTransactionRequest{OperationType: "DEPOSIT", Amount: "100.5"},
+			amount:  "100.50", after: "110.50",
+		},
+		{
+			name: "withdraw keeps the exact decimal", currency: "RUB", balance: "0.3",
+			request: &walletv1.ProcessTransactionRequest{OperationType: "WITHDRAW", Amount: "0.1"},
+			amount:  "0.10", after: "0.20",
+		},
+		{
+			name: "currency without minor units", currency: "JPY", balance: "0",
+			request: &walletv1.ProcessTransactionRequest{OperationType: "DEPOSIT", Amount: "1500"},
+			amount:  "1500", after: "1500",
+		},
+		{
+			name: "three decimals have a scale of three", currency: "KWD", balance: "1",
+			request: &walletv1.ProcessTransactionRequest{OperationType: "DEPOSIT", Amount: "0.125"},
+			amount:  "0.125", after: "1.125",
+		},
+		{
+			name: "too many decimals for the currency", currency: "RUB", balance: "0",
+			request: &walletv1.ProcessTransactionRequest{OperationType: "DEPOSIT", Amount: "10.123"},
+			code:    codes.InvalidArgument,
+		},
+		{
+			name: "fraction of a yen", currency: "JPY", balance: "0",
+			request: &walletv1.ProcessTransactionRequest{OperationType: "DEPOSIT", Amount: "10.5"},
+			code:    codes.InvalidArgument,
+		},
+		{
+			name: "negative amount", currency: "RUB", balance: "100",
+			request: &walletv1.ProcessTransactionRequest{OperationType: "WITHDRAW", Amount: "-10"},
+			code:    codes.InvalidArgument,
+		},
+		{
+			name: "amount that does not fit the storage", currency: "RUB", balance: "0",
+			request: &walletv1.ProcessTransactionRequest{OperationType: "DEPOSIT", Amount: "1" + strings.Repeat("0", 30)},
+			code:    codes.InvalidArgument,
+		},
+		{
+			name: "currency other than the wallet's", currency: "RUB", balance: "0",
+			request: &walletv1.ProcessTransactionRequest{OperationType: "DEPOSIT", Amount: "10", Currency: "USD"},
+			code:    codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wallet := backend.addWallet(tt.currency, tt.balance)
+			tt.request.WalletId = wallet.ID
+
+			transaction, err := client.ProcessTransaction(ctx, tt.request)
+			if tt.code != codes.OK {
+				assert.Equal(t, tt.code, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.amount, transaction.GetAmount())
+			assert.Equal(t, tt.after, transaction.GetBalance())
+			assert.Equal(t, tt.currency, transaction.GetCurrency())
+
+			balance, err := client.GetBalance(ctx, &walletv1.GetBalanceRequest{WalletId: wallet.ID})
+			require.NoError(t, err)
+			assert.Equal(t, tt.after, balance.GetBalance())
+		})
+	}
+}
+
+func TestWalletServer_CreateWalletAmounts(t *testing.T) {
+	client, _ := newTestClient(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		request *walletv1.CreateWalletRequest
+		balance string
+		code    codes.Code
+	}{
+		{"empty wallet", &walletv1.CreateWalletRequest{}, "0.00", codes.OK},
+		{"opening balance", &walletv1.CreateWalletRequest{Balance: "250.5"}, "250.50", codes.OK},
+		{"opening balance in yen", &walletv1.CreateWalletRequest{Currency: "JPY", Balance: "250"}, "250", codes.OK},
+		{"opening balance too precise", &walletv1.CreateWalletRequest{Balance: "0.001"}, "", codes.InvalidArgument},
+		{"unsupported currency", &walletv1.CreateWalletRequest{Currency: "XXX"}, "", codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wallet, err := client.CreateWallet(ctx, tt.request)
+			if tt.code != codes.OK {
+				assert.Equal(t, tt.code, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.balance, wallet.GetBalance())
+			assert.Equal(t, tt.balance, wallet.GetAvailableBalance())
+		})
+	}
+}
+
+func TestWalletServer_ErrorStatuses(t *testing.T) {
+	client, backend := newTestClient(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		prepare func(wallet *entities.Wallet)
+		call    func(walletID string) error
+		code    codes.Code
+		message string
+		limit   string
+	}{
+		{
+			name: "insufficient funds",
+			call: func(walletID string) error {
+				_, err := client.ProcessTransaction(ctx, &walletv1.ProcessTransactionRequest{
+					WalletId: walletID, OperationType: "WITHDRAW", Amount: "150",
+				})
+				return err
+			},
+			code: codes.FailedPrecondition,
+		},
+		{
+			name:    "frozen wallet",
+			prepare: func(wallet *entities.Wallet) { wallet.Status = entities.WalletFrozen },
+			call: func(walletID string) error {
+				_, err := client.ProcessTransaction(ctx, &walletv1.ProcessTransactionRequest{
+					WalletId: walletID, OperationType: "WITHDRAW", Amount: "10",
+				})
+				return err
+			},
+			code: codes.FailedPrecondition,
+		},
+		{
+			name: "velocity limit",
+			prepare: func(wallet *entities.Wallet) {
+				backend.limits.limits[wallet.ID] = entities.VelocityLimits{
+					WalletId: wallet.ID, PerOperation: decimal.NewFromInt(50),
+				}
+			},
+			call: func(walletID string) error {
+				_, err := client.ProcessTransaction(ctx, &walletv1.ProcessTransactionRequest{
+					WalletId: walletID, OperationType: "WITHDRAW", Amount: "60",
+				})
+				return err
+			},
+			code:  codes.ResourceExhausted,
+			limit: entities.LimitPerOperation,
+		},
+		{
+			name: "unknown wallet",
+			call: func(string) error {
+				_, err := client.GetBalance(ctx, &walletv1.GetBalanceRequest{WalletId: "5b0a3b4e-8a5f-4b47-9a0e-5b0e0d3e6c1a"})
+				return err
+			},
+			code:    codes.NotFound,
+			message: "wallet not found",
+		},
+		{
+			name: "transaction on an unknown wallet",
+			call: func(string) error {
+				_, err := client.ProcessTransaction(ctx, &walletv1.ProcessTransactionRequest{
+					WalletId: "5b0a3b4e-8a5f-4b47-9a0e-5b0e0d3e6c1a", OperationType: "DEPOSIT", Amount: "10",
+				})
+				return err
+			},
+			code: codes.NotFound,
+		},
+		{
+			name: "history of an unknown wallet",
+			call: func(string) error {
+				stream, err := client.ListTransactions(ctx, &walletv1.ListTransactionsRequest{WalletId: "5b0a3b4e-8a5f-4b47-9a0e-5b0e0d3e6c1a"})
+				if err != nil {
+					return err
+				}
+				_, err = stream.Recv()
+				return err
+			},
+			code: codes.NotFound,
+		},
+		{
+			name: "storage failure",
+			prepare: func(*entities.Wallet) {
+				backend.wallets.errors["get"] = errors.New("db.First error: connection refused")
+			},
+			call: func(walletID string) error {
+				defer delete(backend.wallets.errors, "get")
+				_, err := client.GetBalance(ctx, &walletv1.GetBalanceRequest{WalletId: walletID})
+				return err
+			},
+			code:    codes.Internal,
+			message: "internal error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wallet := backend.addWallet("RUB", "100")
+			if tt.prepare != nil {
+				tt.prepare(&wallet)
+				backend.wallets.wallets[wallet.ID] = wallet
+			}
+
+			st := status.Convert(tt.call(wallet.ID))
+			assert.Equal(t, tt.code, st.Code())
+			if tt.message != "" {
+				assert.Equal(t, tt.message, st.Message())
+			}
+			if tt.limit != "" {
+				require.Len(t, st.Details(), 1)
+				info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+				require.True(t, ok)
+				assert.Equal(t, tt.limit, info.GetMetadata()["limit"])
+			}
+			// A failed operation leaves the balance alone.
+			assert.True(t, backend.wallets.wallets[wallet.ID].Balance.Equal(decimal.NewFromInt(100)))
+		})
+	}
+}
+
+func TestWalletServer_Idempotency(t *testing.T) {
+	client, backend := newTestClient(t)
+	wallet := backend.addWallet("RUB", "0")
+
+	deposit := func(key, amount string) (*walletv1.Transaction, metadata.MD, error) {
+		ctx := context.Background()
+		if key != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, MetadataIdempotencyKey, key)
+		}
+		var header metadata.MD
+		transaction, err := client.ProcessTransaction(ctx, &walletv1.ProcessTransactionRequest{
+			WalletId: wallet.ID, OperationType: "DEPOSIT", Amount: amount,
+		}, grpc.Header(&header))
+		return transaction, header, err
+	}
+
+	first, header, err := deposit("key-1", "100")
+	require.NoError(t, err)
+	assert.Empty(t, header.Get(MetadataIdempotentReplayed))
+
+	tests := []struct {
+		name     string
+		key      string
+		amount   string
+		code     codes.Code
+		replayed bool
+		balance  string
+	}{
+		{name: "retry is replayed", key: "key-1", amount: "100", replayed: true, balance: "100.00"},
+		{name: "same key with another request", key: "key-1", amount: "200", code: codes.AlreadyExists, balance: "100.00"},
+		{name: "new key runs again", key: "key-2", amount: "100", balance: "200.00"},
+		{name: "no key runs again", amount: "100", balance: "300.00"},
+		{name: "key too long", key: strings.Repeat("k", 256), amount: "100", code: codes.InvalidArgument, balance: "300.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transaction, header, err := deposit(tt.key, tt.amount)
+			if tt.code != codes.OK {
+				assert.Equal(t, tt.code, status.Code(err))
+			} else {
+				require.NoError(t, err)
+				if tt.replayed {
+					assert.Equal(t, []string{"true"}, header.Get(MetadataIdempotentReplayed))
+					assert.Equal(t, first.GetTransactionId(), transaction.GetTransactionId())
+					assert.Equal(t, first.GetBalance(), transaction.GetBalance())
+				} else {
+					assert.Empty(t, header.Get(MetadataIdempotentReplayed))
+					assert.NotEqual(t, first.GetTransactionId(), transaction.GetTransactionId())
+				}
+			}
+
+			balance := backend.wallets.wallets[wallet.ID].Balance
+			assert.Equal(t, tt.balance, entities.FormatAmount(balance, "RUB"))
+		})
+	}
+}
+
+func TestWalletServer_ListTransactions(t *testing.T) {
+	client, backend := newTestClient(t)
+	wallet := backend.addWallet("RUB", "0")
+
+	// More than one page, so the stream has to follow the cursor.
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < historyPageSize+20; i++ {
+		transaction := entities.NewTransaction()
+		transaction.WalletId = wallet.ID
+		transaction.OperationType = "DEPOSIT"
+		transaction.Currency = "RUB"
+		transaction.Amount = decimal.NewFromInt(int64(i + 1))
+		transaction.CreatedAt = created.Add(time.Duration(i) * time.Minute)
+		backend.transactions.transactions = append(backend.transactions.transactions, transaction)
+	}
+
+	stream, err := client.ListTransactions(context.Background(), &walletv1.ListTransactionsRequest{WalletId: wallet.ID})
+	require.NoError(t, err)
+
+	var amounts []string
+	for {
+		transaction, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		amounts = append(amounts, transaction.GetAmount())
+	}
+
+	require.Len(t, amounts, historyPageSize+20)
+	assert.Equal(t, "120.00", amounts[0])
+	assert.Equal(t, "1.00", amounts[len(amounts)-1])
+}
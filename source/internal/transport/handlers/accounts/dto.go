@@ -0,0 +1,25 @@
+package accounts
+
+import "TestProject/source/internal/entities"
+
+type BalanceResponse struct {
+	AccountId string `json:"accountId"`
+	Currency  string `json:"currency"`
+	Balance   string `json:"balance"`
+}
+
+type BalancesResponse struct {
+	Accounts []BalanceResponse `json:"accounts"`
+}
+
+func EntitiesToResponse(balances []entities.AccountBalance) BalancesResponse {
+	response := BalancesResponse{Accounts: make([]BalanceResponse, 0, len(balances))}
+	for _, balance := range balances {
+		response.Accounts = append(response.Accounts, BalanceResponse{
+			AccountId: balance.AccountId,
+			Currency:  balance.Currency,
+			Balance:   entities.FormatAmount(balance.Balance, balance.Currency),
+		})
+	}
+	return response
+}
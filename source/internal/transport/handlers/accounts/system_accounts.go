@@ -0,0 +1,22 @@
+package accounts
+
+import (
+	"log/slog"
+
+	"github.com/labstack/echo/v4"
+)
+
+// GetSystemAccounts returns the balance of every system account. Money that
+// entered the wallets shows up as a negative CASH_IN balance.
+func (h *Handlers) GetSystemAccounts(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	balances, err := h.app.SystemAccountBalances(ctx)
+	if err != nil {
+		h.log.ErrorContext(ctx, "error getting system account balances", slog.String("error", err.Error()))
+
+		return echo.ErrInternalServerError.SetInternal(err)
+	}
+
+	return c.JSON(200, EntitiesToResponse(balances))
+}
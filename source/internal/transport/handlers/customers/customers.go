@@ -0,0 +1,161 @@
+package customers
+
+import (
+	"TestProject/source/internal/entities"
+	"log/slog"
+
+	"github.com/labstack/echo/v4"
+)
+
+const defaultListLimit = 50
+
+func (h *Handlers) CreateCustomer(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := h.log
+
+	var request CreateRequest
+
+	err := c.Bind(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error binding request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	err = c.Validate(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error validating request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	customer := entities.NewCustomer()
+	customer.Name = request.Name
+	customer.Email = request.Email
+	customer.Phone = request.Phone
+
+	customer, err = h.app.CreateCustomer(ctx, customer)
+	if err != nil {
+		logger.ErrorContext(ctx, "error creating customer", slog.String("error", err.Error()))
+		return domainError(err)
+	}
+
+	return c.JSON(201, EntityToResponse(customer))
+}
+
+func (h *Handlers) GetCustomer(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := h.log
+
+	customer, err := h.app.GetCustomer(ctx, c.Param("id"))
+	if err != nil {
+		logger.ErrorContext(ctx, "error getting customer", slog.String("error", err.Error()))
+		return domainError(err)
+	}
+
+	return c.JSON(200, EntityToResponse(customer))
+}
+
+func (h *Handlers) ListCustomers(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := h.log
+
+	var request ListRequest
+
+	err := c.Bind(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error binding request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	err = c.Validate(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error validating request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	limit := request.Limit
+	if limit == 0 {
+		limit = defaultListLimit
+	}
+
+	customers, err := h.app.ListCustomers(ctx, request.Cursor, limit)
+	if err != nil {
+		logger.ErrorContext(ctx, "error listing customers", slog.String("error", err.Error()))
+		return domainError(err)
+	}
+
+	return c.JSON(200, CustomersToResponse(customers, limit))
+}
+
+func (h *Handlers) UpdateCustomer(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := h.log
+
+	var request UpdateRequest
+
+	err := c.Bind(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error binding request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	err = c.Validate(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error validating request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	customer, err := h.app.UpdateCustomer(ctx, entities.Customer{
+		ID:    request.CustomerId,
+		Name:  request.Name,
+		Email: request.Email,
+		Phone: request.Phone,
+	})
+	if err != nil {
+		logger.ErrorContext(ctx, "error updating customer", slog.String("error", err.Error()))
+		return domainError(err)
+	}
+
+	return c.JSON(200, EntityToResponse(customer))
+}
+
+// DeleteCustomer answers 409 while the customer still owns wallets.
+func (h *Handlers) DeleteCustomer(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := h.log
+
+	err := h.app.DeleteCustomer(ctx, c.Param("id"))
+	if err != nil {
+		logger.ErrorContext(ctx, "error deleting customer", slog.String("error", err.Error()))
+		return domainError(err)
+	}
+
+	return c.NoContent(204)
+}
+
+func (h *Handlers) GetCustomerWallets(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := h.log
+
+	result, err := h.app.GetCustomerWallets(ctx, c.Param("id"))
+	if err != nil {
+		logger.ErrorContext(ctx, "error getting customer wallets", slog.String("error", err.Error()))
+		return domainError(err)
+	}
+
+	return c.JSON(200, WalletsToResponse(result))
+}
+
+// GetCustomerWalletsV2 is GetCustomerWallets with amounts sent as decimal
+// strings.
+func (h *Handlers) GetCustomerWalletsV2(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := h.log
+
+	result, err := h.app.GetCustomerWallets(ctx, c.Param("id"))
+	if err != nil {
+		logger.ErrorContext(ctx, "error getting customer wallets", slog.String("error", err.Error()))
+		return domainError(err)
+	}
+
+	return c.JSON(200, WalletsToResponseV2(result))
+}
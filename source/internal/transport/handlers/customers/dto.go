@@ -0,0 +1,160 @@
+package customers
+
+import (
+	"TestProject/source/internal/entities"
+	"time"
+)
+
+type CreateRequest struct {
+	Name  string `json:"name" validate:"required,max=255"`
+	Email string `json:"email" validate:"omitempty,email,max=255"`
+	Phone string `json:"phone" validate:"omitempty,e164"`
+}
+
+// UpdateRequest replaces the customer's name and contacts; omitted
+// contacts are cleared.
+type UpdateRequest struct {
+	CustomerId string `param:"id" validate:"required,uuid"`
+	Name       string `json:"name" validate:"required,max=255"`
+	Email      string `json:"email" validate:"omitempty,email,max=255"`
+	Phone      string `json:"phone" validate:"omitempty,e164"`
+}
+
+type ListRequest struct {
+	Cursor string `query:"cursor" validate:"omitempty,uuid"`
+	Limit  int    `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+type Response struct {
+	CustomerId string    `json:"customer_id"`
+	Name       string    `json:"name"`
+	Email      string    `json:"email,omitempty"`
+	Phone      string    `json:"phone,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// ListResponse.NextCursor is set when there may be more customers; pass it
+// as cursor to get them.
+type ListResponse struct {
+	Customers  []Response `json:"customers"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
+
+type WalletResponse struct {
+	WalletId         string  `json:"wallet_id"`
+	Currency         string  `json:"currency"`
+	Balance          float64 `json:"balance"`
+	AvailableBalance float64 `json:"available_balance"`
+	Status           string  `json:"status"`
+}
+
+type TotalResponse struct {
+	Currency         string  `json:"currency"`
+	Balance          float64 `json:"balance"`
+	AvailableBalance float64 `json:"available_balance"`
+}
+
+// WalletsResponse lists the customer's wallets. TotalBalance sums them per
+// currency.
+type WalletsResponse struct {
+	CustomerId   string           `json:"customer_id"`
+	Wallets      []WalletResponse `json:"wallets"`
+	TotalBalance []TotalResponse  `json:"total_balance"`
+}
+
+type WalletResponseV2 struct {
+	WalletId         string `json:"wallet_id"`
+	Currency         string `json:"currency"`
+	Balance          string `json:"balance"`
+	AvailableBalance string `json:"available_balance"`
+	Status           string `json:"status"`
+}
+
+type TotalResponseV2 struct {
+	Currency         string `json:"currency"`
+	Balance          string `json:"balance"`
+	AvailableBalance string `json:"available_balance"`
+}
+
+type WalletsResponseV2 struct {
+	CustomerId   string             `json:"customer_id"`
+	Wallets      []WalletResponseV2 `json:"wallets"`
+	TotalBalance []TotalResponseV2  `json:"total_balance"`
+}
+
+func EntityToResponse(customer entities.Customer) Response {
+	return Response{
+		CustomerId: customer.ID,
+		Name:       customer.Name,
+		Email:      customer.Email,
+		Phone:      customer.Phone,
+		CreatedAt:  customer.CreatedAt,
+		UpdatedAt:  customer.UpdatedAt,
+	}
+}
+
+func CustomersToResponse(customers []entities.Customer, limit int) ListResponse {
+	response := ListResponse{Customers: make([]Response, 0, len(customers))}
+	for _, customer := range customers {
+		response.Customers = append(response.Customers, EntityToResponse(customer))
+	}
+	if len(customers) == limit {
+		response.NextCursor = customers[len(customers)-1].ID
+	}
+	return response
+}
+
+func WalletsToResponse(result entities.CustomerWallets) WalletsResponse {
+	response := WalletsResponse{
+		CustomerId:   result.Customer.ID,
+		Wallets:      make([]WalletResponse, 0, len(result.Wallets)),
+		TotalBalance: make([]TotalResponse, 0, len(result.Totals)),
+	}
+	for _, wallet := range result.Wallets {
+		balance, _ := wallet.Balance.Float64()
+		available, _ := wallet.Available().Float64()
+		response.Wallets = append(response.Wallets, WalletResponse{
+			WalletId:         wallet.ID,
+			Currency:         wallet.Currency,
+			Balance:          balance,
+			AvailableBalance: available,
+			Status:           wallet.Status,
+		})
+	}
+	for _, total := range result.Totals {
+		balance, _ := total.Balance.Float64()
+		available, _ := total.Available.Float64()
+		response.TotalBalance = append(response.TotalBalance, TotalResponse{
+			Currency:         total.Currency,
+			Balance:          balance,
+			AvailableBalance: available,
+		})
+	}
+	return response
+}
+
+func WalletsToResponseV2(result entities.CustomerWallets) WalletsResponseV2 {
+	response := WalletsResponseV2{
+		CustomerId:   result.Customer.ID,
+		Wallets:      make([]WalletResponseV2, 0, len(result.Wallets)),
+		TotalBalance: make([]TotalResponseV2, 0, len(result.Totals)),
+	}
+	for _, wallet := range result.Wallets {
+		response.Wallets = append(response.Wallets, WalletResponseV2{
+			WalletId:         wallet.ID,
+			Currency:         wallet.Currency,
+			Balance:          entities.FormatAmount(wallet.Balance, wallet.Currency),
+			AvailableBalance: entities.FormatAmount(wallet.Available(), wallet.Currency),
+			Status:           wallet.Status,
+		})
+	}
+	for _, total := range result.Totals {
+		response.TotalBalance = append(response.TotalBalance, TotalResponseV2{
+			Currency:         total.Currency,
+			Balance:          entities.FormatAmount(total.Balance, total.Currency),
+			AvailableBalance: entities.FormatAmount(total.Available, total.Currency),
+		})
+	}
+	return response
+}
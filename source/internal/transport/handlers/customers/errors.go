@@ -0,0 +1,19 @@
+package customers
+
+import (
+	"TestProject/source/internal/entities"
+	"errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+func domainError(err error) *echo.HTTPError {
+	switch {
+	case errors.Is(err, entities.ErrCustomerNotFound):
+		return echo.ErrNotFound.SetInternal(err)
+	case errors.Is(err, entities.ErrCustomerExists),
+		errors.Is(err, entities.ErrCustomerHasWallets):
+		return echo.NewHTTPError(409, err.Error()).SetInternal(err)
+	}
+	return echo.ErrInternalServerError.SetInternal(err)
+}
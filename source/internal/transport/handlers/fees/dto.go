@@ -0,0 +1,135 @@
+package fees
+
+import (
+	"TestProject/source/internal/entities"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// RuleRequest sets the fee of one operation type. Amounts and percentages
+// are decimal strings; omitted values are zero. Default rules take the
+// currency from the path, wallet rules take the wallet currency.
+type RuleRequest struct {
+	WalletId      string        `param:"walletId" validate:"omitempty,uuid"`
+	OperationType string        `param:"operationType" validate:"required,oneof=DEPOSIT WITHDRAW"`
+	Currency      string        `param:"currency" validate:"omitempty,iso4217"`
+	Type          string        `json:"type" validate:"required,oneof=FLAT PERCENTAGE TIERED"`
+	Flat          string        `json:"flat"`
+	Percent       string        `json:"percent"`
+	Tiers         []TierRequest `json:"tiers" validate:"omitempty,max=100,dive"`
+	Min           string        `json:"min"`
+	Max           string        `json:"max"`
+}
+
+type TierRequest struct {
+	From    string `json:"from" validate:"required"`
+	Flat    string `json:"flat"`
+	Percent string `json:"percent"`
+}
+
+type RuleResponse struct {
+	WalletId      string         `json:"wallet_id,omitempty"`
+	OperationType string         `json:"operation_type"`
+	Currency      string         `json:"currency"`
+	Type          string         `json:"type"`
+	Flat          string         `json:"flat"`
+	Percent       string         `json:"percent"`
+	Tiers         []TierResponse `json:"tiers,omitempty"`
+	Min           string         `json:"min"`
+	Max           string         `json:"max"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
+
+type TierResponse struct {
+	From    string `json:"from"`
+	Flat    string `json:"flat"`
+	Percent string `json:"percent"`
+}
+
+type RulesResponse struct {
+	Rules []RuleResponse `json:"rules"`
+}
+
+func parseOptional(value string) (decimal.Decimal, error) {
+	if value == "" {
+		return decimal.Zero, nil
+	}
+	return entities.ParseAmount(value)
+}
+
+func FillRuleFromRequest(request RuleRequest) (entities.FeeRule, error) {
+	rule := entities.FeeRule{
+		WalletId:      request.WalletId,
+		OperationType: request.OperationType,
+		Currency:      request.Currency,
+		Type:          request.Type,
+	}
+
+	for _, field := range []struct {
+		value  string
+		target *decimal.Decimal
+	}{
+		{request.Flat, &rule.Flat},
+		{request.Percent, &rule.Percent},
+		{request.Min, &rule.Min},
+		{request.Max, &rule.Max},
+	} {
+		value, err := parseOptional(field.value)
+		if err != nil {
+			return entities.FeeRule{}, err
+		}
+		*field.target = value
+	}
+
+	for _, tierRequest := range request.Tiers {
+		var tier entities.FeeTier
+		for _, field := range []struct {
+			value  string
+			target *decimal.Decimal
+		}{
+			{tierRequest.From, &tier.From},
+			{tierRequest.Flat, &tier.Flat},
+			{tierRequest.Percent, &tier.Percent},
+		} {
+			value, err := parseOptional(field.value)
+			if err != nil {
+				return entities.FeeRule{}, err
+			}
+			*field.target = value
+		}
+		rule.Tiers = append(rule.Tiers, tier)
+	}
+
+	return rule, nil
+}
+
+func RuleToResponse(rule entities.FeeRule) RuleResponse {
+	response := RuleResponse{
+		WalletId:      rule.WalletId,
+		OperationType: rule.OperationType,
+		Currency:      rule.Currency,
+		Type:          rule.Type,
+		Flat:          entities.FormatAmount(rule.Flat, rule.Currency),
+		Percent:       rule.Percent.String(),
+		Min:           entities.FormatAmount(rule.Min, rule.Currency),
+		Max:           entities.FormatAmount(rule.Max, rule.Currency),
+		UpdatedAt:     rule.UpdatedAt,
+	}
+	for _, tier := range rule.Tiers {
+		response.Tiers = append(response.Tiers, TierResponse{
+			From:    entities.FormatAmount(tier.From, rule.Currency),
+			Flat:    entities.FormatAmount(tier.Flat, rule.Currency),
+			Percent: tier.Percent.String(),
+		})
+	}
+	return response
+}
+
+func RulesToResponse(rules []entities.FeeRule) RulesResponse {
+	response := RulesResponse{Rules: make([]RuleResponse, 0, len(rules))}
+	for _, rule := range rules {
+		response.Rules = append(response.Rules, RuleToResponse(rule))
+	}
+	return response
+}
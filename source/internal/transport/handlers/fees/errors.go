@@ -0,0 +1,23 @@
+package fees
+
+import (
+	"TestProject/source/internal/entities"
+	"errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+func domainError(err error) *echo.HTTPError {
+	switch {
+	case errors.Is(err, entities.ErrFeeRuleNotFound),
+		errors.Is(err, entities.ErrWalletNotFound):
+		return echo.ErrNotFound.SetInternal(err)
+	case errors.Is(err, entities.ErrInvalidFeeRule),
+		errors.Is(err, entities.ErrUnsupportedCurrency),
+		errors.Is(err, entities.ErrInvalidAmountPrecision),
+		errors.Is(err, entities.ErrInvalidAmount),
+		errors.Is(err, entities.ErrAmountOverflow):
+		return echo.NewHTTPError(400, err.Error()).SetInternal(err)
+	}
+	return echo.ErrInternalServerError.SetInternal(err)
+}
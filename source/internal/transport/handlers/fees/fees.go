@@ -0,0 +1,76 @@
+package fees
+
+import (
+	"log/slog"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ListDefaultFees returns the rules that apply to wallets without their own.
+func (h *Handlers) ListDefaultFees(c echo.Context) error {
+	return h.listFees(c, "")
+}
+
+func (h *Handlers) ListWalletFees(c echo.Context) error {
+	return h.listFees(c, c.Param("walletId"))
+}
+
+func (h *Handlers) listFees(c echo.Context, walletID string) error {
+	ctx := c.Request().Context()
+
+	rules, err := h.app.ListFeeRules(ctx, walletID)
+	if err != nil {
+		h.log.ErrorContext(ctx, "error listing fee rules", slog.String("error", err.Error()))
+		return domainError(err)
+	}
+
+	return c.JSON(200, RulesToResponse(rules))
+}
+
+// SetFee serves both the default and the wallet routes; the wallet ID or
+// the currency comes from the path.
+func (h *Handlers) SetFee(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	logger := h.log
+
+	var request RuleRequest
+
+	err := c.Bind(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error binding request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	err = c.Validate(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error validating request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	rule, err := FillRuleFromRequest(request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error when filling in the fee rule", slog.String("error", err.Error()))
+		return echo.NewHTTPError(400, err.Error()).SetInternal(err)
+	}
+
+	rule, err = h.app.SetFeeRule(ctx, rule)
+	if err != nil {
+		logger.ErrorContext(ctx, "error setting fee rule", slog.String("error", err.Error()))
+		return domainError(err)
+	}
+
+	return c.JSON(200, RuleToResponse(rule))
+}
+
+func (h *Handlers) DeleteFee(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	err := h.app.DeleteFeeRule(ctx, c.Param("walletId"), c.Param("operationType"), c.Param("currency"))
+	if err != nil {
+		h.log.ErrorContext(ctx, "error deleting fee rule", slog.String("error", err.Error()))
+		return domainError(err)
+	}
+
+	return c.NoContent(204)
+}
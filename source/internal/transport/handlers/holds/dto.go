@@ -0,0 +1,65 @@
+package holds
+
+import (
+	"TestProject/source/internal/entities"
+	"fmt"
+	"time"
+)
+
+type CreateRequest struct {
+	WalletId   string `param:"walletId" validate:"required,uuid"`
+	Currency   string `json:"currency" validate:"omitempty,iso4217"`
+	Amount     string `json:"amount" validate:"required"`
+	TTLSeconds int    `json:"ttl_seconds" validate:"omitempty,min=1"`
+}
+
+type CaptureRequest struct {
+	HoldId string `param:"holdId" validate:"required,uuid"`
+	Amount string `json:"amount"`
+}
+
+type Response struct {
+	HoldId         string    `json:"hold_id"`
+	WalletId       string    `json:"wallet_id"`
+	Currency       string    `json:"currency"`
+	Amount         string    `json:"amount"`
+	CapturedAmount string    `json:"captured_amount"`
+	Status         string    `json:"status"`
+	TransactionId  string    `json:"transaction_id,omitempty"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+func FillHoldFromRequest(hold entities.Hold, request CreateRequest) (entities.Hold, error) {
+	amount, err := entities.ParseAmount(request.Amount)
+	if err != nil {
+		return entities.Hold{}, err
+	}
+	if !amount.IsPositive() {
+		return entities.Hold{}, fmt.Errorf("%w: amount must be greater than zero", entities.ErrInvalidAmount)
+	}
+
+	hold.WalletId = request.WalletId
+	hold.Currency = request.Currency
+	hold.Amount = amount
+	if request.TTLSeconds > 0 {
+		hold.ExpiresAt = time.Now().UTC().Add(time.Duration(request.TTLSeconds) * time.Second)
+	}
+	return hold, nil
+}
+
+func EntityToResponse(hold entities.Hold) Response {
+	return Response{
+		HoldId:         hold.ID,
+		WalletId:       hold.WalletId,
+		Currency:       hold.Currency,
+		Amount:         entities.FormatAmount(hold.Amount, hold.Currency),
+		CapturedAmount: entities.FormatAmount(hold.CapturedAmount, hold.Currency),
+		Status:         hold.Status,
+		TransactionId:  hold.TransactionId,
+		ExpiresAt:      hold.ExpiresAt,
+		CreatedAt:      hold.CreatedAt,
+		UpdatedAt:      hold.UpdatedAt,
+	}
+}
@@ -0,0 +1,111 @@
+package holds
+
+import (
+	"TestProject/source/internal/entities"
+	"log/slog"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shopspring/decimal"
+)
+
+func (h *Handlers) CreateHold(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := h.log
+
+	var request CreateRequest
+
+	err := c.Bind(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error binding request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	err = c.Validate(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error validating request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	hold := entities.NewHold()
+
+	hold, err = FillHoldFromRequest(hold, request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error filling hold", slog.String("error", err.Error()))
+		return echo.NewHTTPError(400, err.Error()).SetInternal(err)
+	}
+
+	hold, err = h.app.CreateHold(ctx, hold)
+	if err != nil {
+		logger.ErrorContext(ctx, "error creating hold", slog.String("error", err.Error()))
+		return domainError(err)
+	}
+
+	return c.JSON(201, EntityToResponse(hold))
+}
+
+func (h *Handlers) GetHold(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := h.log
+
+	holdID := c.Param("holdId")
+
+	hold, err := h.app.HoldRepo.GetByID(ctx, holdID)
+	if err != nil {
+		logger.ErrorContext(ctx, "error getting hold", slog.String("error", err.Error()))
+		return domainError(err)
+	}
+
+	return c.JSON(200, EntityToResponse(hold))
+}
+
+func (h *Handlers) CaptureHold(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := h.log
+
+	var request CaptureRequest
+
+	err := c.Bind(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error binding request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	err = c.Validate(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error validating request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	var amount *decimal.Decimal
+	if request.Amount != "" {
+		parsed, err := entities.ParseAmount(request.Amount)
+		if err != nil || !parsed.IsPositive() {
+			logger.ErrorContext(ctx, "error parsing capture amount", slog.String("amount", request.Amount))
+			return echo.NewHTTPError(400, "amount must be a positive decimal string").SetInternal(err)
+		}
+		amount = &parsed
+	}
+
+	hold, _, err := h.app.CaptureHold(ctx, request.HoldId, amount)
+	if err != nil {
+		logger.ErrorContext(ctx, "error capturing hold", slog.String("error", err.Error()))
+		return domainError(err)
+	}
+
+	return c.JSON(200, EntityToResponse(hold))
+}
+
+func (h *Handlers) VoidHold(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := h.log
+
+	holdID := c.Param("holdId")
+
+	hold, err := h.app.VoidHold(ctx, holdID)
+	if err != nil {
+		logger.ErrorContext(ctx, "error voiding hold", slog.String("error", err.Error()))
+		return domainError(err)
+	}
+
+	return c.JSON(200, EntityToResponse(hold))
+}
@@ -0,0 +1,101 @@
+package interest
+
+import (
+	"TestProject/source/internal/entities"
+	"time"
+)
+
+// PlanRequest sets a wallet's interest plan. The annual rate is a decimal
+// string in percent, e.g. "7.5"; the other fields default to ACT_365,
+// HALF_UP and MONTHLY.
+type PlanRequest struct {
+	WalletId        string `param:"walletId" validate:"required,uuid"`
+	AnnualRate      string `json:"annual_rate" validate:"required"`
+	DayCount        string `json:"day_count" validate:"omitempty,oneof=ACT_365 ACT_360 ACT_ACT"`
+	Rounding        string `json:"rounding" validate:"omitempty,oneof=HALF_UP HALF_EVEN DOWN UP"`
+	PayoutFrequency string `json:"payout_frequency" validate:"omitempty,oneof=DAILY WEEKLY MONTHLY"`
+}
+
+type PlanResponse struct {
+	WalletId        string    `json:"wallet_id"`
+	Currency        string    `json:"currency"`
+	AnnualRate      string    `json:"annual_rate"`
+	DayCount        string    `json:"day_count"`
+	Rounding        string    `json:"rounding"`
+	PayoutFrequency string    `json:"payout_frequency"`
+	Accrued         string    `json:"accrued"`
+	AccruedThrough  string    `json:"accrued_through,omitempty"`
+	NextPayoutAt    time.Time `json:"next_payout_at"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+type PayoutResponse struct {
+	PayoutId      string    `json:"payout_id"`
+	PeriodEnd     time.Time `json:"period_end"`
+	Amount        string    `json:"amount"`
+	TransactionId string    `json:"transaction_id"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type PayoutsResponse struct {
+	Payouts []PayoutResponse `json:"payouts"`
+}
+
+// RunRequest accrues interest up to the last day finished before as_of,
+// now by default.
+type RunRequest struct {
+	AsOf *time.Time `json:"as_of"`
+}
+
+type RunResponse struct {
+	Plans int `json:"plans"`
+}
+
+func FillPlanFromRequest(request PlanRequest) (entities.InterestPlan, error) {
+	rate, err := entities.ParseAmount(request.AnnualRate)
+	if err != nil {
+		return entities.InterestPlan{}, err
+	}
+
+	return entities.InterestPlan{
+		WalletId:        request.WalletId,
+		AnnualRate:      rate,
+		DayCount:        request.DayCount,
+		Rounding:        request.Rounding,
+		PayoutFrequency: request.PayoutFrequency,
+	}, nil
+}
+
+func PlanToResponse(plan entities.InterestPlan) PlanResponse {
+	response := PlanResponse{
+		WalletId:        plan.WalletId,
+		Currency:        plan.Currency,
+		AnnualRate:      plan.AnnualRate.String(),
+		DayCount:        plan.DayCount,
+		Rounding:        plan.Rounding,
+		PayoutFrequency: plan.PayoutFrequency,
+		Accrued:         plan.Accrued.String(),
+		NextPayoutAt:    plan.NextPayoutAt,
+		CreatedAt:       plan.CreatedAt,
+		UpdatedAt:       plan.UpdatedAt,
+	}
+	if plan.NextAccrualDay.After(plan.CreatedAt) {
+		response.AccruedThrough = plan.NextAccrualDay.AddDate(0, 0, -1).Format(time.DateOnly)
+	}
+	return response
+}
+
+func PayoutsToResponse(payouts []entities.InterestPayout, currency string) PayoutsResponse {
+	response := PayoutsResponse{Payouts: make([]PayoutResponse, 0, len(payouts))}
+	for _, payout := range payouts {
+		response.Payouts = append(response.Payouts, PayoutResponse{
+			PayoutId:      payout.ID,
+			PeriodEnd:     payout.PeriodEnd,
+			Amount:        entities.FormatAmount(payout.Amount, currency),
+			TransactionId: payout.TransactionId,
+			CreatedAt:     payout.CreatedAt,
+		})
+	}
+	return response
+}
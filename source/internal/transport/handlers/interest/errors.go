@@ -0,0 +1,23 @@
+package interest
+
+import (
+	"TestProject/source/internal/entities"
+	"errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+func domainError(err error) *echo.HTTPError {
+	switch {
+	case errors.Is(err, entities.ErrInterestPlanNotFound),
+		errors.Is(err, entities.ErrWalletNotFound):
+		return echo.ErrNotFound.SetInternal(err)
+	case errors.Is(err, entities.ErrInterestPayoutFailed):
+		return echo.NewHTTPError(409, err.Error()).SetInternal(err)
+	case errors.Is(err, entities.ErrInvalidInterestPlan),
+		errors.Is(err, entities.ErrInvalidAmount),
+		errors.Is(err, entities.ErrAmountOverflow):
+		return echo.NewHTTPError(400, err.Error()).SetInternal(err)
+	}
+	return echo.ErrInternalServerError.SetInternal(err)
+}
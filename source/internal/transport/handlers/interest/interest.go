@@ -0,0 +1,133 @@
+package interest
+
+import (
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultPayoutsLimit = 50
+	maxPayoutsLimit     = 500
+)
+
+func (h *Handlers) GetPlan(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	plan, err := h.app.GetInterestPlan(ctx, c.Param("walletId"))
+	if err != nil {
+		h.log.ErrorContext(ctx, "error getting interest plan", slog.String("error", err.Error()))
+		return domainError(err)
+	}
+
+	return c.JSON(200, PlanToResponse(plan))
+}
+
+func (h *Handlers) SetPlan(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := h.log
+
+	var request PlanRequest
+
+	err := c.Bind(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error binding request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	err = c.Validate(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error validating request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	plan, err := FillPlanFromRequest(request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error filling interest plan", slog.String("error", err.Error()))
+		return echo.NewHTTPError(400, err.Error()).SetInternal(err)
+	}
+
+	plan, err = h.app.SetInterestPlan(ctx, plan)
+	if err != nil {
+		logger.ErrorContext(ctx, "error setting interest plan", slog.String("error", err.Error()))
+		return domainError(err)
+	}
+
+	return c.JSON(200, PlanToResponse(plan))
+}
+
+// DeletePlan stops the accrual and pays out the interest accrued so far.
+func (h *Handlers) DeletePlan(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	err := h.app.DeleteInterestPlan(ctx, c.Param("walletId"))
+	if err != nil {
+		h.log.ErrorContext(ctx, "error deleting interest plan", slog.String("error", err.Error()))
+		return domainError(err)
+	}
+
+	return c.NoContent(204)
+}
+
+func (h *Handlers) ListPayouts(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := h.log
+
+	walletID := c.Param("walletId")
+
+	limit := defaultPayoutsLimit
+	if raw := c.QueryParam("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > maxPayoutsLimit {
+			return echo.NewHTTPError(400, "limit must be between 1 and 500").SetInternal(err)
+		}
+		limit = parsed
+	}
+
+	plan, err := h.app.GetInterestPlan(ctx, walletID)
+	if err != nil {
+		logger.ErrorContext(ctx, "error getting interest plan", slog.String("error", err.Error()))
+		return domainError(err)
+	}
+
+	payouts, err := h.app.ListInterestPayouts(ctx, walletID, limit)
+	if err != nil {
+		logger.ErrorContext(ctx, "error listing interest payouts", slog.String("error", err.Error()))
+		return domainError(err)
+	}
+
+	return c.JSON(200, PayoutsToResponse(payouts, plan.Currency))
+}
+
+// RunInterest runs the accrual job now. Days that are already accrued are
+// skipped, so it is safe to repeat.
+func (h *Handlers) RunInterest(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := h.log
+
+	var request RunRequest
+
+	err := c.Bind(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error binding request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	asOf := time.Now()
+	if request.AsOf != nil {
+		if request.AsOf.After(asOf) {
+			return echo.NewHTTPError(400, "as_of is in the future")
+		}
+		asOf = *request.AsOf
+	}
+
+	plans, err := h.app.RunInterest(ctx, asOf)
+	if err != nil {
+		logger.ErrorContext(ctx, "error running interest accrual", slog.String("error", err.Error()))
+		return domainError(err)
+	}
+
+	return c.JSON(200, RunResponse{Plans: plans})
+}
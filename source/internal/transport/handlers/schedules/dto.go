@@ -0,0 +1,105 @@
+package schedules
+
+import (
+	"TestProject/source/internal/entities"
+	"fmt"
+	"time"
+)
+
+type CreateRequest struct {
+	WalletId      string     `param:"walletId" validate:"required,uuid"`
+	OperationType string     `json:"operation_type" validate:"required,oneof=DEPOSIT WITHDRAW"`
+	Currency      string     `json:"currency" validate:"omitempty,iso4217"`
+	Amount        string     `json:"amount" validate:"required"`
+	Frequency     string     `json:"frequency" validate:"required,oneof=ONCE DAILY WEEKLY MONTHLY"`
+	StartAt       *time.Time `json:"start_at"`
+	EndAt         *time.Time `json:"end_at"`
+}
+
+type Response struct {
+	ScheduleId    string     `json:"schedule_id"`
+	WalletId      string     `json:"wallet_id"`
+	OperationType string     `json:"operation_type"`
+	Currency      string     `json:"currency"`
+	Amount        string     `json:"amount"`
+	Frequency     string     `json:"frequency"`
+	Status        string     `json:"status"`
+	StartAt       time.Time  `json:"start_at"`
+	EndAt         *time.Time `json:"end_at,omitempty"`
+	NextRunAt     *time.Time `json:"next_run_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+type RunResponse struct {
+	RunId         string    `json:"run_id"`
+	Occurrence    int       `json:"occurrence"`
+	ScheduledAt   time.Time `json:"scheduled_at"`
+	Status        string    `json:"status"`
+	TransactionId string    `json:"transaction_id,omitempty"`
+	Error         string    `json:"error,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type RunsResponse struct {
+	Runs []RunResponse `json:"runs"`
+}
+
+func FillScheduleFromRequest(schedule entities.Schedule, request CreateRequest) (entities.Schedule, error) {
+	amount, err := entities.ParseAmount(request.Amount)
+	if err != nil {
+		return entities.Schedule{}, err
+	}
+	if !amount.IsPositive() {
+		return entities.Schedule{}, fmt.Errorf("%w: amount must be greater than zero", entities.ErrInvalidAmount)
+	}
+
+	schedule.WalletId = request.WalletId
+	schedule.OperationType = request.OperationType
+	schedule.Currency = request.Currency
+	schedule.Amount = amount
+	schedule.Frequency = request.Frequency
+	if request.StartAt != nil {
+		schedule.StartAt = *request.StartAt
+	}
+	schedule.EndAt = request.EndAt
+	return schedule, nil
+}
+
+func EntityToResponse(schedule entities.Schedule) Response {
+	response := Response{
+		ScheduleId:    schedule.ID,
+		WalletId:      schedule.WalletId,
+		OperationType: schedule.OperationType,
+		Currency:      schedule.Currency,
+		Amount:        entities.FormatAmount(schedule.Amount, schedule.Currency),
+		Frequency:     schedule.Frequency,
+		Status:        schedule.Status,
+		StartAt:       schedule.StartAt,
+		EndAt:         schedule.EndAt,
+		CreatedAt:     schedule.CreatedAt,
+		UpdatedAt:     schedule.UpdatedAt,
+	}
+	// A finished schedule has no next run.
+	if schedule.Status == entities.ScheduleActive || schedule.Status == entities.SchedulePaused {
+		nextRunAt := schedule.NextRunAt
+		response.NextRunAt = &nextRunAt
+	}
+	return response
+}
+
+func RunsToResponse(runs []entities.ScheduleRun) RunsResponse {
+	response := RunsResponse{Runs: make([]RunResponse, 0, len(runs))}
+	for _, run := range runs {
+		response.Runs = append(response.Runs, RunResponse{
+			RunId:         run.ID,
+			Occurrence:    run.Occurrence,
+			ScheduledAt:   run.ScheduledAt,
+			Status:        run.Status,
+			TransactionId: run.TransactionId,
+			Error:         run.Error,
+			CreatedAt:     run.CreatedAt,
+		})
+	}
+	return response
+}
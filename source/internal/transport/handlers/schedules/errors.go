@@ -0,0 +1,26 @@
+package schedules
+
+import (
+	"TestProject/source/internal/entities"
+	"errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+func domainError(err error) *echo.HTTPError {
+	switch {
+	case errors.Is(err, entities.ErrScheduleNotFound),
+		errors.Is(err, entities.ErrWalletNotFound):
+		return echo.ErrNotFound.SetInternal(err)
+	case errors.Is(err, entities.ErrScheduleNotActive):
+		return echo.NewHTTPError(409, err.Error()).SetInternal(err)
+	case errors.Is(err, entities.ErrInvalidSchedule),
+		errors.Is(err, entities.ErrInvalidOperation),
+		errors.Is(err, entities.ErrCurrencyMismatch),
+		errors.Is(err, entities.ErrInvalidAmountPrecision),
+		errors.Is(err, entities.ErrInvalidAmount),
+		errors.Is(err, entities.ErrAmountOverflow):
+		return echo.NewHTTPError(400, err.Error()).SetInternal(err)
+	}
+	return echo.ErrInternalServerError.SetInternal(err)
+}
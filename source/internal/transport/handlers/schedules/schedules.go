@@ -0,0 +1,133 @@
+package schedules
+
+import (
+	"TestProject/source/internal/entities"
+	"log/slog"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultRunsLimit = 50
+	maxRunsLimit     = 500
+)
+
+func (h *Handlers) CreateSchedule(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := h.log
+
+	var request CreateRequest
+
+	err := c.Bind(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error binding request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	err = c.Validate(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error validating request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	schedule := entities.NewSchedule()
+
+	schedule, err = FillScheduleFromRequest(schedule, request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error filling schedule", slog.String("error", err.Error()))
+		return echo.NewHTTPError(400, err.Error()).SetInternal(err)
+	}
+
+	schedule, err = h.app.CreateSchedule(ctx, schedule)
+	if err != nil {
+		logger.ErrorContext(ctx, "error creating schedule", slog.String("error", err.Error()))
+		return domainError(err)
+	}
+
+	return c.JSON(201, EntityToResponse(schedule))
+}
+
+func (h *Handlers) GetSchedule(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := h.log
+
+	scheduleID := c.Param("scheduleId")
+
+	schedule, err := h.app.ScheduleRepo.GetByID(ctx, scheduleID)
+	if err != nil {
+		logger.ErrorContext(ctx, "error getting schedule", slog.String("error", err.Error()))
+		return domainError(err)
+	}
+
+	return c.JSON(200, EntityToResponse(schedule))
+}
+
+func (h *Handlers) ListScheduleRuns(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := h.log
+
+	scheduleID := c.Param("scheduleId")
+
+	limit := defaultRunsLimit
+	if raw := c.QueryParam("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > maxRunsLimit {
+			return echo.NewHTTPError(400, "limit must be between 1 and 500").SetInternal(err)
+		}
+		limit = parsed
+	}
+
+	_, err := h.app.ScheduleRepo.GetByID(ctx, scheduleID)
+	if err != nil {
+		logger.ErrorContext(ctx, "error getting schedule", slog.String("error", err.Error()))
+		return domainError(err)
+	}
+
+	runs, err := h.app.ScheduleRepo.ListRuns(ctx, scheduleID, limit)
+	if err != nil {
+		logger.ErrorContext(ctx, "error listing schedule runs", slog.String("error", err.Error()))
+		return domainError(err)
+	}
+
+	return c.JSON(200, RunsToResponse(runs))
+}
+
+func (h *Handlers) PauseSchedule(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := h.log
+
+	schedule, err := h.app.PauseSchedule(ctx, c.Param("scheduleId"))
+	if err != nil {
+		logger.ErrorContext(ctx, "error pausing schedule", slog.String("error", err.Error()))
+		return domainError(err)
+	}
+
+	return c.JSON(200, EntityToResponse(schedule))
+}
+
+func (h *Handlers) ResumeSchedule(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := h.log
+
+	schedule, err := h.app.ResumeSchedule(ctx, c.Param("scheduleId"))
+	if err != nil {
+		logger.ErrorContext(ctx, "error resuming schedule", slog.String("error", err.Error()))
+		return domainError(err)
+	}
+
+	return c.JSON(200, EntityToResponse(schedule))
+}
+
+func (h *Handlers) CancelSchedule(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := h.log
+
+	schedule, err := h.app.CancelSchedule(ctx, c.Param("scheduleId"))
+	if err != nil {
+		logger.ErrorContext(ctx, "error cancelling schedule", slog.String("error", err.Error()))
+		return domainError(err)
+	}
+
+	return c.JSON(200, EntityToResponse(schedule))
+}
@@ -0,0 +1,123 @@
+package transactions
+
+import (
+	"TestProject/source/internal/entities"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (h *Handlers) CreateBatch(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := h.log
+
+	var request BatchRequest
+
+	err := c.Bind(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error binding request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	err = c.Validate(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error validating request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	transactions := make([]entities.Transaction, 0, len(request.Items))
+	for _, item := range request.Items {
+		transaction, err := FillTransactionFromRequest(entities.NewTransaction(), item)
+		if err != nil {
+			logger.ErrorContext(ctx, "error filling transaction", slog.String("error", err.Error()))
+			return echo.ErrBadRequest.SetInternal(err)
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	mode := batchMode(request.Mode)
+	results, err := h.app.ProcessBatch(ctx, mode, transactions)
+	if err != nil {
+		logger.ErrorContext(ctx, "error processing batch", slog.String("error", err.Error()))
+		return batchError(err)
+	}
+
+	return c.JSON(200, BatchToResponse(mode, results))
+}
+
+// CreateBatchV2 is CreateBatch with amounts sent as decimal strings.
+func (h *Handlers) CreateBatchV2(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := h.log
+
+	var request BatchRequestV2
+
+	err := c.Bind(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error binding request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	err = c.Validate(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error validating request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	transactions := make([]entities.Transaction, 0, len(request.Items))
+	for i, item := range request.Items {
+		transaction, err := FillTransactionFromRequestV2(entities.NewTransaction(), item)
+		if err != nil {
+			logger.ErrorContext(ctx, "error filling transaction", slog.String("error", err.Error()))
+			return echo.NewHTTPError(400, map[string]any{"message": err.Error(), "index": i}).SetInternal(err)
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	mode := batchMode(request.Mode)
+	results, err := h.app.ProcessBatch(ctx, mode, transactions)
+	if err != nil {
+		logger.ErrorContext(ctx, "error processing batch", slog.String("error", err.Error()))
+		return batchError(err)
+	}
+
+	return c.JSON(200, BatchToResponseV2(mode, results))
+}
+
+func batchMode(mode string) string {
+	if mode == "" {
+		return entities.BatchAllOrNothing
+	}
+	return mode
+}
+
+// batchError reports the index of the operation that rolled an
+// ALL_OR_NOTHING batch back, with the status that operation would get alone.
+func batchError(err error) *echo.HTTPError {
+	var itemErr *entities.BatchItemError
+	if !errors.As(err, &itemErr) {
+		if errors.Is(err, entities.ErrInvalidBatch) {
+			return echo.NewHTTPError(400, err.Error()).SetInternal(err)
+		}
+		return echo.ErrInternalServerError.SetInternal(err)
+	}
+
+	code, message := itemError(itemErr.Err)
+	return echo.NewHTTPError(code, map[string]any{"message": message, "index": itemErr.Index}).SetInternal(err)
+}
+
+// itemError turns the error of one batch operation into the status code and
+// message the single-operation endpoints would return for it.
+func itemError(err error) (int, string) {
+	httpErr := domainError(err)
+	switch message := httpErr.Message.(type) {
+	case string:
+		return httpErr.Code, message
+	case map[string]string:
+		return httpErr.Code, message["message"]
+	default:
+		return httpErr.Code, fmt.Sprint(message)
+	}
+}
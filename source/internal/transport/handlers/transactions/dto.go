@@ -1,27 +1,232 @@
 package transactions
 
-import "TestProject/source/internal/entities"
+import (
+	"TestProject/source/internal/entities"
+	"time"
+)
 
 type Request struct {
 	WalletId      string  `json:"wallet_id" validate:"required,uuid"`
 	OperationType string  `json:"operation_type" validate:"required,oneof=DEPOSIT WITHDRAW"`
+	Currency      string  `json:"currency" validate:"omitempty,iso4217"`
 	Amount        float32 `json:"amount" validate:"required,gt=0"`
 }
 
 type Response struct {
-	WalletId      string  `json:"wallet_id"`
-	OperationType string  `json:"operation_type"`
-	Amount        float32 `json:"amount"`
-	Balance       float64 `json:"balance,omitempty"`
+	TransactionId string    `json:"transaction_id"`
+	WalletId      string    `json:"wallet_id"`
+	OperationType string    `json:"operation_type"`
+	Currency      string    `json:"currency"`
+	Amount        float32   `json:"amount"`
+	Fee           float32   `json:"fee"`
+	Balance       float64   `json:"balance,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
 }
 
 func EntityToResponse(transaction entities.Transaction) Response {
 	amount64, _ := transaction.Amount.Float64()
+	fee64, _ := transaction.Fee.Float64()
 	balance, _ := transaction.Balance.Float64()
 	return Response{
+		TransactionId: transaction.ID,
 		WalletId:      transaction.WalletId,
 		OperationType: transaction.OperationType,
+		Currency:      transaction.Currency,
 		Amount:        float32(amount64),
+		Fee:           float32(fee64),
 		Balance:       balance,
+		CreatedAt:     transaction.CreatedAt,
 	}
 }
+
+type TransferRequest struct {
+	FromWalletId string  `json:"from_wallet_id" validate:"required,uuid"`
+	ToWalletId   string  `json:"to_wallet_id" validate:"required,uuid,nefield=FromWalletId"`
+	Currency     string  `json:"currency" validate:"omitempty,iso4217"`
+	Amount       float32 `json:"amount" validate:"required,gt=0"`
+}
+
+type TransferResponse struct {
+	TransferId   string    `json:"transfer_id"`
+	FromWalletId string    `json:"from_wallet_id"`
+	ToWalletId   string    `json:"to_wallet_id"`
+	Currency     string    `json:"currency"`
+	Amount       float32   `json:"amount"`
+	FromBalance  float64   `json:"from_balance"`
+	ToBalance    float64   `json:"to_balance"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func TransferToResponse(transfer entities.Transfer) TransferResponse {
+	amount64, _ := transfer.Amount.Float64()
+	fromBalance, _ := transfer.FromBalance.Float64()
+	toBalance, _ := transfer.ToBalance.Float64()
+	return TransferResponse{
+		TransferId:   transfer.ID,
+		FromWalletId: transfer.FromWalletId,
+		ToWalletId:   transfer.ToWalletId,
+		Currency:     transfer.Currency,
+		Amount:       float32(amount64),
+		FromBalance:  fromBalance,
+		ToBalance:    toBalance,
+		CreatedAt:    transfer.CreatedAt,
+	}
+}
+
+// RequestV2 carries the amount as a decimal string, e.g. "0.10".
+type RequestV2 struct {
+	WalletId      string `json:"wallet_id" validate:"required,uuid"`
+	OperationType string `json:"operation_type" validate:"required,oneof=DEPOSIT WITHDRAW"`
+	Currency      string `json:"currency" validate:"omitempty,iso4217"`
+	Amount        string `json:"amount" validate:"required"`
+}
+
+type ResponseV2 struct {
+	TransactionId string    `json:"transaction_id"`
+	WalletId      string    `json:"wallet_id"`
+	OperationType string    `json:"operation_type"`
+	Currency      string    `json:"currency"`
+	Amount        string    `json:"amount"`
+	Fee           string    `json:"fee"`
+	Balance       string    `json:"balance"`
+	ReferenceId   string    `json:"reference_id,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func EntityToResponseV2(transaction entities.Transaction) ResponseV2 {
+	return ResponseV2{
+		TransactionId: transaction.ID,
+		WalletId:      transaction.WalletId,
+		OperationType: transaction.OperationType,
+		Currency:      transaction.Currency,
+		Amount:        entities.FormatAmount(transaction.Amount, transaction.Currency),
+		Fee:           entities.FormatAmount(transaction.Fee, transaction.Currency),
+		Balance:       entities.FormatAmount(transaction.Balance, transaction.Currency),
+		ReferenceId:   transaction.ReferenceId,
+		CreatedAt:     transaction.CreatedAt,
+	}
+}
+
+// ReversalRequest reverses the transaction in the path. Without an amount the
+// whole not yet reversed remainder is reversed. AdminOverride is only read to
+// reject it outside the admin route, which always overrides.
+type ReversalRequest struct {
+	TransactionId string `param:"transactionId" validate:"required,uuid"`
+	Amount        string `json:"amount"`
+	AdminOverride bool   `json:"admin_override"`
+}
+
+type TransferRequestV2 struct {
+	FromWalletId string `json:"from_wallet_id" validate:"required,uuid"`
+	ToWalletId   string `json:"to_wallet_id" validate:"required,uuid,nefield=FromWalletId"`
+	Currency     string `json:"currency" validate:"omitempty,iso4217"`
+	Amount       string `json:"amount" validate:"required"`
+}
+
+type TransferResponseV2 struct {
+	TransferId   string    `json:"transfer_id"`
+	FromWalletId string    `json:"from_wallet_id"`
+	ToWalletId   string    `json:"to_wallet_id"`
+	Currency     string    `json:"currency"`
+	Amount       string    `json:"amount"`
+	FromBalance  string    `json:"from_balance"`
+	ToBalance    string    `json:"to_balance"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func TransferToResponseV2(transfer entities.Transfer) TransferResponseV2 {
+	return TransferResponseV2{
+		TransferId:   transfer.ID,
+		FromWalletId: transfer.FromWalletId,
+		ToWalletId:   transfer.ToWalletId,
+		Currency:     transfer.Currency,
+		Amount:       entities.FormatAmount(transfer.Amount, transfer.Currency),
+		FromBalance:  entities.FormatAmount(transfer.FromBalance, transfer.Currency),
+		ToBalance:    entities.FormatAmount(transfer.ToBalance, transfer.Currency),
+		CreatedAt:    transfer.CreatedAt,
+	}
+}
+
+// BatchRequest runs up to 1000 operations. Mode defaults to ALL_OR_NOTHING.
+type BatchRequest struct {
+	Mode  string    `json:"mode" validate:"omitempty,oneof=ALL_OR_NOTHING BEST_EFFORT"`
+	Items []Request `json:"items" validate:"required,min=1,max=1000,dive"`
+}
+
+type BatchItemResponse struct {
+	Index       int       `json:"index"`
+	Status      string    `json:"status"`
+	Transaction *Response `json:"transaction,omitempty"`
+	Code        int       `json:"code,omitempty"`
+	Error       string    `json:"error,omitempty"`
+}
+
+type BatchResponse struct {
+	Mode      string              `json:"mode"`
+	Succeeded int                 `json:"succeeded"`
+	Failed    int                 `json:"failed"`
+	Results   []BatchItemResponse `json:"results"`
+}
+
+func BatchToResponse(mode string, results []entities.BatchResult) BatchResponse {
+	response := BatchResponse{Mode: mode, Results: make([]BatchItemResponse, 0, len(results))}
+	for _, result := range results {
+		item := BatchItemResponse{Index: result.Index}
+		if result.Err != nil {
+			item.Status = batchItemFailed
+			item.Code, item.Error = itemError(result.Err)
+			response.Failed++
+		} else {
+			transaction := EntityToResponse(result.Transaction)
+			item.Status = batchItemSucceeded
+			item.Transaction = &transaction
+			response.Succeeded++
+		}
+		response.Results = append(response.Results, item)
+	}
+	return response
+}
+
+type BatchRequestV2 struct {
+	Mode  string      `json:"mode" validate:"omitempty,oneof=ALL_OR_NOTHING BEST_EFFORT"`
+	Items []RequestV2 `json:"items" validate:"required,min=1,max=1000,dive"`
+}
+
+type BatchItemResponseV2 struct {
+	Index       int         `json:"index"`
+	Status      string      `json:"status"`
+	Transaction *ResponseV2 `json:"transaction,omitempty"`
+	Code        int         `json:"code,omitempty"`
+	Error       string      `json:"error,omitempty"`
+}
+
+type BatchResponseV2 struct {
+	Mode      string                `json:"mode"`
+	Succeeded int                   `json:"succeeded"`
+	Failed    int                   `json:"failed"`
+	Results   []BatchItemResponseV2 `json:"results"`
+}
+
+func BatchToResponseV2(mode string, results []entities.BatchResult) BatchResponseV2 {
+	response := BatchResponseV2{Mode: mode, Results: make([]BatchItemResponseV2, 0, len(results))}
+	for _, result := range results {
+		item := BatchItemResponseV2{Index: result.Index}
+		if result.Err != nil {
+			item.Status = batchItemFailed
+			item.Code, item.Error = itemError(result.Err)
+			response.Failed++
+		} else {
+			transaction := EntityToResponseV2(result.Transaction)
+			item.Status = batchItemSucceeded
+			item.Transaction = &transaction
+			response.Succeeded++
+		}
+		response.Results = append(response.Results, item)
+	}
+	return response
+}
+
+const (
+	batchItemSucceeded = "SUCCEEDED"
+	batchItemFailed    = "FAILED"
+)
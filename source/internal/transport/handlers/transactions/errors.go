@@ -0,0 +1,48 @@
+package transactions
+
+import (
+	"TestProject/source/internal/entities"
+	"errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+// domainError maps application errors to HTTP errors shared by all
+// transaction endpoints.
+func domainError(err error) *echo.HTTPError {
+	switch {
+	case errors.Is(err, entities.ErrInsufficientFunds):
+		return echo.NewHTTPError(400, "insufficient funds").SetInternal(err)
+	case errors.Is(err, entities.ErrLimitExceeded):
+		return limitError(err)
+	case errors.Is(err, entities.ErrWalletFrozen),
+		errors.Is(err, entities.ErrWalletClosed):
+		return echo.NewHTTPError(403, err.Error()).SetInternal(err)
+	case errors.Is(err, entities.ErrSameWalletTransfer):
+		return echo.NewHTTPError(400, "cannot transfer to the same wallet").SetInternal(err)
+	case errors.Is(err, entities.ErrCurrencyMismatch),
+		errors.Is(err, entities.ErrInvalidAmountPrecision),
+		errors.Is(err, entities.ErrUnsupportedCurrency),
+		errors.Is(err, entities.ErrInvalidAmount),
+		errors.Is(err, entities.ErrAmountOverflow):
+		return echo.NewHTTPError(400, err.Error()).SetInternal(err)
+	case errors.Is(err, entities.ErrReversalExceedsOriginal):
+		return echo.NewHTTPError(400, err.Error()).SetInternal(err)
+	case errors.Is(err, entities.ErrNotReversible):
+		return echo.NewHTTPError(409, err.Error()).SetInternal(err)
+	case errors.Is(err, entities.ErrWalletNotFound),
+		errors.Is(err, entities.ErrTransactionNotFound):
+		return echo.ErrNotFound.SetInternal(err)
+	}
+	return echo.ErrInternalServerError.SetInternal(err)
+}
+
+// limitError reports the name of the broken limit next to the message.
+func limitError(err error) *echo.HTTPError {
+	message := map[string]string{"message": err.Error()}
+	var limitErr *entities.LimitExceededError
+	if errors.As(err, &limitErr) {
+		message["limit"] = limitErr.Limit
+	}
+	return echo.NewHTTPError(422, message).SetInternal(err)
+}
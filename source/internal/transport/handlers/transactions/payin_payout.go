@@ -2,7 +2,7 @@ package transactions
 
 import (
 	"TestProject/source/internal/entities"
-	"errors"
+	"fmt"
 	"log/slog"
 
 	"github.com/labstack/echo/v4"
@@ -40,10 +40,7 @@ func (h *Handlers) CreateTransaction(c echo.Context) error {
 	transaction, err = h.app.ProcessTransaction(ctx, transaction)
 	if err != nil {
 		logger.ErrorContext(ctx, "error processing transaction", slog.String("error", err.Error()))
-		if errors.Is(err, entities.ErrInsufficientFunds) {
-			return echo.NewHTTPError(400, "insufficient funds").SetInternal(err)
-		}
-		return echo.ErrInternalServerError.SetInternal(err)
+		return domainError(err)
 	}
 
 	response := EntityToResponse(transaction)
@@ -51,9 +48,74 @@ func (h *Handlers) CreateTransaction(c echo.Context) error {
 	return c.JSON(201, response)
 }
 
+// CreateTransactionV2 is CreateTransaction with amounts sent as decimal strings.
+func (h *Handlers) CreateTransactionV2(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := h.log
+
+	var request RequestV2
+
+	err := c.Bind(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error binding request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	err = c.Validate(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error validating request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	transaction := entities.NewTransaction()
+
+	transaction, err = FillTransactionFromRequestV2(transaction, request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error filling transaction",
+			slog.String("error", err.Error()),
+		)
+		return echo.NewHTTPError(400, err.Error()).SetInternal(err)
+	}
+
+	transaction, err = h.app.ProcessTransaction(ctx, transaction)
+	if err != nil {
+		logger.ErrorContext(ctx, "error processing transaction", slog.String("error", err.Error()))
+		return domainError(err)
+	}
+
+	response := EntityToResponseV2(transaction)
+
+	return c.JSON(201, response)
+}
+
 func FillTransactionFromRequest(transaction entities.Transaction, request Request) (entities.Transaction, error) {
 	transaction.WalletId = request.WalletId
 	transaction.OperationType = request.OperationType
+	transaction.Currency = request.Currency
 	transaction.Amount = decimal.NewFromFloat32(request.Amount)
 	return transaction, nil
 }
+
+func FillTransactionFromRequestV2(transaction entities.Transaction, request RequestV2) (entities.Transaction, error) {
+	amount, err := parsePositiveAmount(request.Amount)
+	if err != nil {
+		return entities.Transaction{}, err
+	}
+
+	transaction.WalletId = request.WalletId
+	transaction.OperationType = request.OperationType
+	transaction.Currency = request.Currency
+	transaction.Amount = amount
+	return transaction, nil
+}
+
+func parsePositiveAmount(value string) (decimal.Decimal, error) {
+	amount, err := entities.ParseAmount(value)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+	if !amount.IsPositive() {
+		return decimal.Decimal{}, fmt.Errorf("%w: amount must be greater than zero", entities.ErrInvalidAmount)
+	}
+	return amount, nil
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/shopspring/decimal"
@@ -92,8 +93,10 @@ func TestHandlers_CreateTransaction_Success(t *testing.T) {
 	handlers, e := setupTestHandler()
 
 	expectedBalance := 1500.0
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
 	handlers.app.processFunc = func(ctx interface{}, transaction entities.Transaction) (entities.Transaction, error) {
 		transaction.Balance = decimal.NewFromFloat(expectedBalance)
+		transaction.CreatedAt = createdAt
 		return transaction, nil
 	}
 
@@ -120,6 +123,8 @@ func TestHandlers_CreateTransaction_Success(t *testing.T) {
 	assert.Equal(t, reqBody.OperationType, response.OperationType)
 	assert.Equal(t, reqBody.Amount, response.Amount)
 	assert.Equal(t, expectedBalance, response.Balance)
+	assert.NotEmpty(t, response.TransactionId)
+	assert.True(t, createdAt.Equal(response.CreatedAt))
 }
 
 func TestHandlers_CreateTransaction_InsufficientFunds(t *testing.T) {
@@ -158,3 +163,52 @@ func TestHandlers_CreateTransaction_InvalidRequest(t *testing.T) {
 	err := handlers.CreateTransaction(c)
 	require.Error(t, err)
 }
+
+func TestFillTransactionFromRequestV2_ExactAmounts(t *testing.T) {
+	for _, amount := range []string{"0.1", "16777217", "9999999999999.99"} {
+		transaction, err := FillTransactionFromRequestV2(entities.NewTransaction(), RequestV2{
+			WalletId:      "123e4567-e89b-12d3-a456-426614174000",
+			OperationType: "DEPOSIT",
+			Amount:        amount,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, amount, transaction.Amount.String())
+	}
+}
+
+func TestFillTransactionFromRequestV2_InvalidAmounts(t *testing.T) {
+	tests := []struct {
+		amount  string
+		wantErr error
+	}{
+		{amount: "10000000000000", wantErr: entities.ErrAmountOverflow},
+		{amount: "1e3", wantErr: entities.ErrInvalidAmount},
+		{amount: "abc", wantErr: entities.ErrInvalidAmount},
+		{amount: "0", wantErr: entities.ErrInvalidAmount},
+		{amount: "-5", wantErr: entities.ErrInvalidAmount},
+	}
+
+	for _, tt := range tests {
+		_, err := FillTransactionFromRequestV2(entities.NewTransaction(), RequestV2{
+			WalletId:      "123e4567-e89b-12d3-a456-426614174000",
+			OperationType: "DEPOSIT",
+			Amount:        tt.amount,
+		})
+		assert.ErrorIs(t, err, tt.wantErr, "amount %s", tt.amount)
+	}
+}
+
+func TestEntityToResponseV2_FormatsWithCurrencyScale(t *testing.T) {
+	transaction := entities.NewTransaction()
+	transaction.Currency = "USD"
+	transaction.Amount = decimal.RequireFromString("0.1")
+	transaction.Balance = decimal.RequireFromString("16777217")
+
+	response := EntityToResponseV2(transaction)
+	assert.Equal(t, "0.10", response.Amount)
+	assert.Equal(t, "16777217.00", response.Balance)
+
+	body, err := json.Marshal(response)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `"amount":"0.10"`)
+}
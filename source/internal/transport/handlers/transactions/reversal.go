@@ -0,0 +1,69 @@
+package transactions
+
+import (
+	"log/slog"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shopspring/decimal"
+)
+
+// CreateReversal reverses a transaction within the wallet's available funds.
+// admin_override is rejected here: it is only accepted on the back-office
+// route, CreateAdminReversal.
+func (h *Handlers) CreateReversal(c echo.Context) error {
+	return h.createReversal(c, false)
+}
+
+// CreateAdminReversal reverses a transaction with the admin override: holds
+// and a frozen wallet do not stop it, the overdraft floor does.
+func (h *Handlers) CreateAdminReversal(c echo.Context) error {
+	return h.createReversal(c, true)
+}
+
+func (h *Handlers) createReversal(c echo.Context, adminOverride bool) error {
+	ctx := c.Request().Context()
+	logger := h.log
+
+	var request ReversalRequest
+
+	err := c.Bind(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error binding request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	err = c.Validate(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error validating request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	if request.AdminOverride && !adminOverride {
+		logger.ErrorContext(ctx, "admin override on the public reversal route")
+		return echo.NewHTTPError(400, "admin_override is only accepted on /api/v2/admin/transactions/:transactionId/reversals")
+	}
+
+	var amount *decimal.Decimal
+	if request.Amount != "" {
+		parsed, err := parsePositiveAmount(request.Amount)
+		if err != nil {
+			logger.ErrorContext(ctx, "error parsing reversal amount", slog.String("error", err.Error()))
+			return echo.NewHTTPError(400, err.Error()).SetInternal(err)
+		}
+		amount = &parsed
+	}
+
+	if adminOverride {
+		logger.WarnContext(ctx, "reversal with admin override",
+			slog.String("transaction_id", request.TransactionId),
+		)
+	}
+
+	reversal, err := h.app.ReverseTransaction(ctx, request.TransactionId, amount, adminOverride)
+	if err != nil {
+		logger.ErrorContext(ctx, "error reversing transaction", slog.String("error", err.Error()))
+		return domainError(err)
+	}
+
+	return c.JSON(201, EntityToResponseV2(reversal))
+}
@@ -0,0 +1,33 @@
+package transactions
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlers_CreateReversal_RejectsAdminOverride(t *testing.T) {
+	// The request is rejected before the application is called.
+	handlers := NewHandlers(slog.Default(), nil)
+	e := echo.New()
+	e.Validator = &testValidator{}
+
+	body := `{"amount": "100.00", "admin_override": true}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/transactions/123e4567-e89b-12d3-a456-426614174000/reversals", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("transactionId")
+	c.SetParamValues("123e4567-e89b-12d3-a456-426614174000")
+
+	err := handlers.CreateReversal(c)
+	httpErr, ok := err.(*echo.HTTPError)
+	require.True(t, ok, "expected HTTPError")
+	assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+}
@@ -0,0 +1,109 @@
+package transactions
+
+import (
+	"TestProject/source/internal/entities"
+	"log/slog"
+
+	"github.com/labstack/echo/v4"
+	"github.com/shopspring/decimal"
+)
+
+func (h *Handlers) CreateTransfer(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := h.log
+
+	var request TransferRequest
+
+	err := c.Bind(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error binding request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	err = c.Validate(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error validating request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	transfer := entities.NewTransfer()
+
+	transfer, err = FillTransferFromRequest(transfer, request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error filling transfer",
+			slog.String("error", err.Error()),
+		)
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	transfer, err = h.app.Transfer(ctx, transfer)
+	if err != nil {
+		logger.ErrorContext(ctx, "error processing transfer", slog.String("error", err.Error()))
+		return domainError(err)
+	}
+
+	response := TransferToResponse(transfer)
+
+	return c.JSON(201, response)
+}
+
+// CreateTransferV2 is CreateTransfer with amounts sent as decimal strings.
+func (h *Handlers) CreateTransferV2(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := h.log
+
+	var request TransferRequestV2
+
+	err := c.Bind(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error binding request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	err = c.Validate(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error validating request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	transfer := entities.NewTransfer()
+
+	transfer, err = FillTransferFromRequestV2(transfer, request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error filling transfer",
+			slog.String("error", err.Error()),
+		)
+		return echo.NewHTTPError(400, err.Error()).SetInternal(err)
+	}
+
+	transfer, err = h.app.Transfer(ctx, transfer)
+	if err != nil {
+		logger.ErrorContext(ctx, "error processing transfer", slog.String("error", err.Error()))
+		return domainError(err)
+	}
+
+	response := TransferToResponseV2(transfer)
+
+	return c.JSON(201, response)
+}
+
+func FillTransferFromRequest(transfer entities.Transfer, request TransferRequest) (entities.Transfer, error) {
+	transfer.FromWalletId = request.FromWalletId
+	transfer.ToWalletId = request.ToWalletId
+	transfer.Currency = request.Currency
+	transfer.Amount = decimal.NewFromFloat32(request.Amount)
+	return transfer, nil
+}
+
+func FillTransferFromRequestV2(transfer entities.Transfer, request TransferRequestV2) (entities.Transfer, error) {
+	amount, err := parsePositiveAmount(request.Amount)
+	if err != nil {
+		return entities.Transfer{}, err
+	}
+
+	transfer.FromWalletId = request.FromWalletId
+	transfer.ToWalletId = request.ToWalletId
+	transfer.Currency = request.Currency
+	transfer.Amount = amount
+	return transfer, nil
+}
@@ -2,6 +2,8 @@ package wallet
 
 import (
 	"TestProject/source/internal/entities"
+	"errors"
+	"fmt"
 	"log/slog"
 
 	"github.com/labstack/echo/v4"
@@ -37,11 +39,11 @@ func (h *Handlers) CreateWallet(c echo.Context) error {
 		return echo.ErrConflict.SetInternal(err)
 	}
 
-	wallet, err = h.app.WalletRepo.Create(ctx, wallet)
+	wallet, err = h.app.CreateWallet(ctx, wallet)
 	if err != nil {
 		logger.ErrorContext(ctx, "error creating wallet", slog.String("error", err.Error()))
 
-		return echo.ErrInternalServerError.SetInternal(err)
+		return createWalletError(err)
 	}
 
 	response := EntityToResponse(wallet)
@@ -49,7 +51,83 @@ func (h *Handlers) CreateWallet(c echo.Context) error {
 	return c.JSON(200, response)
 }
 
+// CreateWalletV2 is CreateWallet with the balance sent as a decimal string.
+func (h *Handlers) CreateWalletV2(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	logger := h.log
+
+	var request RequestV2
+
+	err := c.Bind(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error binding request", slog.String("error", err.Error()))
+
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	err = c.Validate(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error validating request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	wallet := entities.NewWallet()
+
+	wallet, err = FillWalletFromRequestV2(wallet, request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error when filling in the wallet's fields", slog.String("error", err.Error()))
+
+		return echo.NewHTTPError(400, err.Error()).SetInternal(err)
+	}
+
+	wallet, err = h.app.CreateWallet(ctx, wallet)
+	if err != nil {
+		logger.ErrorContext(ctx, "error creating wallet", slog.String("error", err.Error()))
+
+		return createWalletError(err)
+	}
+
+	response := EntityToResponseV2(wallet)
+
+	return c.JSON(200, response)
+}
+
+func createWalletError(err error) *echo.HTTPError {
+	if errors.Is(err, entities.ErrUnsupportedCurrency) ||
+		errors.Is(err, entities.ErrInvalidAmountPrecision) ||
+		errors.Is(err, entities.ErrAmountOverflow) {
+		return echo.NewHTTPError(400, err.Error()).SetInternal(err)
+	}
+	if errors.Is(err, entities.ErrCustomerNotFound) {
+		return echo.NewHTTPError(404, "owner not found").SetInternal(err)
+	}
+	return echo.ErrInternalServerError.SetInternal(err)
+}
+
 func FillWalletFromRequest(wallet entities.Wallet, request Request) (entities.Wallet, error) {
 	wallet.Balance = decimal.NewFromFloat(request.Balance)
+	if request.Currency != "" {
+		wallet.Currency = request.Currency
+	}
+	wallet.OwnerId = request.OwnerId
+	return wallet, nil
+}
+
+func FillWalletFromRequestV2(wallet entities.Wallet, request RequestV2) (entities.Wallet, error) {
+	if request.Balance != "" {
+		balance, err := entities.ParseAmount(request.Balance)
+		if err != nil {
+			return entities.Wallet{}, err
+		}
+		if balance.IsNegative() {
+			return entities.Wallet{}, fmt.Errorf("%w: balance cannot be negative", entities.ErrInvalidAmount)
+		}
+		wallet.Balance = balance
+	}
+	if request.Currency != "" {
+		wallet.Currency = request.Currency
+	}
+	wallet.OwnerId = request.OwnerId
 	return wallet, nil
 }
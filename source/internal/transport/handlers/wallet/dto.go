@@ -1,20 +1,392 @@
 package wallet
 
-import "TestProject/source/internal/entities"
+import (
+	"TestProject/source/internal/entities"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
 
 type Request struct {
-	Balance float64 `json:"balance" validate:"required,gte=0"`
+	Balance  float64 `json:"balance" validate:"required,gte=0"`
+	Currency string  `json:"currency" validate:"omitempty,iso4217"`
+	OwnerId  string  `json:"owner_id" validate:"omitempty,uuid"`
 }
 
+// Response.Balance equals LedgerBalance and is kept for older clients.
 type Response struct {
-	WalletId string  `json:"walletId"`
-	Balance  float64 `json:"balance"`
+	WalletId         string  `json:"walletId"`
+	OwnerId          string  `json:"ownerId,omitempty"`
+	Currency         string  `json:"currency"`
+	Balance          float64 `json:"balance"`
+	LedgerBalance    float64 `json:"ledgerBalance"`
+	AvailableBalance float64 `json:"availableBalance"`
+	OverdraftLimit   float64 `json:"overdraftLimit"`
+	RemainingCredit  float64 `json:"remainingCredit"`
+	Status           string  `json:"status"`
+	FreezeMode       string  `json:"freezeMode,omitempty"`
 }
 
 func EntityToResponse(wallet entities.Wallet) Response {
 	balance, _ := wallet.Balance.Float64()
+	available, _ := wallet.Available().Float64()
+	overdraftLimit, _ := wallet.OverdraftLimit.Float64()
+	remainingCredit, _ := wallet.RemainingCredit().Float64()
 	return Response{
-		WalletId: wallet.ID,
-		Balance:  balance,
+		WalletId:         wallet.ID,
+		OwnerId:          wallet.OwnerId,
+		Currency:         wallet.Currency,
+		Balance:          balance,
+		LedgerBalance:    balance,
+		AvailableBalance: available,
+		OverdraftLimit:   overdraftLimit,
+		RemainingCredit:  remainingCredit,
+		Status:           wallet.Status,
+		FreezeMode:       wallet.FreezeMode,
+	}
+}
+
+// RequestV2 carries the initial balance as a decimal string, e.g. "0.10".
+type RequestV2 struct {
+	Balance  string `json:"balance"`
+	Currency string `json:"currency" validate:"omitempty,iso4217"`
+	OwnerId  string `json:"owner_id" validate:"omitempty,uuid"`
+}
+
+type ResponseV2 struct {
+	WalletId         string `json:"walletId"`
+	OwnerId          string `json:"ownerId,omitempty"`
+	Currency         string `json:"currency"`
+	Balance          string `json:"balance"`
+	LedgerBalance    string `json:"ledgerBalance"`
+	AvailableBalance string `json:"availableBalance"`
+	OverdraftLimit   string `json:"overdraftLimit"`
+	RemainingCredit  string `json:"remainingCredit"`
+	Status           string `json:"status"`
+	FreezeMode       string `json:"freezeMode,omitempty"`
+}
+
+func EntityToResponseV2(wallet entities.Wallet) ResponseV2 {
+	balance := entities.FormatAmount(wallet.Balance, wallet.Currency)
+	return ResponseV2{
+		WalletId:         wallet.ID,
+		OwnerId:          wallet.OwnerId,
+		Currency:         wallet.Currency,
+		Balance:          balance,
+		LedgerBalance:    balance,
+		AvailableBalance: entities.FormatAmount(wallet.Available(), wallet.Currency),
+		OverdraftLimit:   entities.FormatAmount(wallet.OverdraftLimit, wallet.Currency),
+		RemainingCredit:  entities.FormatAmount(wallet.RemainingCredit(), wallet.Currency),
+		Status:           wallet.Status,
+		FreezeMode:       wallet.FreezeMode,
+	}
+}
+
+// BalanceAtResponse is the balance of a wallet at AsOf, served for
+// GET /wallets/:walletId?as_of=.
+type BalanceAtResponse struct {
+	WalletId string    `json:"walletId"`
+	Currency string    `json:"currency"`
+	Balance  float64   `json:"balance"`
+	AsOf     time.Time `json:"asOf"`
+}
+
+func HistoricalBalanceToResponse(balance entities.HistoricalBalance) BalanceAtResponse {
+	amount, _ := balance.Balance.Float64()
+	return BalanceAtResponse{
+		WalletId: balance.WalletId,
+		Currency: balance.Currency,
+		Balance:  amount,
+		AsOf:     balance.AsOf,
+	}
+}
+
+type BalanceAtResponseV2 struct {
+	WalletId string    `json:"walletId"`
+	Currency string    `json:"currency"`
+	Balance  string    `json:"balance"`
+	AsOf     time.Time `json:"asOf"`
+}
+
+func HistoricalBalanceToResponseV2(balance entities.HistoricalBalance) BalanceAtResponseV2 {
+	return BalanceAtResponseV2{
+		WalletId: balance.WalletId,
+		Currency: balance.Currency,
+		Balance:  entities.FormatAmount(balance.Balance, balance.Currency),
+		AsOf:     balance.AsOf,
+	}
+}
+
+// StatusRequest changes a wallet's status. Reason is a compliance reason
+// code, e.g. "KYC_REVIEW"; Mode is only used when freezing.
+type StatusRequest struct {
+	WalletId string `param:"walletId" validate:"required,uuid"`
+	Mode     string `json:"mode" validate:"omitempty,oneof=DEBIT ALL"`
+	Reason   string `json:"reason" validate:"required,max=64"`
+}
+
+type StatusResponse struct {
+	WalletId        string     `json:"walletId"`
+	Status          string     `json:"status"`
+	FreezeMode      string     `json:"freezeMode,omitempty"`
+	StatusReason    string     `json:"statusReason"`
+	StatusChangedAt *time.Time `json:"statusChangedAt"`
+}
+
+func EntityToStatusResponse(wallet entities.Wallet) StatusResponse {
+	return StatusResponse{
+		WalletId:        wallet.ID,
+		Status:          wallet.Status,
+		FreezeMode:      wallet.FreezeMode,
+		StatusReason:    wallet.StatusReason,
+		StatusChangedAt: wallet.StatusChangedAt,
+	}
+}
+
+// OverdraftRequest sets the overdraft limit as a decimal string; "0"
+// disables the overdraft.
+type OverdraftRequest struct {
+	WalletId string `param:"walletId" validate:"required,uuid"`
+	Limit    string `json:"limit" validate:"required"`
+}
+
+// LimitsRequest sets a wallet's velocity limits. Amounts are decimal
+// strings; omitted or zero values mean no limit.
+type LimitsRequest struct {
+	WalletId      string `param:"walletId" validate:"required,uuid"`
+	PerOperation  string `json:"perOperation"`
+	DailyAmount   string `json:"dailyAmount"`
+	DailyCount    int64  `json:"dailyCount" validate:"gte=0"`
+	MonthlyAmount string `json:"monthlyAmount"`
+	MonthlyCount  int64  `json:"monthlyCount" validate:"gte=0"`
+}
+
+type LimitsResponse struct {
+	WalletId      string `json:"walletId"`
+	PerOperation  string `json:"perOperation"`
+	DailyAmount   string `json:"dailyAmount"`
+	DailyCount    int64  `json:"dailyCount"`
+	MonthlyAmount string `json:"monthlyAmount"`
+	MonthlyCount  int64  `json:"monthlyCount"`
+}
+
+func FillLimitsFromRequest(request LimitsRequest) (entities.VelocityLimits, error) {
+	limits := entities.VelocityLimits{
+		WalletId:     request.WalletId,
+		DailyCount:   request.DailyCount,
+		MonthlyCount: request.MonthlyCount,
+	}
+
+	for _, field := range []struct {
+		value  string
+		target *decimal.Decimal
+	}{
+		{request.PerOperation, &limits.PerOperation},
+		{request.DailyAmount, &limits.DailyAmount},
+		{request.MonthlyAmount, &limits.MonthlyAmount},
+	} {
+		if field.value == "" {
+			continue
+		}
+		amount, err := entities.ParseAmount(field.value)
+		if err != nil {
+			return entities.VelocityLimits{}, err
+		}
+		*field.target = amount
+	}
+
+	return limits, nil
+}
+
+func LimitsToResponse(limits entities.VelocityLimits) LimitsResponse {
+	return LimitsResponse{
+		WalletId:      limits.WalletId,
+		PerOperation:  limits.PerOperation.String(),
+		DailyAmount:   limits.DailyAmount.String(),
+		DailyCount:    limits.DailyCount,
+		MonthlyAmount: limits.MonthlyAmount.String(),
+		MonthlyCount:  limits.MonthlyCount,
+	}
+}
+
+type ListTransactionsRequest struct {
+	WalletId      string `param:"walletId" validate:"required,uuid"`
+	OperationType string `query:"operation_type" validate:"omitempty,oneof=DEPOSIT WITHDRAW TRANSFER_IN TRANSFER_OUT CAPTURE DEPOSIT_REVERSAL WITHDRAW_REVERSAL INTEREST"`
+	From          string `query:"from" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	To            string `query:"to" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	MinAmount     string `query:"min_amount" validate:"omitempty,number"`
+	MaxAmount     string `query:"max_amount" validate:"omitempty,number"`
+	Cursor        string `query:"cursor"`
+	Limit         int    `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+// StatementRequest selects the statement period [from, to); to defaults to
+// now.
+type StatementRequest struct {
+	WalletId string `param:"walletId" validate:"required,uuid"`
+	From     string `query:"from" validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
+	To       string `query:"to" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+}
+
+type TransactionResponse struct {
+	TransactionId string    `json:"transaction_id"`
+	OperationType string    `json:"operation_type"`
+	Amount        float64   `json:"amount"`
+	Fee           float64   `json:"fee"`
+	BalanceBefore float64   `json:"balance_before"`
+	BalanceAfter  float64   `json:"balance_after"`
+	ReferenceId   string    `json:"reference_id,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type ListTransactionsResponse struct {
+	Transactions []TransactionResponse `json:"transactions"`
+	NextCursor   string                `json:"next_cursor,omitempty"`
+}
+
+func RequestToTransactionFilter(request ListTransactionsRequest) (entities.TransactionFilter, error) {
+	filter := entities.TransactionFilter{
+		WalletId:      request.WalletId,
+		OperationType: request.OperationType,
+		Limit:         request.Limit,
+	}
+
+	if request.From != "" {
+		from, err := time.Parse(time.RFC3339, request.From)
+		if err != nil {
+			return entities.TransactionFilter{}, fmt.Errorf("invalid from: %w", err)
+		}
+		filter.From = &from
+	}
+	if request.To != "" {
+		to, err := time.Parse(time.RFC3339, request.To)
+		if err != nil {
+			return entities.TransactionFilter{}, fmt.Errorf("invalid to: %w", err)
+		}
+		filter.To = &to
+	}
+	if request.MinAmount != "" {
+		minAmount, err := decimal.NewFromString(request.MinAmount)
+		if err != nil {
+			return entities.TransactionFilter{}, fmt.Errorf("invalid min_amount: %w", err)
+		}
+		filter.MinAmount = &minAmount
+	}
+	if request.MaxAmount != "" {
+		maxAmount, err := decimal.NewFromString(request.MaxAmount)
+		if err != nil {
+			return entities.TransactionFilter{}, fmt.Errorf("invalid max_amount: %w", err)
+		}
+		filter.MaxAmount = &maxAmount
+	}
+	if request.Cursor != "" {
+		cursor, err := DecodeCursor(request.Cursor)
+		if err != nil {
+			return entities.TransactionFilter{}, fmt.Errorf("invalid cursor: %w", err)
+		}
+		filter.Cursor = &cursor
+	}
+
+	return filter, nil
+}
+
+func PageToResponse(page entities.TransactionPage) ListTransactionsResponse {
+	response := ListTransactionsResponse{
+		Transactions: make([]TransactionResponse, 0, len(page.Transactions)),
+	}
+
+	for _, transaction := range page.Transactions {
+		amount, _ := transaction.Amount.Float64()
+		fee, _ := transaction.Fee.Float64()
+		balanceBefore, _ := transaction.BalanceBefore.Float64()
+		balanceAfter, _ := transaction.Balance.Float64()
+		response.Transactions = append(response.Transactions, TransactionResponse{
+			TransactionId: transaction.ID,
+			OperationType: transaction.OperationType,
+			Amount:        amount,
+			Fee:           fee,
+			BalanceBefore: balanceBefore,
+			BalanceAfter:  balanceAfter,
+			ReferenceId:   transaction.ReferenceId,
+			CreatedAt:     transaction.CreatedAt,
+		})
+	}
+
+	if page.NextCursor != nil {
+		response.NextCursor = EncodeCursor(*page.NextCursor)
+	}
+
+	return response
+}
+
+type TransactionResponseV2 struct {
+	TransactionId string    `json:"transaction_id"`
+	OperationType string    `json:"operation_type"`
+	Currency      string    `json:"currency"`
+	Amount        string    `json:"amount"`
+	Fee           string    `json:"fee"`
+	BalanceBefore string    `json:"balance_before"`
+	BalanceAfter  string    `json:"balance_after"`
+	ReferenceId   string    `json:"reference_id,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type ListTransactionsResponseV2 struct {
+	Transactions []TransactionResponseV2 `json:"transactions"`
+	NextCursor   string                  `json:"next_cursor,omitempty"`
+}
+
+func PageToResponseV2(page entities.TransactionPage) ListTransactionsResponseV2 {
+	response := ListTransactionsResponseV2{
+		Transactions: make([]TransactionResponseV2, 0, len(page.Transactions)),
+	}
+
+	for _, transaction := range page.Transactions {
+		response.Transactions = append(response.Transactions, TransactionResponseV2{
+			TransactionId: transaction.ID,
+			OperationType: transaction.OperationType,
+			Currency:      transaction.Currency,
+			Amount:        entities.FormatAmount(transaction.Amount, transaction.Currency),
+			Fee:           entities.FormatAmount(transaction.Fee, transaction.Currency),
+			BalanceBefore: entities.FormatAmount(transaction.BalanceBefore, transaction.Currency),
+			BalanceAfter:  entities.FormatAmount(transaction.Balance, transaction.Currency),
+			ReferenceId:   transaction.ReferenceId,
+			CreatedAt:     transaction.CreatedAt,
+		})
+	}
+
+	if page.NextCursor != nil {
+		response.NextCursor = EncodeCursor(*page.NextCursor)
 	}
+
+	return response
+}
+
+// EncodeCursor turns a cursor into an opaque URL-safe token.
+func EncodeCursor(cursor entities.TransactionCursor) string {
+	raw := cursor.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + cursor.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeCursor(token string) (entities.TransactionCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return entities.TransactionCursor{}, err
+	}
+
+	createdAt, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return entities.TransactionCursor{}, errors.New("malformed cursor")
+	}
+
+	parsed, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return entities.TransactionCursor{}, err
+	}
+
+	return entities.TransactionCursor{CreatedAt: parsed, ID: id}, nil
 }
@@ -1,11 +1,53 @@
 package wallet
 
 import (
+	"TestProject/source/internal/entities"
+	"errors"
 	"github.com/labstack/echo/v4"
 	"log/slog"
+	"time"
 )
 
 func (h *Handlers) GetBalance(c echo.Context) error {
+	if c.QueryParam("as_of") != "" {
+		balance, err := h.getBalanceAt(c)
+		if err != nil {
+			return err
+		}
+		return c.JSON(200, HistoricalBalanceToResponse(balance))
+	}
+
+	wallet, err := h.getWallet(c)
+	if err != nil {
+		return err
+	}
+
+	response := EntityToResponse(wallet)
+
+	return c.JSON(200, response)
+}
+
+// GetBalanceV2 is GetBalance with the balance sent as a decimal string.
+func (h *Handlers) GetBalanceV2(c echo.Context) error {
+	if c.QueryParam("as_of") != "" {
+		balance, err := h.getBalanceAt(c)
+		if err != nil {
+			return err
+		}
+		return c.JSON(200, HistoricalBalanceToResponseV2(balance))
+	}
+
+	wallet, err := h.getWallet(c)
+	if err != nil {
+		return err
+	}
+
+	response := EntityToResponseV2(wallet)
+
+	return c.JSON(200, response)
+}
+
+func (h *Handlers) getWallet(c echo.Context) (entities.Wallet, error) {
 	ctx := c.Request().Context()
 
 	logger := h.log
@@ -14,16 +56,41 @@ func (h *Handlers) GetBalance(c echo.Context) error {
 
 	if walletID == "" {
 		logger.ErrorContext(ctx, "error wallet id is not provided")
-		return echo.ErrBadRequest
+		return entities.Wallet{}, echo.ErrBadRequest
 	}
 
 	wallet, err := h.app.WalletRepo.GetByID(ctx, walletID)
 	if err != nil {
 		logger.ErrorContext(ctx, "wallet not found", slog.String("error", err.Error()))
-		return echo.ErrNotFound.SetInternal(err)
+		return entities.Wallet{}, echo.ErrNotFound.SetInternal(err)
 	}
 
-	response := EntityToResponse(wallet)
+	return wallet, nil
+}
 
-	return c.JSON(200, response)
+// getBalanceAt serves ?as_of=, an RFC 3339 timestamp.
+func (h *Handlers) getBalanceAt(c echo.Context) (entities.HistoricalBalance, error) {
+	ctx := c.Request().Context()
+
+	logger := h.log
+
+	asOf, err := time.Parse(time.RFC3339, c.QueryParam("as_of"))
+	if err != nil {
+		logger.ErrorContext(ctx, "error parsing as_of", slog.String("error", err.Error()))
+		return entities.HistoricalBalance{}, echo.NewHTTPError(400, "as_of must be an RFC 3339 timestamp").SetInternal(err)
+	}
+
+	balance, err := h.app.GetBalanceAt(ctx, c.Param("walletId"), asOf)
+	if err != nil {
+		logger.ErrorContext(ctx, "error getting balance as of", slog.String("error", err.Error()))
+		switch {
+		case errors.Is(err, entities.ErrWalletNotFound):
+			return entities.HistoricalBalance{}, echo.ErrNotFound.SetInternal(err)
+		case errors.Is(err, entities.ErrAsOfInFuture):
+			return entities.HistoricalBalance{}, echo.NewHTTPError(400, err.Error()).SetInternal(err)
+		}
+		return entities.HistoricalBalance{}, echo.ErrInternalServerError.SetInternal(err)
+	}
+
+	return balance, nil
 }
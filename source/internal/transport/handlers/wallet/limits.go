@@ -0,0 +1,80 @@
+package wallet
+
+import (
+	"TestProject/source/internal/entities"
+	"errors"
+	"log/slog"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (h *Handlers) GetLimits(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	limits, err := h.app.GetWalletLimits(ctx, c.Param("walletId"))
+	if err != nil {
+		h.log.ErrorContext(ctx, "error getting wallet limits", slog.String("error", err.Error()))
+		return limitsError(err)
+	}
+
+	return c.JSON(200, LimitsToResponse(limits))
+}
+
+func (h *Handlers) SetLimits(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	logger := h.log
+
+	var request LimitsRequest
+
+	err := c.Bind(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error binding request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	err = c.Validate(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error validating request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	limits, err := FillLimitsFromRequest(request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error when filling in the limits", slog.String("error", err.Error()))
+		return echo.NewHTTPError(400, err.Error()).SetInternal(err)
+	}
+
+	limits, err = h.app.SetWalletLimits(ctx, limits)
+	if err != nil {
+		logger.ErrorContext(ctx, "error setting wallet limits", slog.String("error", err.Error()))
+		return limitsError(err)
+	}
+
+	return c.JSON(200, LimitsToResponse(limits))
+}
+
+// ResetLimits makes the wallet use the default limits again.
+func (h *Handlers) ResetLimits(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	limits, err := h.app.ResetWalletLimits(ctx, c.Param("walletId"))
+	if err != nil {
+		h.log.ErrorContext(ctx, "error resetting wallet limits", slog.String("error", err.Error()))
+		return limitsError(err)
+	}
+
+	return c.JSON(200, LimitsToResponse(limits))
+}
+
+func limitsError(err error) *echo.HTTPError {
+	switch {
+	case errors.Is(err, entities.ErrWalletNotFound):
+		return echo.ErrNotFound.SetInternal(err)
+	case errors.Is(err, entities.ErrInvalidAmount),
+		errors.Is(err, entities.ErrInvalidAmountPrecision),
+		errors.Is(err, entities.ErrAmountOverflow):
+		return echo.NewHTTPError(400, err.Error()).SetInternal(err)
+	}
+	return echo.ErrInternalServerError.SetInternal(err)
+}
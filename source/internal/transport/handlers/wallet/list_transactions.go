@@ -0,0 +1,69 @@
+package wallet
+
+import (
+	"TestProject/source/internal/entities"
+	"errors"
+	"log/slog"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (h *Handlers) ListTransactions(c echo.Context) error {
+	page, err := h.listTransactions(c)
+	if err != nil {
+		return err
+	}
+
+	response := PageToResponse(page)
+
+	return c.JSON(200, response)
+}
+
+// ListTransactionsV2 is ListTransactions with amounts sent as decimal strings.
+func (h *Handlers) ListTransactionsV2(c echo.Context) error {
+	page, err := h.listTransactions(c)
+	if err != nil {
+		return err
+	}
+
+	response := PageToResponseV2(page)
+
+	return c.JSON(200, response)
+}
+
+func (h *Handlers) listTransactions(c echo.Context) (entities.TransactionPage, error) {
+	ctx := c.Request().Context()
+
+	logger := h.log
+
+	var request ListTransactionsRequest
+
+	err := c.Bind(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error binding request", slog.String("error", err.Error()))
+		return entities.TransactionPage{}, echo.ErrBadRequest.SetInternal(err)
+	}
+
+	err = c.Validate(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error validating request", slog.String("error", err.Error()))
+		return entities.TransactionPage{}, echo.ErrBadRequest.SetInternal(err)
+	}
+
+	filter, err := RequestToTransactionFilter(request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error building transaction filter", slog.String("error", err.Error()))
+		return entities.TransactionPage{}, echo.ErrBadRequest.SetInternal(err)
+	}
+
+	page, err := h.app.ListTransactions(ctx, filter)
+	if err != nil {
+		logger.ErrorContext(ctx, "error listing transactions", slog.String("error", err.Error()))
+		if errors.Is(err, entities.ErrWalletNotFound) {
+			return entities.TransactionPage{}, echo.ErrNotFound.SetInternal(err)
+		}
+		return entities.TransactionPage{}, echo.ErrInternalServerError.SetInternal(err)
+	}
+
+	return page, nil
+}
@@ -2,6 +2,7 @@ package wallet
 
 import (
 	"TestProject/source/internal/application"
+	"context"
 	"go.uber.org/fx"
 	"log/slog"
 )
@@ -23,11 +24,25 @@ var Module = fx.Module(
 type Handlers struct {
 	log *slog.Logger
 	app *application.Application
+
+	// streams lives as long as the server; wallet streams end when it is
+	// cancelled, since Shutdown does not cancel the requests it waits for.
+	streams     context.Context
+	stopStreams context.CancelFunc
 }
 
 func NewHandlers(log *slog.Logger, app *application.Application) *Handlers {
+	streams, stopStreams := context.WithCancel(context.Background())
 	return &Handlers{
-		log: log,
-		app: app,
+		log:         log,
+		app:         app,
+		streams:     streams,
+		stopStreams: stopStreams,
 	}
 }
+
+// StopStreams ends the open wallet streams and every stream opened later.
+// The server calls it before Shutdown, which would otherwise wait for them.
+func (h *Handlers) StopStreams() {
+	h.stopStreams()
+}
@@ -0,0 +1,63 @@
+package wallet
+
+import (
+	"TestProject/source/internal/entities"
+	"errors"
+	"log/slog"
+
+	"github.com/labstack/echo/v4"
+)
+
+func (h *Handlers) SetOverdraftLimit(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	logger := h.log
+
+	var request OverdraftRequest
+
+	err := c.Bind(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error binding request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	err = c.Validate(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error validating request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	limit, err := entities.ParseAmount(request.Limit)
+	if err != nil {
+		logger.ErrorContext(ctx, "error parsing overdraft limit", slog.String("error", err.Error()))
+		return echo.NewHTTPError(400, err.Error()).SetInternal(err)
+	}
+
+	wallet, err := h.app.SetOverdraftLimit(ctx, request.WalletId, limit)
+	if err != nil {
+		logger.ErrorContext(ctx, "error setting overdraft limit", slog.String("error", err.Error()))
+		return overdraftError(err)
+	}
+
+	logger.InfoContext(ctx, "overdraft limit changed",
+		slog.String("wallet_id", wallet.ID),
+		slog.String("limit", wallet.OverdraftLimit.String()),
+	)
+
+	return c.JSON(200, EntityToResponseV2(wallet))
+}
+
+func overdraftError(err error) *echo.HTTPError {
+	switch {
+	case errors.Is(err, entities.ErrWalletNotFound):
+		return echo.ErrNotFound.SetInternal(err)
+	case errors.Is(err, entities.ErrInvalidAmount),
+		errors.Is(err, entities.ErrInvalidAmountPrecision),
+		errors.Is(err, entities.ErrAmountOverflow):
+		return echo.NewHTTPError(400, err.Error()).SetInternal(err)
+	case errors.Is(err, entities.ErrWalletClosed),
+		errors.Is(err, entities.ErrOverdraftInUse):
+		return echo.NewHTTPError(409, err.Error()).SetInternal(err)
+	}
+	return echo.ErrInternalServerError.SetInternal(err)
+}
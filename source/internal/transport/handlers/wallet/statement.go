@@ -0,0 +1,127 @@
+package wallet
+
+import (
+	"TestProject/source/internal/application"
+	"TestProject/source/internal/entities"
+	"errors"
+	"log/slog"
+	"mime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	mimeTextCSV = "text/csv"
+	mimeNDJSON  = "application/x-ndjson"
+)
+
+// GetStatement streams the statement of a wallet for [from, to) as JSON,
+// CSV or NDJSON, chosen by the Accept header.
+func (h *Handlers) GetStatement(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	logger := h.log
+
+	var request StatementRequest
+
+	err := c.Bind(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error binding request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	err = c.Validate(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error validating request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	from, err := time.Parse(time.RFC3339, request.From)
+	if err != nil {
+		return echo.NewHTTPError(400, "from must be an RFC 3339 timestamp").SetInternal(err)
+	}
+	to := time.Now().UTC()
+	if request.To != "" {
+		to, err = time.Parse(time.RFC3339, request.To)
+		if err != nil {
+			return echo.NewHTTPError(400, "to must be an RFC 3339 timestamp").SetInternal(err)
+		}
+	}
+
+	mediaType, ok := negotiateStatement(c.Request().Header.Get(echo.HeaderAccept))
+	if !ok {
+		return echo.NewHTTPError(406, "statements are available as application/json, text/csv and application/x-ndjson")
+	}
+
+	var writer application.StatementWriter
+	switch mediaType {
+	case mimeTextCSV:
+		writer = newCSVStatementWriter(c.Response())
+	case mimeNDJSON:
+		writer = newNDJSONStatementWriter(c.Response())
+	default:
+		writer = newJSONStatementWriter(c.Response())
+	}
+
+	err = h.app.WriteStatement(ctx, request.WalletId, from, to, writer)
+	if err != nil {
+		logger.ErrorContext(ctx, "error writing statement", slog.String("error", err.Error()))
+		switch {
+		case c.Response().Committed:
+			// The status is already sent; the client sees a truncated body.
+			return nil
+		case errors.Is(err, entities.ErrWalletNotFound):
+			return echo.ErrNotFound.SetInternal(err)
+		case errors.Is(err, entities.ErrInvalidStatementPeriod),
+			errors.Is(err, entities.ErrAsOfInFuture):
+			return echo.NewHTTPError(400, err.Error()).SetInternal(err)
+		}
+		return echo.ErrInternalServerError.SetInternal(err)
+	}
+
+	return nil
+}
+
+// negotiateStatement picks the statement format with the highest q-value in
+// the Accept header, the earliest one on ties. No header means JSON.
+func negotiateStatement(accept string) (string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return echo.MIMEApplicationJSON, true
+	}
+
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := params["q"]; ok {
+			q, err = strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+		}
+
+		var format string
+		switch mediaType {
+		case mimeTextCSV:
+			format = mimeTextCSV
+		case mimeNDJSON, "application/ndjson":
+			format = mimeNDJSON
+		case echo.MIMEApplicationJSON, "application/*", "*/*":
+			format = echo.MIMEApplicationJSON
+		default:
+			continue
+		}
+		if q > bestQ {
+			best, bestQ = format, q
+		}
+	}
+
+	return best, best != ""
+}
@@ -0,0 +1,289 @@
+package wallet
+
+import (
+	"TestProject/source/internal/entities"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// statementFlushEvery is how many lines are buffered before they are
+// flushed to the client.
+const statementFlushEvery = 100
+
+// StatementLineResponse is one line of a JSON or NDJSON statement.
+type StatementLineResponse struct {
+	TransactionId  string    `json:"transaction_id"`
+	OperationType  string    `json:"operation_type"`
+	Amount         string    `json:"amount"`
+	Fee            string    `json:"fee"`
+	Change         string    `json:"change"`
+	RunningBalance string    `json:"running_balance"`
+	ReferenceId    string    `json:"reference_id,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type StatementTotalResponse struct {
+	Count  int64  `json:"count"`
+	Amount string `json:"amount"`
+}
+
+func StatementLineToResponse(line entities.StatementLine, currency string) StatementLineResponse {
+	return StatementLineResponse{
+		TransactionId:  line.Transaction.ID,
+		OperationType:  line.Transaction.OperationType,
+		Amount:         entities.FormatAmount(line.Transaction.Amount, currency),
+		Fee:            entities.FormatAmount(line.Transaction.Fee, currency),
+		Change:         entities.FormatAmount(line.Change, currency),
+		RunningBalance: entities.FormatAmount(line.RunningBalance, currency),
+		ReferenceId:    line.Transaction.ReferenceId,
+		CreatedAt:      line.Transaction.CreatedAt,
+	}
+}
+
+func StatementTotalsToResponse(statement entities.Statement) map[string]StatementTotalResponse {
+	totals := make(map[string]StatementTotalResponse, len(statement.Totals))
+	for operationType, total := range statement.Totals {
+		totals[operationType] = StatementTotalResponse{
+			Count:  total.Count,
+			Amount: entities.FormatAmount(total.Amount, statement.Currency),
+		}
+	}
+	return totals
+}
+
+// jsonStatementWriter writes one JSON document. The header fields go first
+// and the lines are streamed as an array; the closing balance and totals
+// follow the array since they are only known at the end.
+type jsonStatementWriter struct {
+	response *echo.Response
+	currency string
+	lines    int
+}
+
+func newJSONStatementWriter(response *echo.Response) *jsonStatementWriter {
+	return &jsonStatementWriter{response: response}
+}
+
+func (w *jsonStatementWriter) Begin(statement entities.Statement) error {
+	w.currency = statement.Currency
+	w.response.Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	w.response.WriteHeader(200)
+
+	header, err := json.Marshal(struct {
+		WalletId       string    `json:"wallet_id"`
+		Currency       string    `json:"currency"`
+		From           time.Time `json:"from"`
+		To             time.Time `json:"to"`
+		OpeningBalance string    `json:"opening_balance"`
+	}{
+		WalletId:       statement.WalletId,
+		Currency:       statement.Currency,
+		From:           statement.From,
+		To:             statement.To,
+		OpeningBalance: entities.FormatAmount(statement.OpeningBalance, statement.Currency),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Reopen the object to append the lines array.
+	_, err = fmt.Fprintf(w.response, `%s,"transactions":[`, header[:len(header)-1])
+	return err
+}
+
+func (w *jsonStatementWriter) Line(line entities.StatementLine) error {
+	data, err := json.Marshal(StatementLineToResponse(line, w.currency))
+	if err != nil {
+		return err
+	}
+	if w.lines > 0 {
+		data = append([]byte{','}, data...)
+	}
+	_, err = w.response.Write(data)
+	if err != nil {
+		return err
+	}
+
+	w.lines++
+	if w.lines%statementFlushEvery == 0 {
+		w.response.Flush()
+	}
+	return nil
+}
+
+func (w *jsonStatementWriter) End(statement entities.Statement) error {
+	totals, err := json.Marshal(StatementTotalsToResponse(statement))
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w.response, `],"closing_balance":%q,"totals":%s}`+"\n",
+		entities.FormatAmount(statement.ClosingBalance, statement.Currency), totals)
+	return err
+}
+
+// ndjsonStatementWriter writes one JSON object per line: an "opening"
+// record, a "transaction" record per operation and a closing "summary".
+type ndjsonStatementWriter struct {
+	response *echo.Response
+	encoder  *json.Encoder
+	currency string
+	lines    int
+}
+
+func newNDJSONStatementWriter(response *echo.Response) *ndjsonStatementWriter {
+	return &ndjsonStatementWriter{response: response, encoder: json.NewEncoder(response)}
+}
+
+func (w *ndjsonStatementWriter) Begin(statement entities.Statement) error {
+	w.currency = statement.Currency
+	w.response.Header().Set(echo.HeaderContentType, mimeNDJSON)
+	w.response.WriteHeader(200)
+
+	err := w.encoder.Encode(struct {
+		Type           string    `json:"type"`
+		WalletId       string    `json:"wallet_id"`
+		Currency       string    `json:"currency"`
+		From           time.Time `json:"from"`
+		To             time.Time `json:"to"`
+		OpeningBalance string    `json:"opening_balance"`
+	}{
+		Type:           "opening",
+		WalletId:       statement.WalletId,
+		Currency:       statement.Currency,
+		From:           statement.From,
+		To:             statement.To,
+		OpeningBalance: entities.FormatAmount(statement.OpeningBalance, statement.Currency),
+	})
+	if err != nil {
+		return err
+	}
+
+	w.response.Flush()
+	return nil
+}
+
+func (w *ndjsonStatementWriter) Line(line entities.StatementLine) error {
+	err := w.encoder.Encode(struct {
+		Type string `json:"type"`
+		StatementLineResponse
+	}{
+		Type:                  "transaction",
+		StatementLineResponse: StatementLineToResponse(line, w.currency),
+	})
+	if err != nil {
+		return err
+	}
+
+	w.lines++
+	if w.lines%statementFlushEvery == 0 {
+		w.response.Flush()
+	}
+	return nil
+}
+
+func (w *ndjsonStatementWriter) End(statement entities.Statement) error {
+	return w.encoder.Encode(struct {
+		Type           string                            `json:"type"`
+		ClosingBalance string                            `json:"closing_balance"`
+		Totals         map[string]StatementTotalResponse `json:"totals"`
+	}{
+		Type:           "summary",
+		ClosingBalance: entities.FormatAmount(statement.ClosingBalance, statement.Currency),
+		Totals:         StatementTotalsToResponse(statement),
+	})
+}
+
+// csvStatementWriter writes one row per operation between an
+// OPENING_BALANCE row and the TOTAL_<type> and CLOSING_BALANCE rows. Only
+// the TOTAL rows fill the count column.
+type csvStatementWriter struct {
+	response *echo.Response
+	csv      *csv.Writer
+	currency string
+	lines    int
+}
+
+var statementCSVHeader = []string{
+	"created_at", "transaction_id", "operation_type", "amount", "fee", "change", "running_balance", "reference_id", "count",
+}
+
+func newCSVStatementWriter(response *echo.Response) *csvStatementWriter {
+	return &csvStatementWriter{response: response, csv: csv.NewWriter(response)}
+}
+
+func (w *csvStatementWriter) Begin(statement entities.Statement) error {
+	w.currency = statement.Currency
+	w.response.Header().Set(echo.HeaderContentType, mimeTextCSV+"; charset=utf-8")
+	w.response.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="statement-%s-%s.csv"`,
+		statement.WalletId, statement.From.Format(time.DateOnly)))
+	w.response.WriteHeader(200)
+
+	err := w.csv.Write(statementCSVHeader)
+	if err != nil {
+		return err
+	}
+	return w.csv.Write([]string{
+		statement.From.Format(time.RFC3339Nano), "", "OPENING_BALANCE", "", "", "",
+		entities.FormatAmount(statement.OpeningBalance, statement.Currency), "", "",
+	})
+}
+
+func (w *csvStatementWriter) Line(line entities.StatementLine) error {
+	err := w.csv.Write([]string{
+		line.Transaction.CreatedAt.Format(time.RFC3339Nano),
+		line.Transaction.ID,
+		line.Transaction.OperationType,
+		entities.FormatAmount(line.Transaction.Amount, w.currency),
+		entities.FormatAmount(line.Transaction.Fee, w.currency),
+		entities.FormatAmount(line.Change, w.currency),
+		entities.FormatAmount(line.RunningBalance, w.currency),
+		line.Transaction.ReferenceId,
+		"",
+	})
+	if err != nil {
+		return err
+	}
+
+	w.lines++
+	if w.lines%statementFlushEvery == 0 {
+		w.csv.Flush()
+		w.response.Flush()
+	}
+	return w.csv.Error()
+}
+
+func (w *csvStatementWriter) End(statement entities.Statement) error {
+	operationTypes := make([]string, 0, len(statement.Totals))
+	for operationType := range statement.Totals {
+		operationTypes = append(operationTypes, operationType)
+	}
+	sort.Strings(operationTypes)
+
+	for _, operationType := range operationTypes {
+		total := statement.Totals[operationType]
+		err := w.csv.Write([]string{
+			"", "", "TOTAL_" + operationType,
+			entities.FormatAmount(total.Amount, statement.Currency), "", "", "", "", fmt.Sprint(total.Count),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	err := w.csv.Write([]string{
+		statement.To.Format(time.RFC3339Nano), "", "CLOSING_BALANCE", "", "", "",
+		entities.FormatAmount(statement.ClosingBalance, statement.Currency), "", "",
+	})
+	if err != nil {
+		return err
+	}
+
+	w.csv.Flush()
+	return w.csv.Error()
+}
@@ -0,0 +1,83 @@
+package wallet
+
+import (
+	"TestProject/source/internal/entities"
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/labstack/echo/v4"
+)
+
+// FreezeWallet freezes a wallet in the requested mode: DEBIT blocks outgoing
+// money only, ALL blocks every balance change.
+func (h *Handlers) FreezeWallet(c echo.Context) error {
+	return h.changeStatus(c, func(ctx context.Context, request StatusRequest) (entities.Wallet, error) {
+		if request.Mode == "" {
+			return entities.Wallet{}, echo.NewHTTPError(400, "mode is required")
+		}
+		return h.app.FreezeWallet(ctx, request.WalletId, request.Mode, request.Reason)
+	})
+}
+
+func (h *Handlers) UnfreezeWallet(c echo.Context) error {
+	return h.changeStatus(c, func(ctx context.Context, request StatusRequest) (entities.Wallet, error) {
+		return h.app.UnfreezeWallet(ctx, request.WalletId, request.Reason)
+	})
+}
+
+func (h *Handlers) CloseWallet(c echo.Context) error {
+	return h.changeStatus(c, func(ctx context.Context, request StatusRequest) (entities.Wallet, error) {
+		return h.app.CloseWallet(ctx, request.WalletId, request.Reason)
+	})
+}
+
+func (h *Handlers) changeStatus(c echo.Context, change func(context.Context, StatusRequest) (entities.Wallet, error)) error {
+	ctx := c.Request().Context()
+
+	logger := h.log
+
+	var request StatusRequest
+
+	err := c.Bind(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error binding request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	err = c.Validate(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error validating request", slog.String("error", err.Error()))
+		return echo.NewHTTPError(400, err.Error()).SetInternal(err)
+	}
+
+	wallet, err := change(ctx, request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error changing wallet status", slog.String("error", err.Error()))
+		return statusError(err)
+	}
+
+	logger.InfoContext(ctx, "wallet status changed",
+		slog.String("wallet_id", wallet.ID),
+		slog.String("status", wallet.Status),
+		slog.String("freeze_mode", wallet.FreezeMode),
+		slog.String("reason", wallet.StatusReason),
+	)
+
+	return c.JSON(200, EntityToStatusResponse(wallet))
+}
+
+func statusError(err error) error {
+	var httpErr *echo.HTTPError
+	switch {
+	case errors.As(err, &httpErr):
+		return httpErr
+	case errors.Is(err, entities.ErrWalletNotFound):
+		return echo.ErrNotFound.SetInternal(err)
+	case errors.Is(err, entities.ErrWalletClosed),
+		errors.Is(err, entities.ErrWalletNotEmpty),
+		errors.Is(err, entities.ErrInvalidStatusTransition):
+		return echo.NewHTTPError(409, err.Error()).SetInternal(err)
+	}
+	return echo.ErrInternalServerError.SetInternal(err)
+}
@@ -0,0 +1,206 @@
+package wallet
+
+import (
+	"TestProject/source/internal/application"
+	"TestProject/source/internal/entities"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/net/websocket"
+)
+
+const mimeEventStream = "text/event-stream"
+
+// streamSnapshot is the type of the message that carries the whole wallet
+// at the start of a stream that is not resumed.
+const streamSnapshot = "snapshot"
+
+// StreamWallet pushes balance changes of a wallet as they are committed,
+// over Server-Sent Events or, for upgrade requests, over WebSocket. The
+// stream resumes after the Last-Event-ID header or the last_event_id query
+// parameter, which WebSocket clients in browsers have to use.
+func (h *Handlers) StreamWallet(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	logger := h.log
+
+	wallet, err := h.getWallet(c)
+	if err != nil {
+		return err
+	}
+
+	lastEventID, err := parseLastEventID(c)
+	if err != nil {
+		logger.ErrorContext(ctx, "error parsing last event id", slog.String("error", err.Error()))
+		return echo.NewHTTPError(400, "last event id must be a non-negative integer").SetInternal(err)
+	}
+
+	if strings.EqualFold(c.Request().Header.Get(echo.HeaderUpgrade), "websocket") {
+		h.streamWebSocket(c, wallet.ID, lastEventID)
+		return nil
+	}
+
+	response := c.Response()
+	response.Header().Set(echo.HeaderContentType, mimeEventStream)
+	response.Header().Set(echo.HeaderCacheControl, "no-cache")
+	// Keeps nginx from buffering the stream.
+	response.Header().Set("X-Accel-Buffering", "no")
+	response.WriteHeader(200)
+	response.Flush()
+
+	ctx, cancel := h.streamContext(ctx)
+	defer cancel()
+
+	err = h.app.StreamWallet(ctx, wallet.ID, lastEventID, &sseStreamWriter{response: response})
+	if err != nil {
+		// The status is already sent; an EventSource reconnects on its own
+		// with the last event ID it received.
+		logStreamEnd(ctx, logger, err)
+	}
+	return nil
+}
+
+func (h *Handlers) streamWebSocket(c echo.Context, walletID string, lastEventID *int64) {
+	logger := h.log
+
+	// websocket.Server without a Handshake accepts any Origin; the stream
+	// only reads data that GetBalance serves as well.
+	server := websocket.Server{Handler: func(conn *websocket.Conn) {
+		ctx, cancel := h.streamContext(c.Request().Context())
+		defer cancel()
+
+		// Messages from the client are ignored; reading them is how a
+		// closed connection is noticed.
+		go func() {
+			var message []byte
+			for websocket.Message.Receive(conn, &message) == nil {
+			}
+			cancel()
+		}()
+
+		err := h.app.StreamWallet(ctx, walletID, lastEventID, &webSocketStreamWriter{conn: conn})
+		if err != nil {
+			logStreamEnd(ctx, logger, err)
+		}
+	}}
+	server.ServeHTTP(c.Response(), c.Request())
+}
+
+// streamContext returns a context of the request that is also cancelled
+// when the server stops streaming.
+func (h *Handlers) streamContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(h.streams, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
+
+func parseLastEventID(c echo.Context) (*int64, error) {
+	value := c.Request().Header.Get("Last-Event-ID")
+	if value == "" {
+		value = c.QueryParam("last_event_id")
+	}
+	if value == "" {
+		return nil, nil
+	}
+
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	if id < 0 {
+		return nil, fmt.Errorf("negative last event id %d", id)
+	}
+	return &id, nil
+}
+
+func logStreamEnd(ctx context.Context, logger *slog.Logger, err error) {
+	if errors.Is(err, entities.ErrStreamInterrupted) {
+		logger.InfoContext(ctx, "wallet stream interrupted")
+		return
+	}
+	logger.ErrorContext(ctx, "error streaming wallet", slog.String("error", err.Error()))
+}
+
+// StreamMessage is a WebSocket message. Type is "snapshot", with the wallet
+// as in GET /api/v2/wallets/:walletId, an event type with the event, or
+// "heartbeat" without an ID.
+type StreamMessage struct {
+	Id   string `json:"id,omitempty"`
+	Type string `json:"type"`
+	Data any    `json:"data,omitempty"`
+}
+
+// sseStreamWriter writes each message as a Server-Sent Event with the
+// outbox sequence as its ID.
+type sseStreamWriter struct {
+	response *echo.Response
+}
+
+var _ application.WalletStreamWriter = (*sseStreamWriter)(nil)
+
+func (w *sseStreamWriter) Snapshot(wallet entities.Wallet, sequence int64) error {
+	return w.write(sequence, streamSnapshot, EntityToResponseV2(wallet))
+}
+
+func (w *sseStreamWriter) Event(event entities.WalletEvent) error {
+	return w.write(event.Sequence, event.Type, event)
+}
+
+func (w *sseStreamWriter) Heartbeat() error {
+	_, err := io.WriteString(w.response, ": heartbeat\n\n")
+	if err != nil {
+		return err
+	}
+	w.response.Flush()
+	return nil
+}
+
+func (w *sseStreamWriter) write(id int64, eventType string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w.response, "id: %d\nevent: %s\ndata: %s\n\n", id, eventType, payload)
+	if err != nil {
+		return err
+	}
+	w.response.Flush()
+	return nil
+}
+
+type webSocketStreamWriter struct {
+	conn *websocket.Conn
+}
+
+var _ application.WalletStreamWriter = (*webSocketStreamWriter)(nil)
+
+func (w *webSocketStreamWriter) Snapshot(wallet entities.Wallet, sequence int64) error {
+	return websocket.JSON.Send(w.conn, StreamMessage{
+		Id:   strconv.FormatInt(sequence, 10),
+		Type: streamSnapshot,
+		Data: EntityToResponseV2(wallet),
+	})
+}
+
+func (w *webSocketStreamWriter) Event(event entities.WalletEvent) error {
+	return websocket.JSON.Send(w.conn, StreamMessage{
+		Id:   strconv.FormatInt(event.Sequence, 10),
+		Type: event.Type,
+		Data: event,
+	})
+}
+
+func (w *webSocketStreamWriter) Heartbeat() error {
+	return websocket.JSON.Send(w.conn, StreamMessage{Type: "heartbeat"})
+}
@@ -0,0 +1,118 @@
+package wallet
+
+import (
+	"TestProject/source/internal/application"
+	"TestProject/source/internal/entities"
+	"bufio"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+)
+
+// The mocks embed the application interfaces, so calls other than those a
+// stream makes panic.
+
+type mockTransactor struct {
+	application.Transactor
+}
+
+func (mockTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type mockWalletRepo struct {
+	application.WalletRepo
+	wallet entities.Wallet
+}
+
+func (m mockWalletRepo) GetByID(ctx context.Context, walletID string) (entities.Wallet, error) {
+	if walletID != m.wallet.ID {
+		return entities.Wallet{}, entities.ErrWalletNotFound
+	}
+	return m.wallet, nil
+}
+
+func (m mockWalletRepo) GetByIDForShare(ctx context.Context, walletID string) (entities.Wallet, error) {
+	return m.GetByID(ctx, walletID)
+}
+
+type mockOutboxRepo struct {
+	application.OutboxRepo
+}
+
+func (mockOutboxRepo) LastSequence(ctx context.Context, walletID string) (int64, error) {
+	return 0, nil
+}
+
+// mockSubscriber hands out subscriptions that never receive an event.
+type mockSubscriber struct{}
+
+func (mockSubscriber) Subscribe(walletID string) (<-chan entities.WalletEvent, func()) {
+	return make(chan entities.WalletEvent), func() {}
+}
+
+func newStreamServer(t *testing.T) (*Handlers, *httptest.Server, string) {
+	t.Helper()
+
+	wallet := entities.NewWallet()
+	handlers := NewHandlers(slog.New(slog.DiscardHandler), &application.Application{
+		Transactor: mockTransactor{},
+		WalletRepo: mockWalletRepo{wallet: wallet},
+		OutboxRepo: mockOutboxRepo{},
+		Subscriber: mockSubscriber{},
+	})
+
+	e := echo.New()
+	e.GET("/api/v2/wallets/:walletId/stream", handlers.StreamWallet)
+	server := httptest.NewServer(e)
+	t.Cleanup(server.Close)
+
+	return handlers, server, "/api/v2/wallets/" + wallet.ID + "/stream"
+}
+
+func TestHandlers_StreamWallet_StopStreamsEndsSSE(t *testing.T) {
+	handlers, server, path := newStreamServer(t)
+
+	resp, err := http.Get(server.URL + path)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, mimeEventStream, resp.Header.Get(echo.HeaderContentType))
+
+	reader := bufio.NewReader(resp.Body)
+	line, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "id: 0\n", line)
+
+	// Shutdown does not cancel the stream's request; without StopStreams it
+	// would wait for the deadline.
+	handlers.StopStreams()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, server.Config.Shutdown(ctx))
+}
+
+func TestHandlers_StreamWallet_StopStreamsEndsWebSocket(t *testing.T) {
+	handlers, server, path := newStreamServer(t)
+
+	conn, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http")+path, "", server.URL)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	var message StreamMessage
+	require.NoError(t, websocket.JSON.Receive(conn, &message))
+	assert.Equal(t, streamSnapshot, message.Type)
+
+	handlers.StopStreams()
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	err = websocket.JSON.Receive(conn, &message)
+	assert.ErrorContains(t, err, "EOF", "expected the server to close the stream")
+}
@@ -0,0 +1,118 @@
+package webhooks
+
+import (
+	"TestProject/source/internal/entities"
+	"encoding/json"
+	"time"
+)
+
+// CreateRequest.WalletId is set by the wallet route; webhooks created
+// without it receive the events of every wallet.
+type CreateRequest struct {
+	WalletId string `param:"walletId" validate:"omitempty,uuid"`
+	URL      string `json:"url" validate:"required,http_url,max=2048"`
+}
+
+type ListRequest struct {
+	WalletId string `query:"wallet_id" validate:"omitempty,uuid"`
+}
+
+type ListDeliveriesRequest struct {
+	WebhookId string `query:"webhook_id" validate:"omitempty,uuid"`
+	Status    string `query:"status" validate:"omitempty,oneof=PENDING SUCCEEDED DEAD"`
+	Limit     int    `query:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+type Response struct {
+	WebhookId string    `json:"webhook_id"`
+	WalletId  string    `json:"wallet_id,omitempty"`
+	URL       string    `json:"url"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateResponse is the only response that carries the signing secret.
+type CreateResponse struct {
+	Response
+	Secret string `json:"secret"`
+}
+
+type ListResponse struct {
+	Webhooks []Response `json:"webhooks"`
+}
+
+type DeliveryResponse struct {
+	DeliveryId     string          `json:"delivery_id"`
+	WebhookId      string          `json:"webhook_id"`
+	EventId        string          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	WalletId       string          `json:"wallet_id"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+	LastStatusCode int             `json:"last_status_code,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+	Payload        json.RawMessage `json:"payload"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+type DeliveriesResponse struct {
+	Deliveries []DeliveryResponse `json:"deliveries"`
+}
+
+func EntityToResponse(webhook entities.Webhook) Response {
+	return Response{
+		WebhookId: webhook.ID,
+		WalletId:  webhook.WalletId,
+		URL:       webhook.URL,
+		CreatedAt: webhook.CreatedAt,
+	}
+}
+
+func EntityToCreateResponse(webhook entities.Webhook) CreateResponse {
+	return CreateResponse{
+		Response: EntityToResponse(webhook),
+		Secret:   webhook.Secret,
+	}
+}
+
+func WebhooksToResponse(webhooks []entities.Webhook) ListResponse {
+	response := ListResponse{Webhooks: make([]Response, 0, len(webhooks))}
+	for _, webhook := range webhooks {
+		response.Webhooks = append(response.Webhooks, EntityToResponse(webhook))
+	}
+	return response
+}
+
+// DeliveryToResponse omits NextAttemptAt once the delivery is no longer
+// pending.
+func DeliveryToResponse(delivery entities.WebhookDelivery) DeliveryResponse {
+	response := DeliveryResponse{
+		DeliveryId:     delivery.ID,
+		WebhookId:      delivery.WebhookId,
+		EventId:        delivery.EventId,
+		EventType:      delivery.EventType,
+		WalletId:       delivery.WalletId,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		DeliveredAt:    delivery.DeliveredAt,
+		Payload:        delivery.Payload,
+		CreatedAt:      delivery.CreatedAt,
+		UpdatedAt:      delivery.UpdatedAt,
+	}
+	if delivery.Status == entities.DeliveryPending {
+		response.NextAttemptAt = &delivery.NextAttemptAt
+	}
+	return response
+}
+
+func DeliveriesToResponse(deliveries []entities.WebhookDelivery) DeliveriesResponse {
+	response := DeliveriesResponse{Deliveries: make([]DeliveryResponse, 0, len(deliveries))}
+	for _, delivery := range deliveries {
+		response.Deliveries = append(response.Deliveries, DeliveryToResponse(delivery))
+	}
+	return response
+}
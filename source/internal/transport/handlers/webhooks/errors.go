@@ -0,0 +1,22 @@
+package webhooks
+
+import (
+	"TestProject/source/internal/entities"
+	"errors"
+
+	"github.com/labstack/echo/v4"
+)
+
+func domainError(err error) *echo.HTTPError {
+	switch {
+	case errors.Is(err, entities.ErrWebhookNotFound),
+		errors.Is(err, entities.ErrDeliveryNotFound),
+		errors.Is(err, entities.ErrWalletNotFound):
+		return echo.ErrNotFound.SetInternal(err)
+	case errors.Is(err, entities.ErrDeliveryPending):
+		return echo.NewHTTPError(409, err.Error()).SetInternal(err)
+	case errors.Is(err, entities.ErrWebhookURLNotAllowed):
+		return echo.NewHTTPError(400, err.Error()).SetInternal(err)
+	}
+	return echo.ErrInternalServerError.SetInternal(err)
+}
@@ -0,0 +1,148 @@
+package webhooks
+
+import (
+	"TestProject/source/internal/entities"
+	"log/slog"
+
+	"github.com/labstack/echo/v4"
+)
+
+const defaultListLimit = 50
+
+// CreateWebhook registers a webhook for the wallet in the path, or for
+// every wallet on the global route.
+func (h *Handlers) CreateWebhook(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := h.log
+
+	var request CreateRequest
+
+	err := c.Bind(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error binding request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	err = c.Validate(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error validating request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	webhook := entities.NewWebhook()
+	webhook.WalletId = request.WalletId
+	webhook.URL = request.URL
+
+	webhook, err = h.app.CreateWebhook(ctx, webhook)
+	if err != nil {
+		logger.ErrorContext(ctx, "error creating webhook", slog.String("error", err.Error()))
+		return domainError(err)
+	}
+
+	return c.JSON(201, EntityToCreateResponse(webhook))
+}
+
+func (h *Handlers) GetWebhook(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := h.log
+
+	webhook, err := h.app.GetWebhook(ctx, c.Param("webhookId"))
+	if err != nil {
+		logger.ErrorContext(ctx, "error getting webhook", slog.String("error", err.Error()))
+		return domainError(err)
+	}
+
+	return c.JSON(200, EntityToResponse(webhook))
+}
+
+func (h *Handlers) ListWebhooks(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := h.log
+
+	var request ListRequest
+
+	err := c.Bind(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error binding request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	err = c.Validate(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error validating request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	webhooks, err := h.app.ListWebhooks(ctx, request.WalletId)
+	if err != nil {
+		logger.ErrorContext(ctx, "error listing webhooks", slog.String("error", err.Error()))
+		return domainError(err)
+	}
+
+	return c.JSON(200, WebhooksToResponse(webhooks))
+}
+
+func (h *Handlers) DeleteWebhook(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := h.log
+
+	err := h.app.DeleteWebhook(ctx, c.Param("webhookId"))
+	if err != nil {
+		logger.ErrorContext(ctx, "error deleting webhook", slog.String("error", err.Error()))
+		return domainError(err)
+	}
+
+	return c.NoContent(204)
+}
+
+// ListDeliveries returns the latest deliveries; status=DEAD gives the
+// dead-letter list.
+func (h *Handlers) ListDeliveries(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := h.log
+
+	var request ListDeliveriesRequest
+
+	err := c.Bind(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error binding request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	err = c.Validate(&request)
+	if err != nil {
+		logger.ErrorContext(ctx, "error validating request", slog.String("error", err.Error()))
+		return echo.ErrBadRequest.SetInternal(err)
+	}
+
+	limit := request.Limit
+	if limit == 0 {
+		limit = defaultListLimit
+	}
+
+	deliveries, err := h.app.ListWebhookDeliveries(ctx, entities.WebhookDeliveryFilter{
+		WebhookId: request.WebhookId,
+		Status:    request.Status,
+		Limit:     limit,
+	})
+	if err != nil {
+		logger.ErrorContext(ctx, "error listing webhook deliveries", slog.String("error", err.Error()))
+		return domainError(err)
+	}
+
+	return c.JSON(200, DeliveriesToResponse(deliveries))
+}
+
+// ReplayDelivery answers 409 while the delivery is still pending.
+func (h *Handlers) ReplayDelivery(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := h.log
+
+	delivery, err := h.app.ReplayWebhookDelivery(ctx, c.Param("deliveryId"))
+	if err != nil {
+		logger.ErrorContext(ctx, "error replaying webhook delivery", slog.String("error", err.Error()))
+		return domainError(err)
+	}
+
+	return c.JSON(200, DeliveryToResponse(delivery))
+}
@@ -0,0 +1,113 @@
+package transport
+
+import (
+	"TestProject/source/internal/entities"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	HeaderIdempotencyKey     = "Idempotency-Key"
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// Idempotency makes a POST safe to retry when the client sends an
+// Idempotency-Key header. The handler runs inside the same database
+// transaction that stores its response, and its output is buffered until
+// that transaction commits.
+func (h *Handlers) Idempotency(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		key := c.Request().Header.Get(HeaderIdempotencyKey)
+		if key == "" {
+			return next(c)
+		}
+
+		ctx := c.Request().Context()
+		logger := h.logger
+
+		if len(key) > maxIdempotencyKeyLength {
+			return echo.NewHTTPError(http.StatusBadRequest, "idempotency key is too long")
+		}
+
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			logger.ErrorContext(ctx, "error reading request body", slog.String("error", err.Error()))
+			return echo.ErrBadRequest.SetInternal(err)
+		}
+
+		requestHash := hashRequest(c.Request().Method, c.Request().URL.Path, body)
+
+		response := c.Response()
+		original := response.Writer
+
+		record, replayed, err := h.app.ExecuteIdempotent(ctx, key, requestHash, func(ctx context.Context) (int, []byte, error) {
+			recorder := &responseRecorder{header: original.Header()}
+			response.Writer = recorder
+			defer func() { response.Writer = original }()
+
+			request := c.Request().WithContext(ctx)
+			request.Body = io.NopCloser(bytes.NewReader(body))
+			c.SetRequest(request)
+
+			err := next(c)
+			if err != nil {
+				return 0, nil, err
+			}
+			return response.Status, recorder.body.Bytes(), nil
+		})
+		if err != nil {
+			// Nothing reached the client yet, let the error handler write.
+			response.Committed = false
+			response.Size = 0
+
+			if errors.Is(err, entities.ErrIdempotencyKeyReused) {
+				logger.ErrorContext(ctx, "idempotency key reused", slog.String("key", key))
+				return echo.NewHTTPError(http.StatusConflict, "idempotency key reused with a different request").SetInternal(err)
+			}
+			return err
+		}
+
+		if replayed {
+			response.Header().Set(HeaderIdempotentReplayed, "true")
+			return c.JSONBlob(record.StatusCode, record.Response)
+		}
+
+		original.WriteHeader(record.StatusCode)
+		_, err = original.Write(record.Response)
+		return err
+	}
+}
+
+func hashRequest(method, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder keeps the handler output in memory. Headers go straight to
+// the real response since nothing is sent before WriteHeader.
+type responseRecorder struct {
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	return r.body.Write(b)
+}
+
+func (r *responseRecorder) WriteHeader(int) {}
@@ -2,8 +2,15 @@ package transport
 
 import (
 	"TestProject/source/config"
+	"TestProject/source/internal/transport/handlers/accounts"
+	"TestProject/source/internal/transport/handlers/customers"
+	"TestProject/source/internal/transport/handlers/fees"
+	"TestProject/source/internal/transport/handlers/holds"
+	"TestProject/source/internal/transport/handlers/interest"
+	"TestProject/source/internal/transport/handlers/schedules"
 	"TestProject/source/internal/transport/handlers/transactions"
 	"TestProject/source/internal/transport/handlers/wallet"
+	"TestProject/source/internal/transport/handlers/webhooks"
 	"context"
 	"fmt"
 	"log/slog"
@@ -19,6 +26,13 @@ var Module = fx.Module(
 	moduleName,
 	transactions.Module,
 	wallet.Module,
+	holds.Module,
+	accounts.Module,
+	schedules.Module,
+	fees.Module,
+	interest.Module,
+	customers.Module,
+	webhooks.Module,
 	fx.Provide(
 		NewHandlers,
 		NewEchoServer,
@@ -56,6 +70,9 @@ func NewEchoServer(
 			return nil
 		},
 		OnStop: func(ctx context.Context) error {
+			// Shutdown waits for open requests without cancelling them, so
+			// wallet streams have to be ended first.
+			handlers.wallet.StopStreams()
 			return echoRouter.Shutdown(ctx)
 		},
 	})
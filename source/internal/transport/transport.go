@@ -1,8 +1,17 @@
 package transport
 
 import (
+	"TestProject/source/internal/application"
+	"TestProject/source/internal/transport/handlers/accounts"
+	"TestProject/source/internal/transport/handlers/customers"
+	"TestProject/source/internal/transport/handlers/fees"
+	"TestProject/source/internal/transport/handlers/holds"
+	"TestProject/source/internal/transport/handlers/interest"
+	"TestProject/source/internal/transport/handlers/schedules"
 	"TestProject/source/internal/transport/handlers/transactions"
 	"TestProject/source/internal/transport/handlers/wallet"
+	"TestProject/source/internal/transport/handlers/webhooks"
+	"expvar"
 	"log/slog"
 
 	"github.com/labstack/echo/v4"
@@ -11,19 +20,43 @@ import (
 
 type Handlers struct {
 	logger       *slog.Logger
+	app          *application.Application
 	transactions *transactions.Handlers
 	wallet       *wallet.Handlers
+	holds        *holds.Handlers
+	accounts     *accounts.Handlers
+	schedules    *schedules.Handlers
+	fees         *fees.Handlers
+	interest     *interest.Handlers
+	customers    *customers.Handlers
+	webhooks     *webhooks.Handlers
 }
 
 func NewHandlers(
 	logger *slog.Logger,
+	app *application.Application,
 	transactionHandlers *transactions.Handlers,
 	walletHandlers *wallet.Handlers,
+	holdHandlers *holds.Handlers,
+	accountHandlers *accounts.Handlers,
+	scheduleHandlers *schedules.Handlers,
+	feeHandlers *fees.Handlers,
+	interestHandlers *interest.Handlers,
+	customerHandlers *customers.Handlers,
+	webhookHandlers *webhooks.Handlers,
 ) *Handlers {
 	return &Handlers{
 		logger:       logger,
+		app:          app,
 		transactions: transactionHandlers,
 		wallet:       walletHandlers,
+		holds:        holdHandlers,
+		accounts:     accountHandlers,
+		schedules:    scheduleHandlers,
+		fees:         feeHandlers,
+		interest:     interestHandlers,
+		customers:    customerHandlers,
+		webhooks:     webhookHandlers,
 	}
 }
 
@@ -31,11 +64,141 @@ func (h *Handlers) Registry(e *echo.Echo) {
 	e.Use(middleware.Recover())
 	e.Use(middleware.Logger())
 
+	e.GET("/debug/vars", echo.WrapHandler(expvar.Handler()))
+
 	api := e.Group("/api/v1")
 
-	api.POST("/wallets", h.wallet.CreateWallet)
+	api.POST("/wallets", h.wallet.CreateWallet, h.Idempotency)
 
 	api.GET("/wallets/:walletId", h.wallet.GetBalance)
 
-	api.POST("/wallet", h.transactions.CreateTransaction)
+	api.GET("/wallets/:walletId/transactions", h.wallet.ListTransactions)
+
+	api.GET("/wallets/:walletId/stream", h.wallet.StreamWallet)
+
+	api.POST("/wallet", h.transactions.CreateTransaction, h.Idempotency)
+
+	api.POST("/transfers", h.transactions.CreateTransfer, h.Idempotency)
+
+	api.POST("/wallets/batch-transactions", h.transactions.CreateBatch, h.Idempotency)
+
+	api.POST("/customers", h.customers.CreateCustomer, h.Idempotency)
+
+	api.GET("/customers", h.customers.ListCustomers)
+
+	api.GET("/customers/:id", h.customers.GetCustomer)
+
+	api.PUT("/customers/:id", h.customers.UpdateCustomer)
+
+	api.DELETE("/customers/:id", h.customers.DeleteCustomer)
+
+	api.GET("/customers/:id/wallets", h.customers.GetCustomerWallets)
+
+	// v2 sends amounts as decimal strings instead of floats.
+	apiV2 := e.Group("/api/v2")
+
+	apiV2.POST("/wallets", h.wallet.CreateWalletV2, h.Idempotency)
+
+	apiV2.GET("/wallets/:walletId", h.wallet.GetBalanceV2)
+
+	apiV2.GET("/wallets/:walletId/transactions", h.wallet.ListTransactionsV2)
+
+	apiV2.GET("/wallets/:walletId/statement", h.wallet.GetStatement)
+
+	apiV2.POST("/wallet", h.transactions.CreateTransactionV2, h.Idempotency)
+
+	apiV2.POST("/transfers", h.transactions.CreateTransferV2, h.Idempotency)
+
+	apiV2.POST("/wallets/batch-transactions", h.transactions.CreateBatchV2, h.Idempotency)
+
+	apiV2.POST("/transactions/:transactionId/reversals", h.transactions.CreateReversal, h.Idempotency)
+
+	apiV2.POST("/wallets/:walletId/holds", h.holds.CreateHold, h.Idempotency)
+
+	apiV2.GET("/holds/:holdId", h.holds.GetHold)
+
+	apiV2.POST("/holds/:holdId/capture", h.holds.CaptureHold, h.Idempotency)
+
+	apiV2.POST("/holds/:holdId/void", h.holds.VoidHold)
+
+	apiV2.POST("/wallets/:walletId/schedules", h.schedules.CreateSchedule, h.Idempotency)
+
+	apiV2.GET("/schedules/:scheduleId", h.schedules.GetSchedule)
+
+	apiV2.GET("/schedules/:scheduleId/runs", h.schedules.ListScheduleRuns)
+
+	apiV2.POST("/schedules/:scheduleId/pause", h.schedules.PauseSchedule)
+
+	apiV2.POST("/schedules/:scheduleId/resume", h.schedules.ResumeSchedule)
+
+	apiV2.POST("/schedules/:scheduleId/cancel", h.schedules.CancelSchedule)
+
+	apiV2.GET("/wallets/:walletId/interest", h.interest.GetPlan)
+
+	apiV2.GET("/wallets/:walletId/interest/payouts", h.interest.ListPayouts)
+
+	apiV2.POST("/customers", h.customers.CreateCustomer, h.Idempotency)
+
+	apiV2.GET("/customers", h.customers.ListCustomers)
+
+	apiV2.GET("/customers/:id", h.customers.GetCustomer)
+
+	apiV2.PUT("/customers/:id", h.customers.UpdateCustomer)
+
+	apiV2.DELETE("/customers/:id", h.customers.DeleteCustomer)
+
+	apiV2.GET("/customers/:id/wallets", h.customers.GetCustomerWalletsV2)
+
+	apiV2.POST("/wallets/:walletId/webhooks", h.webhooks.CreateWebhook, h.Idempotency)
+
+	apiV2.POST("/webhooks", h.webhooks.CreateWebhook, h.Idempotency)
+
+	apiV2.GET("/webhooks", h.webhooks.ListWebhooks)
+
+	apiV2.GET("/webhooks/:webhookId", h.webhooks.GetWebhook)
+
+	apiV2.DELETE("/webhooks/:webhookId", h.webhooks.DeleteWebhook)
+
+	apiV2.GET("/webhook-deliveries", h.webhooks.ListDeliveries)
+
+	apiV2.POST("/webhook-deliveries/:deliveryId/replay", h.webhooks.ReplayDelivery)
+
+	apiV2.GET("/system-accounts", h.accounts.GetSystemAccounts)
+
+	// Back-office routes; they are expected to be exposed to admins only.
+	admin := apiV2.Group("/admin")
+
+	admin.POST("/wallets/:walletId/freeze", h.wallet.FreezeWallet)
+
+	admin.POST("/wallets/:walletId/unfreeze", h.wallet.UnfreezeWallet)
+
+	admin.POST("/wallets/:walletId/close", h.wallet.CloseWallet)
+
+	admin.PUT("/wallets/:walletId/overdraft", h.wallet.SetOverdraftLimit)
+
+	admin.GET("/wallets/:walletId/limits", h.wallet.GetLimits)
+
+	admin.PUT("/wallets/:walletId/limits", h.wallet.SetLimits)
+
+	admin.DELETE("/wallets/:walletId/limits", h.wallet.ResetLimits)
+
+	admin.GET("/fees", h.fees.ListDefaultFees)
+
+	admin.PUT("/fees/:operationType/:currency", h.fees.SetFee)
+
+	admin.DELETE("/fees/:operationType/:currency", h.fees.DeleteFee)
+
+	admin.GET("/wallets/:walletId/fees", h.fees.ListWalletFees)
+
+	admin.PUT("/wallets/:walletId/fees/:operationType", h.fees.SetFee)
+
+	admin.DELETE("/wallets/:walletId/fees/:operationType", h.fees.DeleteFee)
+
+	admin.PUT("/wallets/:walletId/interest", h.interest.SetPlan)
+
+	admin.DELETE("/wallets/:walletId/interest", h.interest.DeletePlan)
+
+	admin.POST("/interest/run", h.interest.RunInterest)
+
+	admin.POST("/transactions/:transactionId/reversals", h.transactions.CreateAdminReversal, h.Idempotency)
 }
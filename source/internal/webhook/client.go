@@ -0,0 +1,143 @@
+package webhook
+
+import (
+	"TestProject/source/config"
+	"TestProject/source/internal/application"
+	"TestProject/source/internal/entities"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+
+	"go.uber.org/fx"
+)
+
+const moduleName = "webhook"
+
+// maxDrainedBody is how much of a response body is read so that the
+// connection can be reused; the body itself is not used.
+const maxDrainedBody = 64 << 10
+
+// blockedPrefixes are the ranges outside the public internet that netip
+// has no predicate for.
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+}
+
+var Module = fx.Module(
+	moduleName,
+	fx.Provide(
+		New,
+	),
+)
+
+func New(conf config.WebhookConfig) application.WebhookClient {
+	return NewClient(&http.Client{Timeout: conf.Timeout}, conf.AllowPrivateNetworks)
+}
+
+// Client posts webhook deliveries over HTTP. Redirects are not followed:
+// receivers are expected to register the final URL. Unless private networks
+// are allowed, it only connects to public addresses, checked when a webhook
+// is registered and again on every dial, after DNS resolution, so that a
+// name later pointed at an internal address gets nowhere.
+type Client struct {
+	http                 *http.Client
+	allowPrivateNetworks bool
+}
+
+func NewClient(client *http.Client, allowPrivateNetworks bool) *Client {
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	if !allowPrivateNetworks {
+		dialer := &net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+			Control: func(network, address string, _ syscall.RawConn) error {
+				addrPort, err := netip.ParseAddrPort(address)
+				if err != nil {
+					return fmt.Errorf("%w: %s", entities.ErrWebhookURLNotAllowed, address)
+				}
+				return checkAddr(addrPort.Addr())
+			},
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		// A proxy would be the address checked instead of the receiver.
+		transport.Proxy = nil
+		transport.DialContext = dialer.DialContext
+		client.Transport = transport
+	}
+	return &Client{http: client, allowPrivateNetworks: allowPrivateNetworks}
+}
+
+// CheckURL accepts http and https URLs whose host resolves only to public
+// addresses.
+func (c *Client) CheckURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return fmt.Errorf("%w: %s is not an http or https url", entities.ErrWebhookURLNotAllowed, rawURL)
+	}
+	if c.allowPrivateNetworks {
+		return nil
+	}
+
+	host := u.Hostname()
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return checkAddr(addr)
+	}
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("%w: cannot resolve %s: %v", entities.ErrWebhookURLNotAllowed, host, err)
+	}
+	for _, addr := range addrs {
+		err = checkAddr(addr)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Client) Post(ctx context.Context, url string, header http.Header, body []byte) (int, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("error creating request: %w", err)
+	}
+	request.Header = header
+
+	response, err := c.http.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, maxDrainedBody))
+
+	return response.StatusCode, nil
+}
+
+// checkAddr rejects loopback, private, link-local, multicast and other
+// non-public addresses, including IPv4 addresses mapped into IPv6.
+func checkAddr(addr netip.Addr) error {
+	addr = addr.Unmap()
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return fmt.Errorf("%w: %s is not a public address", entities.ErrWebhookURLNotAllowed, addr)
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return fmt.Errorf("%w: %s is not a public address", entities.ErrWebhookURLNotAllowed, addr)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package webhook
+
+import (
+	"TestProject/source/internal/entities"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_CheckURL(t *testing.T) {
+	client := NewClient(&http.Client{}, false)
+
+	for _, rawURL := range []string{
+		"http://127.0.0.1/hook",
+		"http://localhost:8080/hook",
+		"http://[::1]/hook",
+		"http://10.0.0.5/hook",
+		"http://172.16.0.1/hook",
+		"http://192.168.1.10/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://[fe80::1]/hook",
+		"http://[fd00::1]/hook",
+		"http://[::ffff:127.0.0.1]/hook",
+		"http://0.0.0.0/hook",
+		"http://100.64.0.1/hook",
+		"ftp://93.184.216.34/hook",
+	} {
+		err := client.CheckURL(context.Background(), rawURL)
+		assert.ErrorIs(t, err, entities.ErrWebhookURLNotAllowed, rawURL)
+	}
+
+	assert.NoError(t, client.CheckURL(context.Background(), "https://93.184.216.34/hook"))
+	assert.NoError(t, NewClient(&http.Client{}, true).CheckURL(context.Background(), "http://127.0.0.1/hook"))
+}
+
+func TestClient_Post_ChecksAddressOnDial(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	// The check on registration is passed by, e.g., a name that resolved to
+	// a public address then; the dial still refuses the loopback server.
+	_, err := NewClient(&http.Client{}, false).Post(context.Background(), server.URL, http.Header{}, []byte("{}"))
+	require.ErrorIs(t, err, entities.ErrWebhookURLNotAllowed)
+
+	status, err := NewClient(&http.Client{}, true).Post(context.Background(), server.URL, http.Header{}, []byte("{}"))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, status)
+}